@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"gaia-mcp-go/cmd/sse"
 	"gaia-mcp-go/cmd/stdio"
 	"gaia-mcp-go/version"
 	"log/slog"
@@ -34,11 +35,7 @@ func Execute() {
 	// Signal handling
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
-	go func() {
-		<-c
-		slog.Info("Received signal to terminate", "signal", <-c)
-		cancel()
-	}()
+	go cancelOnSignal(c, cancel)
 
 	if err := rootCmd.ExecuteContext(ctx); err != nil {
 		slog.Error("Failed to execute root command", "error", err)
@@ -46,8 +43,18 @@ func Execute() {
 	}
 }
 
+// cancelOnSignal cancels the root context on the first signal received, so
+// subcommands can shut down gracefully
+func cancelOnSignal(signals <-chan os.Signal, cancel context.CancelFunc) {
+	sig := <-signals
+	slog.Info("Received signal to terminate", "signal", sig)
+	cancel()
+}
+
 func init() {
 	rootCmd.SetVersionTemplate(`{{printf "%s" .Version}}`)
 	// Add subcommands
 	rootCmd.AddCommand(stdio.StdioCmd)
+	rootCmd.AddCommand(sse.SseCmd)
+	rootCmd.AddCommand(versionCmd)
 }
@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestCancelOnSignal(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	signals := make(chan os.Signal, 1)
+	go cancelOnSignal(signals, cancel)
+
+	// A single signal is enough to start the shutdown
+	signals <- syscall.SIGTERM
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("context was not cancelled after the first signal")
+	}
+}
@@ -0,0 +1,203 @@
+package sse
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"gaia-mcp-go/cmd/stdio"
+	"gaia-mcp-go/internal/api"
+	"gaia-mcp-go/internal/interfaces"
+	"gaia-mcp-go/internal/tools"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/spf13/cobra"
+)
+
+var (
+	SseCmd = &cobra.Command{
+		Use:   "sse",
+		Short: "Run the Gaia MCP server over HTTP with server-sent events",
+		Long:  `Run the Gaia MCP server over HTTP. Clients connect to /sse and send messages to /message.`,
+		Run:   runSse,
+	}
+
+	// DefaultAddr is the address the server listens on when --addr is not set.
+	// It only accepts local connections.
+	DefaultAddr = "127.0.0.1:8080"
+
+	// AuthTokenEnv names the environment variable holding the bearer token
+	// clients must send, read when --auth-token is not set
+	AuthTokenEnv = "GAIA_MCP_AUTH_TOKEN"
+
+	// ErrUnauthenticatedRemote is returned when the server would accept remote
+	// connections without requiring a bearer token
+	ErrUnauthenticatedRemote = errors.New("listening on a non-loopback address requires --auth-token (or " + AuthTokenEnv + "), or --allow-unauthenticated to serve without one")
+
+	// shutdownTimeout bounds how long open connections may delay shutdown
+	shutdownTimeout = 10 * time.Second
+)
+
+func init() {
+	addFlags(SseCmd)
+}
+
+// addFlags registers the SSE server flags on the command, on top of the flags
+// shared with the stdio transport
+func addFlags(cmd *cobra.Command) {
+	stdio.AddFlags(cmd)
+	cmd.Flags().String("addr", DefaultAddr, "The address to listen on, e.g. 127.0.0.1:8080, or :8080 to accept remote connections")
+	cmd.Flags().String("auth-token", "", "Bearer token clients must send in the Authorization header (overrides the "+AuthTokenEnv+" environment variable)")
+	cmd.Flags().Bool("allow-unauthenticated", false, "Serve a non-loopback address without a bearer token")
+}
+
+func runSse(cmd *cobra.Command, args []string) {
+	cfg, err := stdio.LoadConfig(cmd, os.LookupEnv)
+	if err != nil {
+		slog.Error("Invalid configuration", "error", err)
+		os.Exit(1)
+	}
+
+	addr, _ := cmd.Flags().GetString("addr")
+	authToken, err := resolveAuthToken(cmd, addr, os.LookupEnv)
+	if err != nil {
+		slog.Error("Invalid configuration", "error", err)
+		os.Exit(1)
+	}
+
+	// HTTP clients can carry larger payloads, so images are returned at full resolution
+	tracker, gaiaTools, ok := stdio.Prepare(cmd, &cfg, tools.ServerModeHTTP)
+	if !ok {
+		return
+	}
+	s := newServer(gaiaTools, cmd.Version, cfg.MaxConcurrentTools)
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		slog.Error("Failed to listen", "addr", addr, "error", err)
+		os.Exit(1)
+	}
+
+	slog.Info("Serving MCP over SSE", "addr", listener.Addr().String(), "auth", authToken != "")
+	if err := serveUntilShutdown(cmd.Context(), listener, s, authToken, tracker); err != nil {
+		slog.Error("Failed to serve SSE", "error", err)
+	}
+}
+
+// serveUntilShutdown serves until ctx is done, then cancels the tasks still
+// pending in tracker (nil unless --cancel-on-shutdown is set)
+func serveUntilShutdown(ctx context.Context, listener net.Listener, s *server.MCPServer, authToken string, tracker *api.TaskTracker) error {
+	err := serve(ctx, listener, s, authToken)
+
+	if tracker != nil {
+		stdio.CancelPendingTasks(context.Background(), tracker)
+	}
+	return err
+}
+
+// resolveAuthToken returns the bearer token clients must send. A non-empty
+// --auth-token flag takes precedence over the GAIA_MCP_AUTH_TOKEN environment variable.
+// It returns ErrUnauthenticatedRemote when there is no token, addr accepts
+// remote connections and --allow-unauthenticated is not set.
+func resolveAuthToken(cmd *cobra.Command, addr string, lookupEnv func(string) (string, bool)) (string, error) {
+	token, err := cmd.Flags().GetString("auth-token")
+	if err != nil {
+		return "", fmt.Errorf("failed to get auth-token flag: %w", err)
+	}
+	token = strings.TrimSpace(token)
+	if token == "" {
+		if value, ok := lookupEnv(AuthTokenEnv); ok {
+			token = strings.TrimSpace(value)
+		}
+	}
+	if token != "" {
+		return token, nil
+	}
+
+	allowUnauthenticated, err := cmd.Flags().GetBool("allow-unauthenticated")
+	if err != nil {
+		return "", fmt.Errorf("failed to get allow-unauthenticated flag: %w", err)
+	}
+	if !allowUnauthenticated && !isLoopback(addr) {
+		return "", ErrUnauthenticatedRemote
+	}
+	return "", nil
+}
+
+// isLoopback reports whether addr only accepts connections from this machine.
+// An empty host, such as in ":8080", listens on every interface.
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil || host == "" {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// newServer creates the MCP server with the given tools, registered the same
+// way as for the stdio transport
+func newServer(gaiaTools []interfaces.GaiaTool, version string, maxConcurrent int) *server.MCPServer {
+	s := server.NewMCPServer(
+		stdio.ServerName,
+		version,
+		server.WithToolCapabilities(false),
+	)
+
+	tools.RegisterTools(s, gaiaTools, maxConcurrent)
+
+	return s
+}
+
+// serve serves the MCP server over SSE on the listener until ctx is done,
+// then shuts down gracefully. When authToken is set, requests without it as
+// their bearer token are rejected.
+func serve(ctx context.Context, listener net.Listener, s *server.MCPServer, authToken string) error {
+	httpServer := &http.Server{}
+	sseServer := server.NewSSEServer(s, server.WithHTTPServer(httpServer))
+	httpServer.Handler = requireBearerToken(authToken, sseServer)
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- httpServer.Serve(listener)
+	}()
+
+	select {
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		return sseServer.Shutdown(shutdownCtx)
+	}
+}
+
+// requireBearerToken rejects requests whose Authorization header does not
+// carry the token with 401 Unauthorized. An empty token lets every request through.
+func requireBearerToken(token string, next http.Handler) http.Handler {
+	if token == "" {
+		return next
+	}
+
+	expected := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="gaia-mcp"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,270 @@
+package sse
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"gaia-mcp-go/cmd/stdio"
+	"gaia-mcp-go/internal/api"
+	"gaia-mcp-go/internal/testutil"
+	"gaia-mcp-go/internal/tools"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readEvent reads the next server-sent event and returns its name and data
+func readEvent(t *testing.T, reader *bufio.Reader) (string, string) {
+	t.Helper()
+
+	var event, data string
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+
+		line = strings.TrimRight(line, "\r\n")
+		switch {
+		case line == "" && data != "":
+			return event, data
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data = strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		}
+	}
+}
+
+// postMessage sends a JSON-RPC message to the session's message endpoint
+func postMessage(t *testing.T, baseUrl, endpoint string, message map[string]any) {
+	t.Helper()
+
+	body, err := json.Marshal(message)
+	require.NoError(t, err)
+
+	res, err := http.Post(baseUrl+endpoint, "application/json", strings.NewReader(string(body)))
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusAccepted, res.StatusCode)
+}
+
+func TestServe_ListsTools(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		gaiaTools := tools.NewGaiaTools(api.NewGaiaApi(api.GaiaApiConfig{ApiKey: "test-key"}), tools.ToolDefaults{ServerMode: tools.ServerModeHTTP}, tools.ToolDeps{})
+		served <- serve(ctx, listener, newServer(gaiaTools, "test", 0), "")
+	}()
+
+	baseUrl := "http://" + listener.Addr().String()
+
+	// The SSE stream announces the endpoint to post messages to
+	sseReq, err := http.NewRequestWithContext(ctx, http.MethodGet, baseUrl+"/sse", nil)
+	require.NoError(t, err)
+	stream, err := http.DefaultClient.Do(sseReq)
+	require.NoError(t, err)
+	defer stream.Body.Close()
+	require.Equal(t, http.StatusOK, stream.StatusCode)
+
+	reader := bufio.NewReader(stream.Body)
+	event, endpoint := readEvent(t, reader)
+	require.Equal(t, "endpoint", event)
+	require.Contains(t, endpoint, "sessionId=")
+
+	postMessage(t, baseUrl, endpoint, map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "initialize",
+		"params": map[string]any{
+			"protocolVersion": "2024-11-05",
+			"clientInfo":      map[string]any{"name": "test", "version": "1.0"},
+		},
+	})
+	_, data := readEvent(t, reader)
+	assert.Contains(t, data, `"id":1`)
+
+	postMessage(t, baseUrl, endpoint, map[string]any{
+		"jsonrpc": "2.0",
+		"id":      2,
+		"method":  "tools/list",
+	})
+	_, data = readEvent(t, reader)
+
+	var response struct {
+		Id     int `json:"id"`
+		Result struct {
+			Tools []struct {
+				Name string `json:"name"`
+			} `json:"tools"`
+		} `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(data), &response))
+	assert.Equal(t, 2, response.Id)
+
+	var names []string
+	for _, tool := range response.Result.Tools {
+		names = append(names, tool.Name)
+	}
+	expected := tools.NewGaiaTools(nil, tools.ToolDefaults{}, tools.ToolDeps{})
+	assert.Len(t, names, len(expected))
+	assert.Contains(t, names, "generate_image")
+	assert.Contains(t, names, "inpaint")
+
+	// Cancelling the context shuts the server down
+	cancel()
+	select {
+	case err := <-served:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not shut down")
+	}
+}
+
+func TestServeUntilShutdown_CancelsPendingTasks(t *testing.T) {
+	gaia := testutil.NewTestServer()
+	defer gaia.Close()
+
+	gaia.AddResponse("POST", "/api/recipe/agi-tasks/create-task", testutil.MockResponse{
+		StatusCode: 200,
+		Body:       api.ImageGeneratedResponse{Success: true, TaskId: "task-1"},
+	})
+	gaia.AddResponse("POST", "/api/recipe/agi-tasks/task-1/cancel", testutil.MockResponse{StatusCode: 200})
+
+	tracker := api.NewTaskTracker(api.NewGaiaApi(api.GaiaApiConfig{BaseUrl: gaia.URL, ApiKey: "test-key"}))
+	_, err := tracker.GenerateImages(context.Background(), api.GenerateImagesRequest{})
+	require.NoError(t, err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		gaiaTools := tools.NewGaiaTools(tracker, tools.ToolDefaults{ServerMode: tools.ServerModeHTTP}, tools.ToolDeps{})
+		served <- serveUntilShutdown(ctx, listener, newServer(gaiaTools, "test", 0), "", tracker)
+	}()
+
+	// Cancelling the context stops the server and cancels the pending task
+	cancel()
+	select {
+	case err := <-served:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not shut down")
+	}
+
+	assert.Len(t, gaia.Requests("POST", "/api/recipe/agi-tasks/task-1/cancel"), 1)
+	assert.Empty(t, tracker.PendingTasks())
+}
+
+func TestServe_RequiresBearerToken(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go serve(ctx, listener, newServer(nil, "test", 0), "secret")
+
+	baseUrl := "http://" + listener.Addr().String()
+
+	tests := []struct {
+		name          string
+		authorization string
+		expected      int
+	}{
+		{"Missing token", "", http.StatusUnauthorized},
+		{"Wrong token", "Bearer nope", http.StatusUnauthorized},
+		{"Token without scheme", "secret", http.StatusUnauthorized},
+		{"Valid token", "Bearer secret", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqCtx, reqCancel := context.WithCancel(ctx)
+			defer reqCancel()
+
+			req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, baseUrl+"/sse", nil)
+			require.NoError(t, err)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+
+			res, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer res.Body.Close()
+			assert.Equal(t, tt.expected, res.StatusCode)
+		})
+	}
+}
+
+func TestResolveAuthToken(t *testing.T) {
+	tests := []struct {
+		name                 string
+		addr                 string
+		flag                 string
+		env                  map[string]string
+		allowUnauthenticated bool
+		expected             string
+		expectedError        error
+	}{
+		{name: "Loopback without token", addr: "127.0.0.1:8080"},
+		{name: "Localhost without token", addr: "localhost:8080"},
+		{name: "IPv6 loopback without token", addr: "[::1]:8080"},
+		{name: "All interfaces without token", addr: ":8080", expectedError: ErrUnauthenticatedRemote},
+		{name: "Remote address without token", addr: "0.0.0.0:8080", expectedError: ErrUnauthenticatedRemote},
+		{name: "Remote address with flag", addr: ":8080", flag: "flag-token", expected: "flag-token"},
+		{name: "Remote address with env var", addr: ":8080", env: map[string]string{AuthTokenEnv: " env-token "}, expected: "env-token"},
+		{name: "Flag takes precedence over env var", addr: ":8080", flag: "flag-token", env: map[string]string{AuthTokenEnv: "env-token"}, expected: "flag-token"},
+		{name: "Remote address explicitly unauthenticated", addr: ":8080", allowUnauthenticated: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{Use: "sse"}
+			addFlags(cmd)
+			if tt.flag != "" {
+				require.NoError(t, cmd.Flags().Set("auth-token", tt.flag))
+			}
+			if tt.allowUnauthenticated {
+				require.NoError(t, cmd.Flags().Set("allow-unauthenticated", "true"))
+			}
+
+			token, err := resolveAuthToken(cmd, tt.addr, func(key string) (string, bool) {
+				value, ok := tt.env[key]
+				return value, ok
+			})
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, token)
+		})
+	}
+}
+
+func TestLoadConfig_SharesStdioSettings(t *testing.T) {
+	cmd := &cobra.Command{Use: "sse"}
+	addFlags(cmd)
+	require.NoError(t, cmd.Flags().Set("api-key", "test-key"))
+	require.NoError(t, cmd.Flags().Set("safe-mode", "true"))
+	require.NoError(t, cmd.Flags().Set("confirm-cost", "true"))
+	require.NoError(t, cmd.Flags().Set("banned-terms-file", "/etc/gaia/banned.txt"))
+
+	cfg, err := stdio.LoadConfig(cmd, func(string) (string, bool) { return "", false })
+	require.NoError(t, err)
+
+	assert.Equal(t, "sse", cfg.Transport)
+	assert.True(t, cfg.SafeMode)
+	assert.True(t, cfg.ConfirmCost)
+	assert.Equal(t, "/etc/gaia/banned.txt", cfg.BannedTermsFile)
+	assert.Equal(t, DefaultAddr, cmd.Flags().Lookup("addr").DefValue)
+}
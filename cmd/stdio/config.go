@@ -0,0 +1,198 @@
+package stdio
+
+import (
+	"encoding/json"
+	"fmt"
+	"gaia-mcp-go/internal/api"
+	"gaia-mcp-go/pkg/shared"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Config is the resolved configuration of the server, shared by every transport
+type Config struct {
+	Transport          string         `json:"transport"`
+	BaseUrl            string         `json:"baseUrl"`
+	HomepageUrl        string         `json:"homepageUrl"`
+	ApiKeys            []string       `json:"apiKeys"`
+	KeyStrategy        string         `json:"keyStrategy"`
+	HttpTimeout        time.Duration  `json:"-"`
+	CheckAuth          bool           `json:"checkAuth"`
+	SafeMode           bool           `json:"safeMode"`
+	ConfirmCost        bool           `json:"confirmCost"`
+	CreditCosts        map[string]int `json:"creditCosts,omitempty"`
+	CancelOnShutdown   bool           `json:"cancelOnShutdown"`
+	ImageMaxSize       int            `json:"imageMaxSize"`
+	ImageQuality       int            `json:"imageQuality"`
+	ImageCacheSize     int            `json:"imageCacheSize"`
+	ImageCacheTTL      time.Duration  `json:"-"`
+	MaxConcurrentTools int            `json:"maxConcurrentTools"`
+	BannedTermsFile    string         `json:"bannedTermsFile,omitempty"`
+	DownloadDir        string         `json:"downloadDir,omitempty"`
+	Tools              []string       `json:"tools"`
+	PrintConfig        bool           `json:"-"`
+	PrintConfigExit    bool           `json:"-"`
+}
+
+// LoadConfig reads the configuration from the flags registered by AddFlags and
+// the environment. Transport is set to the command's name.
+// It returns an error when a flag cannot be read or a value is invalid.
+func LoadConfig(cmd *cobra.Command, lookupEnv func(string) (string, bool)) (Config, error) {
+	flags := cmd.Flags()
+	cfg := Config{
+		Transport: cmd.Name(),
+		BaseUrl:   shared.BASE_API_URL,
+	}
+
+	apiKey, err := ResolveApiKey(cmd, lookupEnv)
+	if err != nil {
+		return Config{}, err
+	}
+	cfg.ApiKeys = strings.Split(apiKey, ",")
+
+	if cfg.KeyStrategy, err = flags.GetString("key-strategy"); err != nil {
+		return Config{}, fmt.Errorf("failed to get key-strategy flag: %w", err)
+	}
+
+	if cfg.HomepageUrl, err = flags.GetString("homepage-url"); err != nil {
+		return Config{}, fmt.Errorf("failed to get homepage-url flag: %w", err)
+	}
+	cfg.HomepageUrl = strings.TrimSuffix(strings.TrimSpace(cfg.HomepageUrl), "/")
+	if parsed, err := url.Parse(cfg.HomepageUrl); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return Config{}, fmt.Errorf("homepage-url must be an http(s) URL, got %q", cfg.HomepageUrl)
+	}
+
+	if cfg.HttpTimeout, err = resolveHttpTimeout(cmd, lookupEnv); err != nil {
+		return Config{}, err
+	}
+
+	boolFlags := map[string]*bool{
+		"check-auth":         &cfg.CheckAuth,
+		"safe-mode":          &cfg.SafeMode,
+		"confirm-cost":       &cfg.ConfirmCost,
+		"cancel-on-shutdown": &cfg.CancelOnShutdown,
+		"print-config":       &cfg.PrintConfig,
+		"print-config-exit":  &cfg.PrintConfigExit,
+	}
+	for name, value := range boolFlags {
+		if *value, err = flags.GetBool(name); err != nil {
+			return Config{}, fmt.Errorf("failed to get %s flag: %w", name, err)
+		}
+	}
+
+	if cfg.CreditCosts, err = flags.GetStringToInt("credit-costs"); err != nil {
+		return Config{}, fmt.Errorf("failed to get credit-costs flag: %w", err)
+	}
+	for key, cost := range cfg.CreditCosts {
+		if cost < 0 {
+			return Config{}, fmt.Errorf("credit-costs %s must not be negative, got %d", key, cost)
+		}
+	}
+
+	if cfg.BannedTermsFile, err = flags.GetString("banned-terms-file"); err != nil {
+		return Config{}, fmt.Errorf("failed to get banned-terms-file flag: %w", err)
+	}
+	if cfg.DownloadDir, err = flags.GetString("download-dir"); err != nil {
+		return Config{}, fmt.Errorf("failed to get download-dir flag: %w", err)
+	}
+
+	if cfg.ImageMaxSize, err = flags.GetInt("image-max-size"); err != nil {
+		return Config{}, fmt.Errorf("failed to get image-max-size flag: %w", err)
+	}
+	if cfg.ImageQuality, err = flags.GetInt("image-quality"); err != nil {
+		return Config{}, fmt.Errorf("failed to get image-quality flag: %w", err)
+	}
+	if cfg.ImageCacheSize, err = flags.GetInt("image-cache-size"); err != nil {
+		return Config{}, fmt.Errorf("failed to get image-cache-size flag: %w", err)
+	}
+	if cfg.ImageCacheSize < 0 {
+		return Config{}, fmt.Errorf("image-cache-size must not be negative, got %d", cfg.ImageCacheSize)
+	}
+	if cfg.ImageCacheTTL, err = flags.GetDuration("image-cache-ttl"); err != nil {
+		return Config{}, fmt.Errorf("failed to get image-cache-ttl flag: %w", err)
+	}
+
+	if cfg.MaxConcurrentTools, err = flags.GetInt("max-concurrent-tools"); err != nil {
+		return Config{}, fmt.Errorf("failed to get max-concurrent-tools flag: %w", err)
+	}
+	if cfg.MaxConcurrentTools < 0 {
+		return Config{}, fmt.Errorf("max-concurrent-tools must not be negative, got %d", cfg.MaxConcurrentTools)
+	}
+
+	return cfg, nil
+}
+
+// apiConfig returns the API client configuration
+func (c Config) apiConfig() api.GaiaApiConfig {
+	return api.GaiaApiConfig{
+		BaseUrl:     c.BaseUrl,
+		ApiKeys:     c.ApiKeys,
+		KeyStrategy: api.KeyStrategy(c.KeyStrategy),
+		SafeMode:    c.SafeMode,
+		Timeout:     c.HttpTimeout,
+		HomepageUrl: c.HomepageUrl,
+	}
+}
+
+const (
+	// creditCostStyleKey is the --credit-costs key of the cost of creating a style
+	creditCostStyleKey = "style"
+	// creditCostUploadKey is the --credit-costs key of the cost of uploading an image
+	creditCostUploadKey = "upload"
+)
+
+// creditCosts returns the cost estimates of the credit check. Keys other than
+// style and upload are recipe ids.
+func (c Config) creditCosts() api.CreditCosts {
+	costs := api.CreditCosts{PerImage: map[shared.RecipeId]int{}}
+	for key, cost := range c.CreditCosts {
+		switch key {
+		case creditCostStyleKey:
+			costs.PerStyle = cost
+		case creditCostUploadKey:
+			costs.PerUpload = cost
+		default:
+			costs.PerImage[shared.RecipeId(key)] = cost
+		}
+	}
+	return costs
+}
+
+// printConfig writes the configuration as indented JSON, with the API keys redacted
+func printConfig(w io.Writer, cfg Config) error {
+	redacted := cfg
+	redacted.ApiKeys = make([]string, len(cfg.ApiKeys))
+	for i, key := range cfg.ApiKeys {
+		redacted.ApiKeys[i] = redactApiKey(key)
+	}
+
+	// Print durations as strings such as "1m0s" rather than nanoseconds
+	data, err := json.MarshalIndent(struct {
+		Config
+		HttpTimeout   string `json:"httpTimeout"`
+		ImageCacheTTL string `json:"imageCacheTTL"`
+	}{redacted, redacted.HttpTimeout.String(), redacted.ImageCacheTTL.String()}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
+
+// redactApiKey hides an API key, keeping only its last 4 characters when the
+// key is long enough for them not to give it away
+func redactApiKey(key string) string {
+	key = strings.TrimSpace(key)
+	switch {
+	case key == "":
+		return "(not set)"
+	case len(key) <= 12:
+		return "****"
+	default:
+		return "****" + key[len(key)-4:]
+	}
+}
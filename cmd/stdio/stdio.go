@@ -1,11 +1,20 @@
 package stdio
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"gaia-mcp-go/internal/api"
+	"gaia-mcp-go/internal/interfaces"
 	"gaia-mcp-go/internal/tools"
+	"gaia-mcp-go/pkg/httpclient"
+	"gaia-mcp-go/pkg/imageutil"
 	"gaia-mcp-go/pkg/shared"
 	"log/slog"
+	"net/http"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/spf13/cobra"
@@ -20,32 +29,62 @@ var (
 	}
 
 	ServerName = "gaia-mcp-server"
+
+	// shutdownCancelTimeout bounds how long cancelling pending tasks may delay shutdown
+	shutdownCancelTimeout = 10 * time.Second
+
+	// ApiKeyEnv names the environment variable holding the API key, read when
+	// --api-key is not set so the key stays out of process listings
+	ApiKeyEnv = "GAIA_API_KEY"
+
+	// ErrMissingApiKey is returned when neither --api-key nor GAIA_API_KEY is set
+	ErrMissingApiKey = errors.New("no API key: set the " + ApiKeyEnv + " environment variable or pass --api-key")
+
+	// httpTimeoutEnv names the environment variable holding the default API request timeout
+	httpTimeoutEnv = "GAIA_HTTP_TIMEOUT"
+
+	// ErrInvalidApiKey is returned when the Gaia API rejects the configured API key
+	ErrInvalidApiKey = errors.New("invalid or expired API key")
 )
 
 func init() {
-	StdioCmd.Flags().StringP("api-key", "k", "", "The API key to use for the Gaia MCP server")
+	AddFlags(StdioCmd)
+}
+
+// AddFlags registers the server flags shared by every transport on the command
+func AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("api-key", "k", "", "The API key to use for the Gaia MCP server (comma-separated to use several keys; overrides the "+ApiKeyEnv+" environment variable)")
+	cmd.Flags().String("key-strategy", string(api.KeyStrategyFailover), "How to use several API keys: 'failover' or 'round-robin'")
+	cmd.Flags().Bool("check-auth", false, "Verify the API key against the Gaia API before serving and exit if it is rejected")
+	cmd.Flags().Bool("safe-mode", false, "Withhold generated images and style thumbnails flagged as sensitive or unsafe")
+	cmd.Flags().Bool("cancel-on-shutdown", false, "Cancel polled tasks (e.g. describe) that are still running when the server shuts down; image generations in flight finish anyway")
+	cmd.Flags().Bool("confirm-cost", false, "Check the credit balance against the estimated cost before each generation, style creation or paid upload")
+	cmd.Flags().StringToInt("credit-costs", nil, "Credits per image by recipe id for --confirm-cost, e.g. image-generator-simple=2,upscaler=1; the keys style and upload set the cost of a style and of an uploaded image (unlisted recipes and styles cost 1, uploads are free)")
+	cmd.Flags().String("homepage-url", shared.HOMEPAGE_URL, "GAIA web app linked from error messages, e.g. to buy credits or create an API key, for self-hosted deployments")
+	cmd.Flags().Duration("http-timeout", api.DefaultTimeout, "Timeout for each Gaia API request, e.g. 30s or 2m (overrides the "+httpTimeoutEnv+" environment variable)")
+	cmd.Flags().Int("image-max-size", imageutil.MCPMaxWidth, "Maximum width and height in pixels of images returned to the MCP client")
+	cmd.Flags().Int("image-quality", imageutil.MCPJPEGQuality, "JPEG quality (1-100) of images returned to the MCP client")
+	cmd.Flags().Int("image-cache-size", 0, "Number of processed images kept in memory, so an image returned again is not downloaded twice (0 disables the cache)")
+	cmd.Flags().Duration("image-cache-ttl", imageutil.DefaultCacheTTL, "How long a processed image stays in the image cache, e.g. 5m")
+	cmd.Flags().Int("max-concurrent-tools", tools.DefaultMaxConcurrentTools, "Maximum number of tool calls that run at once; further calls wait for a free slot (0 for no limit)")
+	cmd.Flags().String("banned-terms-file", "", "File of banned terms, one per line. Prompts containing one are rejected before they are submitted")
+	cmd.Flags().String("download-dir", "", "Directory the download_image tool saves images to (defaults to "+tools.DefaultDownloadDir()+")")
+	cmd.Flags().Bool("print-config", false, "Print the resolved configuration (with API keys redacted) to stderr at startup")
+	cmd.Flags().Bool("print-config-exit", false, "Exit after printing the configuration instead of serving (implies --print-config)")
 }
 
 func runStdio(cmd *cobra.Command, args []string) {
-	// Get the API key from the args
-	apiKey, err := cmd.Flags().GetString("api-key")
+	cfg, err := LoadConfig(cmd, os.LookupEnv)
 	if err != nil {
-		slog.Error("Failed to get API key", "error", err)
+		slog.Error("Invalid configuration", "error", err)
 		os.Exit(1)
 	}
 
-	// Create the API client
-	apiClient := api.NewGaiaApi(api.GaiaApiConfig{
-		BaseUrl: shared.BASE_API_URL,
-		ApiKey:  apiKey,
-	})
-
-	// Create the tools
-	generateImageTool := tools.NewGenerateImageTool(apiClient)
-	faceEnhancerTool := tools.NewFaceEnhancerTool(apiClient)
-	remixTool := tools.NewRemixTool(apiClient)
-	upscalerTool := tools.NewUpscalerTool(apiClient)
-	uploadImageTool := tools.NewUploadImageTool(apiClient)
+	// Stdio clients enforce tight size limits, so images are kept small
+	tracker, gaiaTools, ok := Prepare(cmd, &cfg, tools.ServerModeStdio)
+	if !ok {
+		return
+	}
 
 	// Create the server
 	s := server.NewMCPServer(
@@ -54,15 +93,198 @@ func runStdio(cmd *cobra.Command, args []string) {
 		server.WithToolCapabilities(false),
 	)
 
-	// Add the tools to the server
-	s.AddTool(generateImageTool.MCPTool(), generateImageTool.Handler)
-	s.AddTool(faceEnhancerTool.MCPTool(), faceEnhancerTool.Handler)
-	s.AddTool(remixTool.MCPTool(), remixTool.Handler)
-	s.AddTool(upscalerTool.MCPTool(), upscalerTool.Handler)
-	s.AddTool(uploadImageTool.MCPTool(), uploadImageTool.Handler)
+	// Add the tools to the server, validating arguments against each tool schema first,
+	// tagging the API calls of every tool call with its trace IDs and queueing calls
+	// over the concurrency limit
+	tools.RegisterTools(s, gaiaTools, cfg.MaxConcurrentTools)
 
 	// Start the server
 	if err := server.ServeStdio(s); err != nil {
 		slog.Error("Failed to serve stdio", "error", err)
 	}
+
+	if tracker != nil {
+		CancelPendingTasks(context.Background(), tracker)
+	}
+}
+
+// Prepare builds the tools a transport serves from the configuration, so every
+// transport applies the same safety settings: the credit check, task tracking,
+// banned terms and image limits. It prints the configuration and verifies the
+// API key when asked to.
+//
+// It exits the process when a setting is invalid or the API key is rejected.
+// It returns the task tracker (nil unless --cancel-on-shutdown is set) and the
+// tools, or false when the server should not start (--print-config-exit).
+func Prepare(cmd *cobra.Command, cfg *Config, mode tools.ServerMode) (*api.TaskTracker, []interfaces.GaiaTool, bool) {
+	// Create the API client
+	var apiClient api.GaiaApi = api.NewGaiaApi(cfg.apiConfig())
+
+	// Reject generations the credit balance cannot cover before submitting them
+	if cfg.ConfirmCost {
+		apiClient = api.NewCostGuard(apiClient, cfg.creditCosts(), cfg.HomepageUrl)
+	}
+
+	// Track created tasks so they can be cancelled on shutdown
+	var tracker *api.TaskTracker
+	if cfg.CancelOnShutdown {
+		tracker = api.NewTaskTracker(apiClient)
+		apiClient = tracker
+	}
+
+	// Tune the size/quality tradeoff of the images returned by the tools
+	if err := applyImageDefaults(cfg.ImageMaxSize, cfg.ImageQuality); err != nil {
+		slog.Error("Invalid image settings", "error", err)
+		os.Exit(1)
+	}
+
+	// Create the tools
+	toolDefaults := tools.ToolDefaults{
+		ServerMode:     mode,
+		DownloadDir:    cfg.DownloadDir,
+		ImageCacheSize: cfg.ImageCacheSize,
+		ImageCacheTTL:  cfg.ImageCacheTTL,
+	}
+
+	// Optionally reject prompts with banned terms before they cost credits
+	var toolDeps tools.ToolDeps
+	if cfg.BannedTermsFile != "" {
+		filter, err := tools.LoadPromptFilter(cfg.BannedTermsFile)
+		if err != nil {
+			slog.Error("Failed to load banned terms", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Loaded banned terms", "count", filter.Len())
+		toolDeps.PromptFilter = filter
+	}
+	gaiaTools := tools.NewGaiaTools(apiClient, toolDefaults, toolDeps)
+	for _, tool := range gaiaTools {
+		cfg.Tools = append(cfg.Tools, tool.ToolName())
+	}
+
+	// Stdout carries the MCP protocol over stdio, so the configuration goes to stderr
+	if cfg.PrintConfig || cfg.PrintConfigExit {
+		if err := printConfig(cmd.ErrOrStderr(), *cfg); err != nil {
+			slog.Error("Failed to print configuration", "error", err)
+		}
+		if cfg.PrintConfigExit {
+			return nil, nil, false
+		}
+	}
+
+	// Verify the API key before serving any tools
+	if cfg.CheckAuth {
+		if err := verifyApiKey(cmd.Context(), apiClient); err != nil {
+			if errors.Is(err, ErrInvalidApiKey) {
+				slog.Error("Invalid or expired API key. Please check the --api-key value", "error", err)
+				os.Exit(1)
+			}
+			// Other failures (e.g. network issues) may be transient, so keep serving
+			slog.Warn("Could not verify API key, continuing anyway", "error", err)
+		}
+	}
+
+	return tracker, gaiaTools, true
+}
+
+// ResolveApiKey returns the API key. A non-empty --api-key flag takes
+// precedence over the GAIA_API_KEY environment variable.
+// It returns ErrMissingApiKey when neither is set.
+func ResolveApiKey(cmd *cobra.Command, lookupEnv func(string) (string, bool)) (string, error) {
+	apiKey, err := cmd.Flags().GetString("api-key")
+	if err != nil {
+		return "", fmt.Errorf("failed to get api-key flag: %w", err)
+	}
+	if strings.TrimSpace(apiKey) != "" {
+		return strings.TrimSpace(apiKey), nil
+	}
+
+	if value, ok := lookupEnv(ApiKeyEnv); ok && strings.TrimSpace(value) != "" {
+		return strings.TrimSpace(value), nil
+	}
+
+	return "", ErrMissingApiKey
+}
+
+// resolveHttpTimeout returns the API request timeout. An explicit --http-timeout
+// flag takes precedence over the GAIA_HTTP_TIMEOUT environment variable, which
+// takes precedence over api.DefaultTimeout.
+// It returns an error when the value is not a positive duration string such as "30s".
+func resolveHttpTimeout(cmd *cobra.Command, lookupEnv func(string) (string, bool)) (time.Duration, error) {
+	if cmd.Flags().Changed("http-timeout") {
+		timeout, err := cmd.Flags().GetDuration("http-timeout")
+		if err != nil {
+			return 0, fmt.Errorf("failed to get http-timeout flag: %w", err)
+		}
+		if timeout <= 0 {
+			return 0, fmt.Errorf("http-timeout must be positive, got %s", timeout)
+		}
+		return timeout, nil
+	}
+
+	value, ok := lookupEnv(httpTimeoutEnv)
+	if !ok || strings.TrimSpace(value) == "" {
+		return api.DefaultTimeout, nil
+	}
+
+	timeout, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a duration such as 30s or 2m, got %q", httpTimeoutEnv, value)
+	}
+	if timeout <= 0 {
+		return 0, fmt.Errorf("%s must be positive, got %q", httpTimeoutEnv, value)
+	}
+	return timeout, nil
+}
+
+// applyImageDefaults sets the dimensions and quality of images returned in MCP responses.
+// It returns an error when the size is not positive or the quality is outside 1-100.
+func applyImageDefaults(maxSize, quality int) error {
+	if maxSize <= 0 {
+		return fmt.Errorf("image-max-size must be positive, got %d", maxSize)
+	}
+	if quality < 1 || quality > 100 {
+		return fmt.Errorf("image-quality must be between 1 and 100, got %d", quality)
+	}
+
+	imageutil.MCPMaxWidth = maxSize
+	imageutil.MCPMaxHeight = maxSize
+	imageutil.MCPJPEGQuality = quality
+	return nil
+}
+
+// CancelPendingTasks cancels the tasks still running when the server stops,
+// so users aren't billed for abandoned work. Failures are logged, not fatal.
+func CancelPendingTasks(ctx context.Context, tracker *api.TaskTracker) {
+	pending := tracker.PendingTasks()
+	if len(pending) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, shutdownCancelTimeout)
+	defer cancel()
+
+	slog.Info("Cancelling pending tasks before shutdown", "count", len(pending))
+	if err := tracker.CancelPending(ctx); err != nil {
+		slog.Warn("Failed to cancel some pending tasks", "error", err)
+	}
+}
+
+// verifyApiKey checks the API key by fetching the current user.
+//
+// Returns an error wrapping ErrInvalidApiKey when the API responds with
+// 401 Unauthorized or 403 Forbidden, or a generic error for any other failure.
+func verifyApiKey(ctx context.Context, apiClient api.GaiaApi) error {
+	user, err := apiClient.WhoAmI(ctx)
+	if err != nil {
+		var apiErr *httpclient.APIError
+		if errors.As(err, &apiErr) &&
+			(apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden) {
+			return fmt.Errorf("%w: %v", ErrInvalidApiKey, err)
+		}
+		return fmt.Errorf("failed to verify API key: %w", err)
+	}
+
+	slog.Info("Authenticated with Gaia API", "uid", user.Uid, "username", user.Username)
+	return nil
 }
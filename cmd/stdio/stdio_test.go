@@ -0,0 +1,327 @@
+package stdio
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"gaia-mcp-go/internal/api"
+	"gaia-mcp-go/internal/testutil"
+	"gaia-mcp-go/internal/tools"
+	"gaia-mcp-go/pkg/imageutil"
+	"gaia-mcp-go/pkg/shared"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyApiKey(t *testing.T) {
+	tests := []struct {
+		name          string
+		mockResponse  testutil.MockResponse
+		expectError   bool
+		expectInvalid bool
+	}{
+		{
+			name: "Valid API key",
+			mockResponse: testutil.MockResponse{
+				StatusCode: 200,
+				Body: api.User{
+					Uid:      "user-123",
+					Username: "testuser",
+				},
+			},
+		},
+		{
+			name: "Unauthorized API key",
+			mockResponse: testutil.MockResponse{
+				StatusCode: 401,
+				Body:       map[string]interface{}{"message": "Unauthorized"},
+			},
+			expectError:   true,
+			expectInvalid: true,
+		},
+		{
+			name: "Forbidden API key",
+			mockResponse: testutil.MockResponse{
+				StatusCode: 403,
+				Body:       map[string]interface{}{"message": "Forbidden"},
+			},
+			expectError:   true,
+			expectInvalid: true,
+		},
+		{
+			name: "Unexpected client error",
+			mockResponse: testutil.MockResponse{
+				StatusCode: 404,
+				Body:       map[string]interface{}{"message": "Not Found"},
+			},
+			expectError:   true,
+			expectInvalid: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup test server
+			server := testutil.NewTestServer()
+			defer server.Close()
+
+			server.AddResponse("GET", "/api/users/me", tt.mockResponse)
+
+			apiClient := api.NewGaiaApi(api.GaiaApiConfig{
+				BaseUrl: server.URL,
+				ApiKey:  "test-key",
+			})
+
+			err := verifyApiKey(context.Background(), apiClient)
+
+			if !tt.expectError {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.Error(t, err)
+			assert.Equal(t, tt.expectInvalid, errors.Is(err, ErrInvalidApiKey))
+		})
+	}
+}
+
+func TestCancelPendingTasks(t *testing.T) {
+	server := testutil.NewTestServer()
+	defer server.Close()
+
+	server.AddResponse("POST", "/api/recipe/agi-tasks/create-task", testutil.MockResponse{
+		StatusCode: 200,
+		Body:       api.ImageGeneratedResponse{Success: true, TaskId: "task-1"},
+	})
+	server.AddResponse("POST", "/api/recipe/agi-tasks/task-1/cancel", testutil.MockResponse{StatusCode: 200})
+
+	tracker := api.NewTaskTracker(api.NewGaiaApi(api.GaiaApiConfig{
+		BaseUrl: server.URL,
+		ApiKey:  "test-key",
+	}))
+	_, err := tracker.GenerateImages(context.Background(), api.GenerateImagesRequest{})
+	assert.NoError(t, err)
+
+	CancelPendingTasks(context.Background(), tracker)
+
+	assert.Len(t, server.Requests("POST", "/api/recipe/agi-tasks/task-1/cancel"), 1)
+	assert.Empty(t, tracker.PendingTasks())
+}
+
+func TestApplyImageDefaults(t *testing.T) {
+	previousWidth, previousHeight, previousQuality := imageutil.MCPMaxWidth, imageutil.MCPMaxHeight, imageutil.MCPJPEGQuality
+	t.Cleanup(func() {
+		imageutil.MCPMaxWidth, imageutil.MCPMaxHeight, imageutil.MCPJPEGQuality = previousWidth, previousHeight, previousQuality
+	})
+
+	t.Run("Valid settings are used by the tools", func(t *testing.T) {
+		assert.NoError(t, applyImageDefaults(768, 85))
+
+		config := tools.ToolDefaults{ServerMode: tools.ServerModeStdio}.ImageProcessorConfig()
+		assert.Equal(t, 768, config.MaxWidth)
+		assert.Equal(t, 768, config.MaxHeight)
+		assert.Equal(t, 85, config.JPEGQuality)
+	})
+
+	t.Run("Invalid settings are rejected", func(t *testing.T) {
+		assert.Error(t, applyImageDefaults(0, 70))
+		assert.Error(t, applyImageDefaults(512, 0))
+		assert.Error(t, applyImageDefaults(512, 101))
+		assert.Equal(t, 768, imageutil.MCPMaxWidth)
+	})
+}
+
+func TestResolveHttpTimeout(t *testing.T) {
+	tests := []struct {
+		name          string
+		flag          string
+		env           map[string]string
+		expected      time.Duration
+		expectedError string
+	}{
+		{name: "Default", expected: api.DefaultTimeout},
+		{name: "Empty env var uses the default", env: map[string]string{httpTimeoutEnv: " "}, expected: api.DefaultTimeout},
+		{name: "Env var", env: map[string]string{httpTimeoutEnv: "90s"}, expected: 90 * time.Second},
+		{name: "Env var with minutes", env: map[string]string{httpTimeoutEnv: "2m"}, expected: 2 * time.Minute},
+		{name: "Flag", flag: "15s", expected: 15 * time.Second},
+		{name: "Flag takes precedence over env var", flag: "15s", env: map[string]string{httpTimeoutEnv: "90s"}, expected: 15 * time.Second},
+		{name: "Flag skips an invalid env var", flag: "15s", env: map[string]string{httpTimeoutEnv: "soon"}, expected: 15 * time.Second},
+		{name: "Invalid env var", env: map[string]string{httpTimeoutEnv: "soon"}, expectedError: `GAIA_HTTP_TIMEOUT must be a duration such as 30s or 2m, got "soon"`},
+		{name: "Env var without unit", env: map[string]string{httpTimeoutEnv: "30"}, expectedError: "must be a duration"},
+		{name: "Negative env var", env: map[string]string{httpTimeoutEnv: "-5s"}, expectedError: "GAIA_HTTP_TIMEOUT must be positive"},
+		{name: "Zero flag", flag: "0s", expectedError: "http-timeout must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			cmd.Flags().Duration("http-timeout", api.DefaultTimeout, "")
+			if tt.flag != "" {
+				require.NoError(t, cmd.Flags().Set("http-timeout", tt.flag))
+			}
+
+			lookupEnv := func(key string) (string, bool) {
+				value, ok := tt.env[key]
+				return value, ok
+			}
+
+			timeout, err := resolveHttpTimeout(cmd, lookupEnv)
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, timeout)
+		})
+	}
+}
+
+func TestResolveApiKey(t *testing.T) {
+	tests := []struct {
+		name          string
+		flag          string
+		env           map[string]string
+		expected      string
+		expectedError error
+	}{
+		{name: "Flag", flag: "flag-key", expected: "flag-key"},
+		{name: "Env var", env: map[string]string{ApiKeyEnv: "env-key"}, expected: "env-key"},
+		{name: "Flag takes precedence over env var", flag: "flag-key", env: map[string]string{ApiKeyEnv: "env-key"}, expected: "flag-key"},
+		{name: "Empty flag falls back to env var", flag: " ", env: map[string]string{ApiKeyEnv: "env-key"}, expected: "env-key"},
+		{name: "Surrounding whitespace is trimmed", env: map[string]string{ApiKeyEnv: " env-key\n"}, expected: "env-key"},
+		{name: "Neither set", expectedError: ErrMissingApiKey},
+		{name: "Empty env var", env: map[string]string{ApiKeyEnv: ""}, expectedError: ErrMissingApiKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			AddFlags(cmd)
+			if tt.flag != "" {
+				require.NoError(t, cmd.Flags().Set("api-key", tt.flag))
+			}
+
+			lookupEnv := func(key string) (string, bool) {
+				value, ok := tt.env[key]
+				return value, ok
+			}
+
+			apiKey, err := ResolveApiKey(cmd, lookupEnv)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, apiKey)
+		})
+	}
+}
+
+func TestLoadConfig_MissingApiKey(t *testing.T) {
+	cmd := &cobra.Command{}
+	AddFlags(cmd)
+
+	_, err := LoadConfig(cmd, func(string) (string, bool) { return "", false })
+	assert.ErrorIs(t, err, ErrMissingApiKey)
+	assert.ErrorContains(t, err, "GAIA_API_KEY")
+}
+
+func TestLoadConfig(t *testing.T) {
+	cmd := &cobra.Command{Use: "stdio"}
+	AddFlags(cmd)
+	require.NoError(t, cmd.Flags().Set("api-key", "sk-live-0123456789abcdef,short"))
+	require.NoError(t, cmd.Flags().Set("key-strategy", "round-robin"))
+	require.NoError(t, cmd.Flags().Set("safe-mode", "true"))
+	require.NoError(t, cmd.Flags().Set("image-quality", "85"))
+	require.NoError(t, cmd.Flags().Set("print-config", "true"))
+	require.NoError(t, cmd.Flags().Set("max-concurrent-tools", "2"))
+	require.NoError(t, cmd.Flags().Set("image-cache-size", "16"))
+	require.NoError(t, cmd.Flags().Set("homepage-url", "https://gaia.example.com/"))
+	require.NoError(t, cmd.Flags().Set("credit-costs", "image-generator-simple=2,style=5,upload=1"))
+
+	cfg, err := LoadConfig(cmd, func(key string) (string, bool) {
+		if key == httpTimeoutEnv {
+			return "90s", true
+		}
+		return "", false
+	})
+	require.NoError(t, err)
+	cfg.Tools = []string{"generate_image", "upscaler"}
+
+	assert.True(t, cfg.PrintConfig)
+	assert.False(t, cfg.PrintConfigExit)
+	assert.Equal(t, 90*time.Second, cfg.apiConfig().Timeout)
+	assert.Equal(t, api.KeyStrategyRoundRobin, cfg.apiConfig().KeyStrategy)
+	assert.Equal(t, "https://gaia.example.com", cfg.apiConfig().HomepageUrl)
+	assert.Equal(t, api.CreditCosts{
+		PerImage:  map[shared.RecipeId]int{shared.RecipeIdImageGeneratorSimple: 2},
+		PerStyle:  5,
+		PerUpload: 1,
+	}, cfg.creditCosts())
+
+	var out bytes.Buffer
+	require.NoError(t, printConfig(&out, cfg))
+
+	var printed map[string]any
+	require.NoError(t, json.Unmarshal(out.Bytes(), &printed))
+	assert.Equal(t, "stdio", printed["transport"])
+	assert.Equal(t, shared.BASE_API_URL, printed["baseUrl"])
+	assert.Equal(t, "round-robin", printed["keyStrategy"])
+	assert.Equal(t, "https://gaia.example.com", printed["homepageUrl"])
+	assert.Equal(t, "1m30s", printed["httpTimeout"])
+	assert.Equal(t, true, printed["safeMode"])
+	assert.Equal(t, false, printed["checkAuth"])
+	assert.Equal(t, 85.0, printed["imageQuality"])
+	assert.Equal(t, 2.0, printed["maxConcurrentTools"])
+	assert.Equal(t, 16.0, printed["imageCacheSize"])
+	assert.Equal(t, "5m0s", printed["imageCacheTTL"])
+	assert.Equal(t, []any{"generate_image", "upscaler"}, printed["tools"])
+	assert.NotContains(t, printed, "printConfig")
+
+	// API keys are redacted
+	assert.Equal(t, []any{"****cdef", "****"}, printed["apiKeys"])
+	assert.NotContains(t, out.String(), "sk-live")
+	assert.NotContains(t, out.String(), "short")
+}
+
+func TestLoadConfig_NegativeMaxConcurrentTools(t *testing.T) {
+	cmd := &cobra.Command{}
+	AddFlags(cmd)
+	require.NoError(t, cmd.Flags().Set("api-key", "test-key"))
+	require.NoError(t, cmd.Flags().Set("max-concurrent-tools", "-1"))
+
+	_, err := LoadConfig(cmd, func(string) (string, bool) { return "", false })
+	assert.EqualError(t, err, "max-concurrent-tools must not be negative, got -1")
+}
+
+func TestLoadConfig_NegativeCreditCost(t *testing.T) {
+	cmd := &cobra.Command{}
+	AddFlags(cmd)
+	require.NoError(t, cmd.Flags().Set("api-key", "test-key"))
+	require.NoError(t, cmd.Flags().Set("credit-costs", "upscaler=-1"))
+
+	_, err := LoadConfig(cmd, func(string) (string, bool) { return "", false })
+	assert.EqualError(t, err, "credit-costs upscaler must not be negative, got -1")
+}
+
+func TestLoadConfig_InvalidHomepageUrl(t *testing.T) {
+	cmd := &cobra.Command{}
+	AddFlags(cmd)
+	require.NoError(t, cmd.Flags().Set("api-key", "test-key"))
+	require.NoError(t, cmd.Flags().Set("homepage-url", "gaia.example.com"))
+
+	_, err := LoadConfig(cmd, func(string) (string, bool) { return "", false })
+	assert.EqualError(t, err, `homepage-url must be an http(s) URL, got "gaia.example.com"`)
+}
+
+func TestRedactApiKey(t *testing.T) {
+	assert.Equal(t, "(not set)", redactApiKey(""))
+	assert.Equal(t, "****", redactApiKey("abc123"))
+	assert.Equal(t, "****", redactApiKey("abcdefghijkl"))
+	assert.Equal(t, "****mnop", redactApiKey("abcdefghijklmnop"))
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"gaia-mcp-go/version"
+
+	"github.com/spf13/cobra"
+)
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print the build information",
+	Long:  `Print the version, git commit, build date, Go version and platform of this build.`,
+	Args:  cobra.NoArgs,
+	RunE:  runVersion,
+}
+
+func init() {
+	versionCmd.Flags().Bool("json", false, "Print the build information as JSON")
+}
+
+func runVersion(cmd *cobra.Command, args []string) error {
+	asJSON, _ := cmd.Flags().GetBool("json")
+	info := version.Get()
+
+	if !asJSON {
+		_, err := fmt.Fprintln(cmd.OutOrStdout(), info.String())
+		return err
+	}
+
+	encoder := json.NewEncoder(cmd.OutOrStdout())
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(info)
+}
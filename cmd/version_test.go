@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"gaia-mcp-go/version"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runVersionCmd runs the version command with the given flags and returns its output
+func runVersionCmd(t *testing.T, args ...string) string {
+	t.Helper()
+
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs(append([]string{"version"}, args...))
+	t.Cleanup(func() {
+		rootCmd.SetOut(nil)
+		rootCmd.SetArgs(nil)
+		versionCmd.Flags().Set("json", "false")
+	})
+
+	require.NoError(t, rootCmd.Execute())
+	return out.String()
+}
+
+func TestVersionCmd(t *testing.T) {
+	t.Run("JSON", func(t *testing.T) {
+		output := runVersionCmd(t, "--json")
+
+		var fields map[string]any
+		require.NoError(t, json.Unmarshal([]byte(output), &fields))
+		assert.Contains(t, fields, "gitCommit")
+		assert.Equal(t, runtime.Version(), fields["goVersion"])
+		assert.Equal(t, runtime.GOOS+"/"+runtime.GOARCH, fields["platform"])
+		assert.Equal(t, version.Version, fields["raw"])
+	})
+
+	t.Run("Human readable", func(t *testing.T) {
+		output := runVersionCmd(t)
+
+		assert.Equal(t, version.Get().String()+"\n", output)
+	})
+}
@@ -3,15 +3,18 @@ module gaia-mcp-go
 go 1.23.3
 
 require (
+	github.com/andybalholm/brotli v1.2.5
+	github.com/google/uuid v1.6.0
 	github.com/mark3labs/mcp-go v0.31.0
 	github.com/spf13/cobra v1.9.1
 	github.com/stretchr/testify v1.9.0
 	golang.org/x/image v0.27.0
+	golang.org/x/sync v0.10.0
+	golang.org/x/time v0.11.0
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
-	github.com/google/uuid v1.6.0 // indirect
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/spf13/cast v1.7.1 // indirect
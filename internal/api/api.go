@@ -4,15 +4,22 @@ import (
 	"bytes"
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"gaia-mcp-go/pkg/httpclient"
 	"gaia-mcp-go/pkg/imageutil"
+	"gaia-mcp-go/pkg/retry"
 	"gaia-mcp-go/pkg/shared"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/sync/errgroup"
 )
 
 // Package api provides a client interface for interacting with the Gaia API.
@@ -45,10 +52,43 @@ type GaiaApi interface {
 	//   - imageUrls: Slice of HTTP(S) URLs pointing to reference images
 	//   - name: Human-readable name for the style
 	//   - description: Optional description of the style (can be nil)
+	//   - opts: Optional image weights or weight preset
 	//
 	// Returns the created SdStyle with its unique ID and metadata, or an error
-	// if the creation fails due to invalid URLs, network issues, or API errors.
-	CreateStyle(ctx context.Context, imageUrls []string, name string, description *string) (SdStyle, error)
+	// if the creation fails due to invalid URLs or weights, network issues, or API errors.
+	CreateStyle(ctx context.Context, imageUrls []string, name string, description *string, opts ...CreateStyleOptions) (SdStyle, error)
+
+	// GetStyle fetches an SD style, including its reference images.
+	//
+	// Parameters:
+	//   - ctx: Context for request cancellation and timeout control
+	//   - styleId: The unique identifier of the style
+	//
+	// Returns the SdStyle, or an error if the style does not exist or the
+	// request fails.
+	GetStyle(ctx context.Context, styleId string) (SdStyle, error)
+
+	// ListStyles lists the SD styles available to the user, one page at a time.
+	//
+	// Parameters:
+	//   - ctx: Context for request cancellation and timeout control
+	//   - page: The 1-based page number; values below 1 request the first page
+	//   - perPage: The number of styles per page; zero or less uses DefaultStylesPerPage
+	//
+	// Returns the page of styles with the pagination totals, or an error if the
+	// request fails.
+	ListStyles(ctx context.Context, page, perPage int) (httpclient.PaginatedResponse[SdStyle], error)
+
+	// DeleteStyle deletes an SD style.
+	//
+	// Parameters:
+	//   - ctx: Context for request cancellation and timeout control
+	//   - styleId: The unique identifier of the style
+	//
+	// Only styles whose Capabilities.CanDelete is true can be deleted. For the
+	// others the API refuses the request, and the server's reason is returned
+	// as an *APIStatusError with status 403.
+	DeleteStyle(ctx context.Context, styleId string) error
 
 	// GenerateImages creates a new image generation task using the Gaia AGI system.
 	//
@@ -57,6 +97,10 @@ type GaiaApi interface {
 	//   - req: GenerateImagesRequest containing generation parameters like prompt,
 	//          style settings, dimensions, and other generation options
 	//
+	// An Idempotency-Key header is sent with every submission (req.IdempotencyKey,
+	// or a generated UUID when empty) and stays the same across client retries,
+	// so the server won't create duplicate tasks.
+	//
 	// Returns ImageGeneratedResponse containing the task ID and status information,
 	// or an error if the generation request fails validation or submission.
 	GenerateImages(ctx context.Context, req GenerateImagesRequest) (ImageGeneratedResponse, error)
@@ -71,10 +115,98 @@ type GaiaApi interface {
 	//   - ctx: Context for request cancellation and timeout control
 	//   - imageUrls: Slice of HTTP(S) URLs pointing to images to upload
 	//   - associatedResource: Metadata about the resource these images are associated with
+	//   - opts: Optional UploadOptions, e.g. a progress callback
 	//
 	// Returns a slice of UploadFile containing the uploaded file metadata,
 	// or an error if any uploads fail. Partial failures are reported in the error.
-	UploadImages(ctx context.Context, imageUrls []string, associatedResource shared.FileAssociatedResource) ([]UploadFile, error)
+	UploadImages(ctx context.Context, imageUrls []string, associatedResource shared.FileAssociatedResource, opts ...UploadOptions) ([]UploadFile, error)
+
+	// WhoAmI returns the user that owns the configured API key.
+	//
+	// Parameters:
+	//   - ctx: Context for request cancellation and timeout control
+	//
+	// Returns the authenticated User, or an error if the API key is invalid,
+	// expired, or the request fails. This is a cheap call that can be used to
+	// validate credentials before doing any real work.
+	WhoAmI(ctx context.Context) (User, error)
+
+	// GetAccount returns the billing state of the configured API key's user.
+	//
+	// Parameters:
+	//   - ctx: Context for request cancellation and timeout control
+	//
+	// Returns the Account with the current credit balance, or an error if the
+	// request fails.
+	GetAccount(ctx context.Context) (Account, error)
+
+	// EstimateCost estimates the credits a generation request will consume.
+	//
+	// The estimate is computed locally from the recipe's cost per image and the
+	// number of images; no request is sent to the API.
+	//
+	// Parameters:
+	//   - ctx: Context for request cancellation and timeout control
+	//   - req: The generation request to estimate
+	//
+	// Returns the estimated credit cost, or an error if it cannot be estimated.
+	EstimateCost(ctx context.Context, req GenerateImagesRequest) (int, error)
+
+	// GetTaskStatus fetches the current state of a recipe task.
+	//
+	// Parameters:
+	//   - ctx: Context for request cancellation and timeout control
+	//   - taskId: The unique identifier of the task to look up
+	//
+	// Returns the RecipeTask with its current status, or an error if the
+	// task cannot be found or the request fails.
+	GetTaskStatus(ctx context.Context, taskId string) (RecipeTask, error)
+
+	// WaitForTask polls a task at a fixed interval until it reaches a terminal
+	// status (COMPLETED, FAILED or CANCELLED).
+	//
+	// Parameters:
+	//   - ctx: Context for request cancellation and timeout control
+	//   - taskId: The unique identifier of the task to wait for
+	//   - pollInterval: Time between polls (DefaultPollInterval when not positive)
+	//
+	// Returns the task in its terminal status, or an error if polling fails
+	// or the context is done.
+	WaitForTask(ctx context.Context, taskId string, pollInterval time.Duration) (RecipeTask, error)
+
+	// WaitForTaskWithOptions polls a task until it reaches a terminal status
+	// (COMPLETED, FAILED or CANCELLED).
+	//
+	// Parameters:
+	//   - ctx: Context for request cancellation and timeout control
+	//   - taskId: The unique identifier of the task to wait for
+	//   - opts: Polling strategy, overall deadline and progress callback
+	//
+	// Returns the task in its terminal status, or an error if polling fails,
+	// the context is cancelled, or the deadline is exceeded.
+	WaitForTaskWithOptions(ctx context.Context, taskId string, opts WaitOptions) (RecipeTask, error)
+
+	// DownloadImageBytes fetches the original file of a generated image.
+	//
+	// Unlike the MCP image helpers, the bytes are returned exactly as served
+	// by the GAIA CDN, without any decoding or re-encoding.
+	//
+	// Parameters:
+	//   - ctx: Context for request cancellation and timeout control
+	//   - imageUrl: URL of the image. It must be hosted on the GAIA CDN.
+	//
+	// Returns the raw image bytes and their content type, or an error if the
+	// URL is not a GAIA CDN URL or the download fails.
+	DownloadImageBytes(ctx context.Context, imageUrl string) ([]byte, string, error)
+
+	// CancelTask requests cancellation of a queued or running task.
+	//
+	// Parameters:
+	//   - ctx: Context for request cancellation and timeout control
+	//   - taskId: The unique identifier of the task to cancel
+	//
+	// Returns an error if the task cannot be cancelled or the request fails.
+	CancelTask(ctx context.Context, taskId string) error
 }
 
 // GaiaApiConfig holds the configuration needed to create a Gaia API client.
@@ -86,6 +218,58 @@ type GaiaApiConfig struct {
 	BaseUrl string
 	// ApiKey is the authentication token for accessing the Gaia API
 	ApiKey string
+	// ApiKeys optionally lists several API keys to spread load across.
+	// When set, it is used instead of ApiKey.
+	ApiKeys []string
+	// KeyStrategy selects how ApiKeys are used. Defaults to KeyStrategyFailover.
+	// With either strategy, a key that is rate limited or out of credits is
+	// skipped and the request is retried with the next key.
+	KeyStrategy KeyStrategy
+	// CdnUrl is the base URL of the GAIA CDN that serves generated images.
+	// Defaults to shared.CDN_URL.
+	CdnUrl string
+	// SafeMode withholds generated images and style thumbnails that
+	// moderation rated as sensitive or unsafe.
+	SafeMode bool
+	// Timeout bounds every API and CDN request. Defaults to DefaultTimeout.
+	Timeout time.Duration
+	// UploadTimeout bounds each chunk upload of UploadImages, which sends far
+	// more data than other requests. Defaults to DefaultUploadTimeout.
+	UploadTimeout time.Duration
+	// HomepageUrl is the GAIA web app linked from error messages, e.g. to buy
+	// credits or create an API key. Defaults to shared.HOMEPAGE_URL.
+	HomepageUrl string
+	// MaxConcurrentImages bounds how many images UploadImages processes at
+	// once. Defaults to DefaultMaxConcurrentImages.
+	MaxConcurrentImages int
+	// ChunkUploadAttempts bounds the attempts at uploading each chunk of an
+	// image, including the first one. Defaults to DefaultChunkUploadAttempts.
+	ChunkUploadAttempts int
+}
+
+// DefaultTimeout is the request timeout used when GaiaApiConfig.Timeout is not set
+const DefaultTimeout = 60 * time.Second
+
+// DefaultUploadTimeout is the chunk upload timeout used when
+// GaiaApiConfig.UploadTimeout is not set
+const DefaultUploadTimeout = 5 * time.Minute
+
+// DefaultMaxConcurrentImages is the number of images uploaded at once when
+// GaiaApiConfig.MaxConcurrentImages is not set
+const DefaultMaxConcurrentImages = 4
+
+// DefaultChunkUploadAttempts is the number of attempts at uploading a chunk
+// when GaiaApiConfig.ChunkUploadAttempts is not set
+const DefaultChunkUploadAttempts = 3
+
+// IdempotencyKeyHeader is the header used to dedupe generation submissions
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// chunkRetryBackoff is the delay schedule between chunk upload attempts
+var chunkRetryBackoff = retry.Backoff{
+	Strategy: retry.StrategyExponential,
+	Initial:  500 * time.Millisecond,
+	Max:      5 * time.Second,
 }
 
 // gaiaApi is the concrete implementation of the GaiaApi interface.
@@ -93,14 +277,36 @@ type GaiaApiConfig struct {
 // This struct contains an HTTP client configured with the appropriate
 // base URL, authentication headers, and timeout settings for Gaia API calls.
 type gaiaApi struct {
-	client *httpclient.Client
+	client      *httpclient.Client
+	cdnClient   *httpclient.Client
+	cdnUrl      string
+	safeMode    bool
+	keys        *keyPool
+	homepageUrl string
+
+	// maxConcurrentImages bounds the images processed at once by UploadImages.
+	// DefaultMaxConcurrentImages is used when it is not positive.
+	maxConcurrentImages int
+
+	// chunkUploadAttempts bounds the attempts per chunk upload.
+	// DefaultChunkUploadAttempts is used when it is not positive.
+	chunkUploadAttempts int
+	// uploadTimeout bounds each chunk upload attempt.
+	// DefaultUploadTimeout is used when it is not positive.
+	uploadTimeout time.Duration
+	// chunkBackoff is the delay schedule between chunk upload attempts
+	chunkBackoff retry.Backoff
+	// processor downloads and re-encodes the images UploadImages uploads.
+	// It lives as long as the client, so downloads reuse connections.
+	processor *imageutil.Processor
 }
 
 // NewGaiaApi creates a new Gaia API client with the provided configuration.
 //
 // The client is configured with:
-//   - Bearer token authentication using the provided API key
-//   - 60-second timeout for all API requests
+//   - Bearer token authentication using the provided API key(s)
+//   - cfg.Timeout (60 seconds by default) for all API requests
+//   - cfg.UploadTimeout (5 minutes by default) for each image chunk upload
 //   - Automatic request/response JSON marshaling
 //
 // Parameters:
@@ -108,21 +314,57 @@ type gaiaApi struct {
 //
 // Returns a GaiaApi interface implementation ready for use.
 func NewGaiaApi(cfg GaiaApiConfig) GaiaApi {
+	apiKeys := cfg.ApiKeys
+	if len(apiKeys) == 0 {
+		apiKeys = []string{cfg.ApiKey}
+	}
+	keys := newKeyPool(apiKeys, cfg.KeyStrategy)
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	// The Authorization header is set per request from the key pool
 	client := httpclient.New(httpclient.Config{
 		BaseURL: cfg.BaseUrl,
-		DefaultHeaders: map[string]string{
-			"Authorization": fmt.Sprintf("Bearer %s", cfg.ApiKey),
-		},
-		Timeout: 60 * time.Second, // 60 seconds timeout for calling the API
+		Timeout: timeout,
 	})
-	return &gaiaApi{client: client}
+	client.AddHeaderInterceptor(keys.interceptor)
+	client.AddHeaderInterceptor(requestTraceInterceptor)
+
+	cdnUrl := strings.TrimSuffix(cfg.CdnUrl, "/")
+	if cdnUrl == "" {
+		cdnUrl = shared.CDN_URL
+	}
+	cdnClient := httpclient.New(httpclient.Config{
+		BaseURL: cdnUrl,
+		Timeout: timeout,
+	})
+	cdnClient.AddHeaderInterceptor(keys.interceptor)
+
+	return &gaiaApi{
+		client:      client,
+		cdnClient:   cdnClient,
+		cdnUrl:      cdnUrl,
+		safeMode:    cfg.SafeMode,
+		keys:        keys,
+		homepageUrl: strings.TrimSuffix(cfg.HomepageUrl, "/"),
+
+		maxConcurrentImages: cfg.MaxConcurrentImages,
+		chunkUploadAttempts: cfg.ChunkUploadAttempts,
+		uploadTimeout:       cfg.UploadTimeout,
+		chunkBackoff:        chunkRetryBackoff,
+		processor:           imageutil.NewProcessor(imageutil.FullResolutionConfig()),
+	}
 }
 
 // CreateStyle creates a new SD style from reference images.
 //
 // This method formats the provided image URLs into the expected API payload
-// format, with each image assigned a default weight of 0.5. The style can
-// be used later for image generation tasks.
+// format. Each image is weighted by the options' raw weights, or else by
+// their weight preset, which defaults to DefaultStyleImageWeight for every
+// image. The style can be used later for image generation tasks.
 //
 // The method handles:
 //   - Image URL validation and formatting
@@ -135,16 +377,22 @@ func NewGaiaApi(cfg GaiaApiConfig) GaiaApi {
 //   - imageUrls: URLs of reference images (must be HTTP/HTTPS)
 //   - name: Display name for the style
 //   - description: Optional style description (pass nil if not needed)
+//   - opts: Optional image weights or weight preset
 //
 // Returns the created SdStyle containing the style ID and metadata,
-// or an error if creation fails.
-func (a *gaiaApi) CreateStyle(ctx context.Context, imageUrls []string, name string, description *string) (SdStyle, error) {
+// or an error if the weights are invalid or creation fails.
+func (a *gaiaApi) CreateStyle(ctx context.Context, imageUrls []string, name string, description *string, opts ...CreateStyleOptions) (SdStyle, error) {
+	weights, err := styleImageWeights(len(imageUrls), opts)
+	if err != nil {
+		return SdStyle{}, err
+	}
+
 	// Formatting imageUrls to be an array of images
 	images := make([]map[string]interface{}, len(imageUrls))
 	for i, imageUrl := range imageUrls {
 		images[i] = map[string]interface{}{
 			"url":    imageUrl,
-			"weight": 0.5,
+			"weight": weights[i],
 		}
 	}
 
@@ -158,16 +406,140 @@ func (a *gaiaApi) CreateStyle(ctx context.Context, imageUrls []string, name stri
 	}
 
 	// Use the type-safe As[T] function - cleaner and more idiomatic
-	sdStyle, err := httpclient.As[SdStyle](
-		a.client.PostJSON(ctx, "/api/sd-styles", payload, map[string]string{}),
-	)
+	var sdStyle SdStyle
+	err = a.keys.withKey(ctx, func(ctx context.Context) (err error) {
+		sdStyle, err = httpclient.As[SdStyle](
+			a.client.PostJSON(ctx, "/api/sd-styles", payload, map[string]string{}),
+		)
+		return err
+	})
 	if err != nil {
-		return SdStyle{}, ProcessError(err)
+		return SdStyle{}, a.processError(err)
+	}
+
+	// The style itself is kept, only its flagged preview is withheld
+	if a.safeMode && sdStyle.ThumbnailModerationRating.IsFlagged() {
+		sdStyle.ThumbnailUrl = ""
 	}
 
 	return sdStyle, nil
 }
 
+// GetStyle fetches an SD style by its ID.
+//
+// Parameters:
+//   - ctx: Request context for cancellation and timeout
+//   - styleId: The unique identifier of the style
+//
+// Returns the SdStyle with its reference images and metadata, or an error
+// if the request fails.
+func (a *gaiaApi) GetStyle(ctx context.Context, styleId string) (SdStyle, error) {
+	var sdStyle SdStyle
+	err := a.keys.withKey(ctx, func(ctx context.Context) (err error) {
+		sdStyle, err = httpclient.As[SdStyle](
+			a.client.GetJSON(ctx, "/api/sd-styles/"+url.PathEscape(styleId), map[string]string{}),
+		)
+		return err
+	})
+	if err != nil {
+		return SdStyle{}, a.processError(err)
+	}
+
+	// Withhold a flagged preview, as for newly created styles
+	if a.safeMode && sdStyle.ThumbnailModerationRating.IsFlagged() {
+		sdStyle.ThumbnailUrl = ""
+	}
+
+	return sdStyle, nil
+}
+
+// DefaultStylesPerPage is the page size of ListStyles when none is given
+const DefaultStylesPerPage = 20
+
+// ListStyles fetches a page of the user's SD styles.
+//
+// Parameters:
+//   - ctx: Request context for cancellation and timeout
+//   - page: The 1-based page number
+//   - perPage: The number of styles per page
+//
+// Returns the styles of the page with the pagination totals, or an error if
+// the request fails.
+func (a *gaiaApi) ListStyles(ctx context.Context, page, perPage int) (httpclient.PaginatedResponse[SdStyle], error) {
+	if page < 1 {
+		page = 1
+	}
+	if perPage <= 0 {
+		perPage = DefaultStylesPerPage
+	}
+
+	query := url.Values{}
+	query.Set("page", strconv.Itoa(page))
+	query.Set("per_page", strconv.Itoa(perPage))
+
+	var styles httpclient.PaginatedResponse[SdStyle]
+	err := a.keys.withKey(ctx, func(ctx context.Context) (err error) {
+		styles, err = httpclient.AsPaginated[SdStyle](
+			a.client.GetJSON(ctx, "/api/sd-styles?"+query.Encode(), map[string]string{}),
+		)
+		return err
+	})
+	if err != nil {
+		return httpclient.PaginatedResponse[SdStyle]{}, a.processError(err)
+	}
+
+	// Withhold flagged previews, as for a single style
+	if a.safeMode {
+		for i := range styles.Data {
+			if styles.Data[i].ThumbnailModerationRating.IsFlagged() {
+				styles.Data[i].ThumbnailUrl = ""
+			}
+		}
+	}
+
+	return styles, nil
+}
+
+// DeleteStyle deletes an SD style by its ID.
+//
+// Parameters:
+//   - ctx: Request context for cancellation and timeout
+//   - styleId: The unique identifier of the style
+//
+// Returns an error if the request fails. When the user cannot delete the
+// style, the error carries the server's reason.
+func (a *gaiaApi) DeleteStyle(ctx context.Context, styleId string) error {
+	err := a.keys.withKey(ctx, func(ctx context.Context) error {
+		_, err := httpclient.As[struct{}](
+			a.client.DeleteJSON(ctx, "/api/sd-styles/"+url.PathEscape(styleId), map[string]string{}),
+		)
+		return err
+	})
+	if err == nil || isEmptyBody(err) {
+		// The delete endpoint may reply without a body (e.g. 204 No Content)
+		return nil
+	}
+
+	// Forbidden means the style cannot be deleted rather than a bad API key,
+	// so report the server's reason instead of the generic permission message
+	var apiErr *httpclient.APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusForbidden {
+		return &APIStatusError{
+			StatusCode: apiErr.StatusCode,
+			Message:    fmt.Sprintf("Style %s cannot be deleted: %s", styleId, apiErr.Message),
+			Err:        apiErr,
+		}
+	}
+
+	return a.processError(err)
+}
+
+// isEmptyBody reports whether err is a successful response that had no body to decode
+func isEmptyBody(err error) bool {
+	var decodeErr *httpclient.DecodeError
+	return errors.As(err, &decodeErr) && strings.TrimSpace(decodeErr.Snippet) == ""
+}
+
 // GenerateImages submits an image generation request to the Gaia AGI system.
 //
 // This method calls the agi-tasks/create-task endpoint to start a new
@@ -178,20 +550,85 @@ func (a *gaiaApi) CreateStyle(ctx context.Context, imageUrls []string, name stri
 //   - ctx: Request context for cancellation and timeout
 //   - req: Complete generation request with prompt, style, dimensions, etc.
 //
-// Returns ImageGeneratedResponse with task ID and initial status,
-// or an error if the request fails validation or submission.
+// Returns ImageGeneratedResponse with task ID and initial status, an
+// *InvalidParamError if the params fail ValidateGenerateRequest, or an error
+// if the submission fails.
 func (a *gaiaApi) GenerateImages(ctx context.Context, req GenerateImagesRequest) (ImageGeneratedResponse, error) {
+	// Catch invalid params locally rather than spending a request on them
+	if err := ValidateGenerateRequest(req); err != nil {
+		return ImageGeneratedResponse{}, err
+	}
+
+	// One key per logical request; the client's retries reuse the same headers
+	idempotencyKey := req.IdempotencyKey
+	if idempotencyKey == "" {
+		idempotencyKey = uuid.NewString()
+	}
+
 	// Use the type-safe As[T] function - cleaner and more idiomatic
-	imageGeneratedResponse, err := httpclient.As[ImageGeneratedResponse](
-		a.client.PostJSON(ctx, "/api/recipe/agi-tasks/create-task", req, map[string]string{}),
-	)
+	ctx = withKeySession(ctx)
+	var imageGeneratedResponse ImageGeneratedResponse
+	err := a.keys.withKey(ctx, func(ctx context.Context) (err error) {
+		imageGeneratedResponse, err = httpclient.As[ImageGeneratedResponse](
+			a.client.PostJSON(ctx, "/api/recipe/agi-tasks/create-task", req, map[string]string{
+				IdempotencyKeyHeader: idempotencyKey,
+			}),
+		)
+		return err
+	})
 	if err != nil {
-		return ImageGeneratedResponse{}, ProcessError(err)
+		return ImageGeneratedResponse{}, a.processError(err)
+	}
+
+	// A task still running is followed up with the account that created it
+	if len(imageGeneratedResponse.Images) == 0 {
+		a.keys.rememberTask(ctx, imageGeneratedResponse.TaskId)
+	}
+
+	if a.safeMode {
+		return withholdFlaggedImages(imageGeneratedResponse)
 	}
 
 	return imageGeneratedResponse, nil
 }
 
+// withholdFlaggedImages removes images rated sensitive or unsafe from the response.
+//
+// Returns ErrUnsafeContent when images were generated but all of them were flagged.
+func withholdFlaggedImages(res ImageGeneratedResponse) (ImageGeneratedResponse, error) {
+	if len(res.ModerationRatings) == 0 {
+		return res, nil
+	}
+
+	images := make([]string, 0, len(res.Images))
+	ratings := make([]ThumbnailModerationRating, 0, len(res.Images))
+	var origins []string
+	for i, image := range res.Images {
+		var rating ThumbnailModerationRating
+		if i < len(res.ModerationRatings) {
+			rating = res.ModerationRatings[i]
+		}
+		if rating.IsFlagged() {
+			continue
+		}
+		images = append(images, image)
+		ratings = append(ratings, rating)
+		// Keep the origin urls aligned with the remaining images
+		if i < len(res.OriginImages) {
+			origins = append(origins, res.OriginImages[i])
+		}
+	}
+
+	if len(res.Images) > 0 && len(images) == 0 {
+		return ImageGeneratedResponse{}, ErrUnsafeContent
+	}
+
+	res.Images = images
+	res.ModerationRatings = ratings
+	res.OriginImages = origins
+	return res, nil
+}
+
 // UploadImages handles concurrent multipart upload of multiple images.
 //
 // This method performs the following steps for each image:
@@ -201,135 +638,339 @@ func (a *gaiaApi) GenerateImages(ctx context.Context, req GenerateImagesRequest)
 //  4. Uploads image data in chunks concurrently for better performance
 //  5. Completes the multipart upload process
 //
-// The method uses goroutines for concurrent chunk uploads within each image
-// and continues processing other images even if some fail. All failures
-// are collected and reported in the final error.
+// Up to GaiaApiConfig.MaxConcurrentImages images are processed at once, and
+// the chunks of each image are uploaded concurrently. The method continues
+// processing other images even if some fail. All failures are collected and
+// reported in the final error. Uploaded files are returned in input order.
 //
 // Parameters:
 //   - ctx: Request context for cancellation and timeout control
 //   - imageUrls: Slice of HTTP/HTTPS URLs pointing to images
 //   - associatedResource: Metadata linking uploads to a specific resource
+//   - opts: Optional UploadOptions. Its Progress callback is called as each
+//     chunk completes, and no longer once any image has failed.
 //
 // Returns a slice of successfully uploaded files, or an error containing
 // details of any failures. If some uploads succeed and others fail,
 // only the error is returned with failure details.
-func (a *gaiaApi) UploadImages(ctx context.Context, imageUrls []string, associatedResource shared.FileAssociatedResource) ([]UploadFile, error) {
-	var uploadedFiles []UploadFile
-	var failedFiles []map[string]string
+func (a *gaiaApi) UploadImages(ctx context.Context, imageUrls []string, associatedResource shared.FileAssociatedResource, opts ...UploadOptions) ([]UploadFile, error) {
+	progress := newUploadProgress(opts)
 
-	for _, imageUrl := range imageUrls {
-		if !strings.HasPrefix(imageUrl, "http") {
-			failedFiles = append(failedFiles, map[string]string{
-				"url":   imageUrl,
-				"error": "URL must start with http:// or https://",
-			})
-			continue
-		}
+	// Every upload is initialized and completed with the same account
+	ctx = withKeySession(ctx)
+
+	// Results are stored by input position so the order does not depend on
+	// which upload finishes first
+	uploadedFiles := make([]UploadFile, len(imageUrls))
+	uploadErrors := make([]error, len(imageUrls))
 
-		// process the image
-		imageData, _, w, h, err := a.processImage(ctx, imageUrl)
+	limit := a.maxConcurrentImages
+	if limit <= 0 {
+		limit = DefaultMaxConcurrentImages
+	}
+
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i, imageUrl := range imageUrls {
+		wg.Add(1)
+		go func(i int, imageUrl string) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			uploadedFiles[i], uploadErrors[i] = a.uploadImage(ctx, imageUrl, associatedResource, progress)
+			if uploadErrors[i] != nil {
+				progress.fail()
+			}
+		}(i, imageUrl)
+	}
+	wg.Wait()
+
+	var failedFiles []map[string]string
+	for i, err := range uploadErrors {
 		if err != nil {
 			failedFiles = append(failedFiles, map[string]string{
-				"url":   imageUrl,
+				"url":   imageUrls[i],
 				"error": err.Error(),
 			})
-			continue
 		}
+	}
+
+	if len(failedFiles) > 0 {
+		return nil, fmt.Errorf("failed to upload some files: %v", failedFiles)
+	}
+
+	return uploadedFiles, nil
+}
+
+// uploadImage downloads, processes and uploads a single image
+func (a *gaiaApi) uploadImage(ctx context.Context, imageUrl string, associatedResource shared.FileAssociatedResource, progress *uploadProgress) (UploadFile, error) {
+	if !strings.HasPrefix(imageUrl, "http") {
+		return UploadFile{}, errors.New("URL must start with http:// or https://")
+	}
+
+	// process the image
+	imageData, mimeType, w, h, err := a.processImage(ctx, imageUrl)
+	if err != nil {
+		return UploadFile{}, err
+	}
+
+	// Upload the image, starting over with fresh presigned URLs if they expire
+	return a.uploadImageData(ctx, imageData, mimeType, w, h, associatedResource, progress)
+}
+
+// maxUploadReinits bounds how many times an upload is re-initialized after its
+// presigned chunk URLs expire
+const maxUploadReinits = 2
+
+// errPresignedUrlExpired is wrapped by chunk upload errors caused by an expired presigned URL
+var errPresignedUrlExpired = errors.New("presigned upload URL expired")
 
+// uploadImageData runs the multipart upload of one image: initialize, upload
+// the chunks and complete.
+//
+// Presigned chunk URLs are only valid for a limited time, so the chunks of a
+// very large upload can be rejected late in the process. When that happens the
+// upload is initialized again to get fresh URLs and the chunks are uploaded
+// again, up to maxUploadReinits times.
+//
+// Returns the uploaded file, or the error of the last attempt.
+func (a *gaiaApi) uploadImageData(ctx context.Context, imageData []byte, mimeType string, w, h int, associatedResource shared.FileAssociatedResource, progress *uploadProgress) (UploadFile, error) {
+	for attempt := 0; ; attempt++ {
 		// Initialize the upload file
-		initUploadResponse, err := a.initUploadImage(ctx, imageData, w, h, associatedResource)
+		initUploadResponse, err := a.initUploadImage(ctx, imageData, mimeType, w, h, associatedResource)
 		if err != nil {
-			failedFiles = append(failedFiles, map[string]string{
-				"url":   imageUrl,
-				"error": err.Error(),
-			})
+			return UploadFile{}, err
+		}
+
+		// Upload chunks concurrently; the first failure cancels the rest.
+		// A re-initialized upload sends every chunk again, so its bytes are expected again.
+		progress.expect(int64(len(imageData)))
+		parts, err := a.uploadChunks(ctx, imageData, initUploadResponse.UploadUrls, progress)
+		if errors.Is(err, errPresignedUrlExpired) && attempt < maxUploadReinits {
 			continue
 		}
+		if err != nil {
+			return UploadFile{}, fmt.Errorf("Failed to upload some chunks: %v", err)
+		}
 
-		// Upload chunk concurrently
-		var wg sync.WaitGroup
-		uploadParts := make([]*UploadPart, len(initUploadResponse.UploadUrls))
-		uploadErrs := make([]error, len(initUploadResponse.UploadUrls))
-
-		for i, url := range initUploadResponse.UploadUrls {
-			wg.Add(1)
-			go func(i int, url string) {
-				defer wg.Done()
-
-				// Calculate the chunk boundaries
-				start := i * shared.UPLOAD_CHUNK_SIZE
-				end := start + shared.UPLOAD_CHUNK_SIZE
-				if end > len(imageData) {
-					end = len(imageData)
-				}
+		// Complete the upload
+		if err := a.completeUpload(ctx, initUploadResponse.Key, initUploadResponse.UploadId, parts); err != nil {
+			return UploadFile{}, err
+		}
 
-				chunk := imageData[start:end]
-				partNumber := i + 1
+		return initUploadResponse.File, nil
+	}
+}
 
-				// Upload the chunk
-				part, err := a.uploadChunk(ctx, chunk, url, partNumber)
-				if err != nil {
-					uploadErrs[i] = err
-					return
-				}
+// isExpiredPresignedUrl reports whether an S3 chunk upload response means the
+// presigned URL has expired. S3 answers 403 with an AccessDenied "Request has
+// expired" error, or an ExpiredToken error when the signing credentials expired.
+func isExpiredPresignedUrl(statusCode int, body []byte) bool {
+	if statusCode != http.StatusForbidden {
+		return false
+	}
+	return bytes.Contains(body, []byte("Request has expired")) ||
+		bytes.Contains(body, []byte("<Code>ExpiredToken</Code>"))
+}
 
-				uploadParts[i] = part
-			}(i, url)
-		}
+// WhoAmI fetches the user associated with the configured API key.
+//
+// This method calls the current-user endpoint and returns the basic profile
+// fields (uid, name, email, username). Because it has no side effects, it is
+// suitable for validating the API key at startup.
+//
+// Parameters:
+//   - ctx: Request context for cancellation and timeout
+//
+// Returns the authenticated User, or an error if the request fails or the
+// API key is rejected.
+func (a *gaiaApi) WhoAmI(ctx context.Context) (User, error) {
+	var user User
+	err := a.keys.withKey(ctx, func(ctx context.Context) (err error) {
+		user, err = httpclient.As[User](
+			a.client.GetJSON(ctx, "/api/users/me", map[string]string{}),
+		)
+		return err
+	})
+	if err != nil {
+		return User{}, a.processError(err)
+	}
 
-		wg.Wait()
+	return user, nil
+}
 
-		// Check for errors
-		var hasErrors bool
-		for _, err := range uploadErrs {
-			if err != nil {
-				hasErrors = true
-				break
-			}
-		}
+// GetAccount fetches the billing state of the user associated with the configured API key.
+//
+// The credit balance is part of the current-user profile, so this reads the
+// same endpoint as WhoAmI.
+//
+// Parameters:
+//   - ctx: Request context for cancellation and timeout
+//
+// Returns the Account with the current credit balance, or an error if the
+// request fails or the API key is rejected.
+func (a *gaiaApi) GetAccount(ctx context.Context) (Account, error) {
+	var account Account
+	err := a.keys.withKey(ctx, func(ctx context.Context) (err error) {
+		account, err = httpclient.As[Account](
+			a.client.GetJSON(ctx, "/api/users/me", map[string]string{}),
+		)
+		return err
+	})
+	if err != nil {
+		return Account{}, a.processError(err)
+	}
 
-		if hasErrors {
-			failedFiles = append(failedFiles, map[string]string{
-				"url":   imageUrl,
-				"error": "Failed to upload some chunks",
-			})
-			continue
+	return account, nil
+}
+
+// GetTaskStatus fetches a recipe task by its ID.
+//
+// A task created through this client is fetched with the API key that created it.
+//
+// Parameters:
+//   - ctx: Request context for cancellation and timeout
+//   - taskId: The unique identifier of the task
+//
+// Returns the RecipeTask including its current status, or an error if the
+// request fails.
+func (a *gaiaApi) GetTaskStatus(ctx context.Context, taskId string) (RecipeTask, error) {
+	var task RecipeTask
+	err := a.keys.withKey(a.keys.withTaskKey(ctx, taskId), func(ctx context.Context) (err error) {
+		task, err = httpclient.As[RecipeTask](
+			a.client.GetJSON(ctx, "/api/recipe/agi-tasks/"+url.PathEscape(taskId), map[string]string{}),
+		)
+		return err
+	})
+	if err != nil {
+		return RecipeTask{}, a.processError(err)
+	}
+
+	if IsTerminalTaskStatus(task.Status) {
+		a.keys.forgetTask(taskId)
+	}
+
+	return task, nil
+}
+
+// CancelTask asks the API to cancel a queued or running task.
+//
+// Parameters:
+//   - ctx: Request context for cancellation and timeout
+//   - taskId: The unique identifier of the task
+//
+// Returns an error if the request fails (e.g. the task already finished).
+// A task created through this client is cancelled with the API key that created it.
+func (a *gaiaApi) CancelTask(ctx context.Context, taskId string) error {
+	err := a.keys.withKey(a.keys.withTaskKey(ctx, taskId), func(ctx context.Context) error {
+		resp, err := a.client.POST(ctx, "/api/recipe/agi-tasks/"+url.PathEscape(taskId)+"/cancel", nil, map[string]string{})
+		if err != nil {
+			return err
 		}
+		defer resp.Body.Close()
 
-		// Convert to slice without nil pointers
-		var parts []UploadPart
-		for _, part := range uploadParts {
-			if part != nil {
-				parts = append(parts, *part)
+		// The cancel endpoint may reply without a body, so only inspect it on failure
+		if resp.StatusCode >= 400 {
+			body, _ := io.ReadAll(resp.Body)
+			return &httpclient.APIError{
+				StatusCode: resp.StatusCode,
+				Message:    string(body),
+				Headers:    resp.Header,
 			}
 		}
+		return nil
+	})
+	if err != nil {
+		return a.processError(err)
+	}
 
-		// Complete the upload
-		if err := a.completeUpload(ctx, initUploadResponse.Key, initUploadResponse.UploadId, parts); err != nil {
-			failedFiles = append(failedFiles, map[string]string{
-				"url":   imageUrl,
-				"error": err.Error(),
-			})
-			continue
-		}
+	a.keys.forgetTask(taskId)
+	return nil
+}
 
-		uploadedFiles = append(uploadedFiles, initUploadResponse.File)
+// DownloadImageBytes downloads a generated image from the GAIA CDN as-is.
+//
+// The URL must share the scheme and host of the configured CDN. The request is
+// sent through the API's HTTP client so it is authenticated and retried like
+// any other call.
+//
+// Parameters:
+//   - ctx: Request context for cancellation and timeout
+//   - imageUrl: The GAIA CDN URL of the image
+//
+// Returns the raw bytes and content type (from the response header, or
+// sniffed from the bytes when missing), or an error if validation or the
+// download fails.
+func (a *gaiaApi) DownloadImageBytes(ctx context.Context, imageUrl string) ([]byte, string, error) {
+	endpoint, err := a.cdnEndpoint(imageUrl)
+	if err != nil {
+		return nil, "", err
 	}
 
-	if len(failedFiles) > 0 {
-		return nil, fmt.Errorf("failed to upload some files: %v", failedFiles)
+	var resp *http.Response
+	err = a.keys.withKey(ctx, func(ctx context.Context) (err error) {
+		resp, err = a.cdnClient.GET(ctx, endpoint, map[string]string{
+			"Accept": "image/*,*/*",
+		})
+		return err
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to download image: %w", err)
 	}
+	defer resp.Body.Close()
 
-	return uploadedFiles, nil
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read image data: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, "", a.processError(&httpclient.APIError{
+			StatusCode: resp.StatusCode,
+			Message:    string(data),
+			Headers:    resp.Header,
+		})
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+
+	return data, contentType, nil
+}
+
+// cdnEndpoint validates that imageUrl points at the configured CDN and
+// returns its path and query relative to the CDN base URL.
+func (a *gaiaApi) cdnEndpoint(imageUrl string) (string, error) {
+	cdn, err := url.Parse(a.cdnUrl)
+	if err != nil {
+		return "", fmt.Errorf("invalid CDN url %q: %w", a.cdnUrl, err)
+	}
+
+	parsed, err := url.Parse(imageUrl)
+	if err != nil {
+		return "", fmt.Errorf("invalid image url %q: %w", imageUrl, err)
+	}
+
+	if parsed.Scheme != cdn.Scheme || parsed.Host != cdn.Host || !strings.HasPrefix(parsed.Path, cdn.Path+"/") {
+		return "", fmt.Errorf("image url must start with %s/", a.cdnUrl)
+	}
+
+	return strings.TrimPrefix(parsed.RequestURI(), cdn.Path), nil
 }
 
 // processImage downloads, processes, and extracts metadata from an image URL.
 //
 // This method performs several operations on the source image:
 //  1. Downloads the image from the provided URL using the imageutil package
-//  2. Processes it without resizing to maintain original quality
-//  3. Converts the base64-encoded image data to raw bytes
-//  4. Extracts image dimensions (width and height)
+//  2. Rotates JPEG photos upright according to their EXIF orientation, so
+//     pictures taken with a rotated phone are not stored sideways
+//  3. Re-encodes it without resizing to maintain original quality, which
+//     also drops the orientation tag now that the pixels are upright
+//  4. Extracts image dimensions (width and height) of the upright image
 //
 // The method is used internally by UploadImages to prepare image data
 // for multipart upload. It handles various image formats and ensures
@@ -346,12 +987,16 @@ func (a *gaiaApi) UploadImages(ctx context.Context, imageUrls []string, associat
 //   - h: Image height in pixels
 //   - err: Error if download, processing, or dimension extraction fails
 func (a *gaiaApi) processImage(ctx context.Context, imageUrl string) (imageData []byte, mimeType string, w, h int, err error) {
-	var base64Data string
+	// Fetch the image; the processor applies the EXIF orientation while decoding
+	img, format, err := a.processor.DownloadImage(ctx, imageUrl)
+	if err != nil {
+		return nil, "", 0, 0, fmt.Errorf("failed to process image: downloading image: %w", err)
+	}
+	img = a.processor.ApplyStages(img)
 
-	// Fetch the image
-	base64Data, mimeType, err = imageutil.ProcessImageNoResizeForMCP(ctx, imageUrl)
+	base64Data, mimeType, err := a.processor.EncodeImageToBase64Pure(img, format)
 	if err != nil {
-		return nil, "", 0, 0, fmt.Errorf("failed to process image: %w", err)
+		return nil, "", 0, 0, fmt.Errorf("failed to process image: encoding image: %w", err)
 	}
 
 	// Convert base64 data to bytes
@@ -360,13 +1005,9 @@ func (a *gaiaApi) processImage(ctx context.Context, imageUrl string) (imageData
 		return nil, "", 0, 0, fmt.Errorf("failed to decode base64 data: %w", err)
 	}
 
-	// Get dimensions of the image
-	w, h, err = imageutil.GetImageDimensions(ctx, imageUrl)
-	if err != nil {
-		return nil, "", 0, 0, fmt.Errorf("failed to get image dimensions: %w", err)
-	}
-
-	return imageData, mimeType, w, h, nil
+	// The dimensions of the upright image, after any EXIF rotation
+	bounds := img.Bounds()
+	return imageData, mimeType, bounds.Dx(), bounds.Dy(), nil
 }
 
 // initUploadImage initializes a multipart upload session for a single image.
@@ -386,6 +1027,8 @@ func (a *gaiaApi) processImage(ctx context.Context, imageUrl string) (imageData
 // Parameters:
 //   - ctx: Request context for cancellation and timeout control
 //   - imageData: Raw image bytes to be uploaded
+//   - mimeType: MIME type of the image, which also sets the filename extension.
+//     Defaults to "image/png" when empty.
 //   - w: Image width in pixels (for metadata)
 //   - h: Image height in pixels (for metadata)
 //   - associatedResource: Resource metadata linking this upload to a specific entity
@@ -396,15 +1039,20 @@ func (a *gaiaApi) processImage(ctx context.Context, imageUrl string) (imageData
 func (a *gaiaApi) initUploadImage(
 	ctx context.Context,
 	imageData []byte,
+	mimeType string,
 	w, h int,
 	associatedResource shared.FileAssociatedResource,
 ) (*InitUploadResponse, error) {
+	if mimeType == "" {
+		mimeType = "image/png"
+	}
+
 	// Prepare the request payload
 	payload := map[string]interface{}{
 		"files": []map[string]interface{}{
 			{
-				"filename": fmt.Sprintf("image_%d.png", time.Now().Unix()),
-				"mimetype": "image/png",
+				"filename": fmt.Sprintf("image_%d%s", time.Now().Unix(), imageutil.ExtensionForMimeType(mimeType)),
+				"mimetype": mimeType,
 				"metadata": map[string]int{
 					"width":  w,
 					"height": h,
@@ -417,9 +1065,13 @@ func (a *gaiaApi) initUploadImage(
 	}
 
 	// Send the request - the API returns an array of InitUploadResponse
-	initUploadResponses, err := httpclient.As[[]InitUploadResponse](
-		a.client.PostJSON(ctx, "/api/upload/initialize", payload, map[string]string{}),
-	)
+	var initUploadResponses []InitUploadResponse
+	err := a.keys.withKey(ctx, func(ctx context.Context) (err error) {
+		initUploadResponses, err = httpclient.As[[]InitUploadResponse](
+			a.client.PostJSON(ctx, "/api/upload/initialize", payload, map[string]string{}),
+		)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
@@ -432,18 +1084,63 @@ func (a *gaiaApi) initUploadImage(
 	return &initUploadResponses[0], nil
 }
 
+// uploadChunks uploads every chunk of an image concurrently.
+//
+// The chunks share a cancellable context: as soon as one chunk fails, the
+// in-flight uploads of the remaining chunks are cancelled since the image
+// can no longer be completed.
+//
+// Parameters:
+//   - ctx: Request context for cancellation and timeout
+//   - imageData: The full image bytes, split into UPLOAD_CHUNK_SIZE chunks
+//   - uploadUrls: The presigned URL for each chunk, in part order
+//   - progress: Receives the size of each uploaded chunk (may be nil)
+//
+// Returns the uploaded parts in part order, or the first chunk error.
+func (a *gaiaApi) uploadChunks(ctx context.Context, imageData []byte, uploadUrls []string, progress *uploadProgress) ([]UploadPart, error) {
+	g, gctx := errgroup.WithContext(ctx)
+	parts := make([]UploadPart, len(uploadUrls))
+
+	for i, url := range uploadUrls {
+		g.Go(func() error {
+			// Calculate the chunk boundaries
+			start := i * shared.UPLOAD_CHUNK_SIZE
+			end := start + shared.UPLOAD_CHUNK_SIZE
+			if end > len(imageData) {
+				end = len(imageData)
+			}
+
+			part, err := a.uploadChunk(gctx, imageData[start:end], url, i+1)
+			if err != nil {
+				// Expired URLs are recovered by re-initializing the upload
+				if !errors.Is(err, errPresignedUrlExpired) {
+					progress.fail()
+				}
+				return err
+			}
+			progress.advance(int64(end - start))
+
+			parts[i] = *part
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return parts, nil
+}
+
 // uploadChunk uploads a single data chunk directly to a presigned S3 URL.
 //
 // This method handles the actual upload of individual chunks in a multipart
 // upload process. It bypasses the regular API client to send data directly
 // to AWS S3 using presigned URLs for better performance and reduced server load.
 //
-// The method:
-//  1. Creates a direct HTTP PUT request to the presigned S3 URL
-//  2. Sets appropriate headers for S3 compatibility (Content-Type, Content-Length)
-//  3. Uses a dedicated HTTP client with extended timeout for large chunks
-//  4. Validates the upload response and extracts the required ETag
-//  5. Returns upload part information needed for multipart completion
+// Network errors and 5xx responses are retried with exponential backoff, up
+// to GaiaApiConfig.ChunkUploadAttempts attempts in total. 4xx responses, such
+// as an expired presigned URL, fail immediately.
 //
 // Parameters:
 //   - ctx: Request context for cancellation and timeout control
@@ -453,41 +1150,74 @@ func (a *gaiaApi) initUploadImage(
 //
 // Returns:
 //   - UploadPart: Contains ETag and part number required for upload completion
-//   - error: Error if HTTP request fails, upload is rejected, or ETag is missing
+//   - error: Error of the last attempt if the upload is rejected, every attempt
+//     fails, or the ETag is missing
 func (a *gaiaApi) uploadChunk(ctx context.Context, chunk []byte, url string, partNumber int) (*UploadPart, error) {
+	attempts := a.chunkUploadAttempts
+	if attempts <= 0 {
+		attempts = DefaultChunkUploadAttempts
+	}
+
+	for attempt := 1; ; attempt++ {
+		part, retryable, err := a.putChunk(ctx, chunk, url, partNumber)
+		if err == nil || !retryable || attempt >= attempts {
+			return part, err
+		}
+
+		if err := a.chunkBackoff.Wait(ctx, attempt); err != nil {
+			return nil, fmt.Errorf("chunk %d upload cancelled while waiting to retry: %w", partNumber, err)
+		}
+	}
+}
+
+// putChunk makes a single attempt at uploading a chunk to its presigned URL.
+// It reports whether a failure is worth retrying: network errors and 5xx
+// responses are, while rejected requests are not.
+func (a *gaiaApi) putChunk(ctx context.Context, chunk []byte, url string, partNumber int) (*UploadPart, bool, error) {
 	// Create a direct HTTP request to the presigned S3 URL
-	// Don't use a.client.PUT() because it prepends the base URL
+	// Don't use a.client.PUT() because it prepends the base URL.
+	// The body reader is created per attempt so a retry sends the whole chunk.
 	req, err := http.NewRequestWithContext(ctx, "PUT", url, bytes.NewReader(chunk))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request for chunk %d: %w", partNumber, err)
+		return nil, false, fmt.Errorf("failed to create request for chunk %d: %w", partNumber, err)
 	}
 
 	// Set required headers for S3 upload
 	req.Header.Set("Content-Type", "application/octet-stream")
 	req.Header.Set("Content-Length", fmt.Sprintf("%d", len(chunk)))
 
-	// Create a new HTTP client for direct S3 uploads
+	// Create a new HTTP client for direct S3 uploads, with a longer timeout
+	// than API requests since chunks can be large
+	timeout := a.uploadTimeout
+	if timeout <= 0 {
+		timeout = DefaultUploadTimeout
+	}
 	httpClient := &http.Client{
-		Timeout: 60 * time.Second, // Longer timeout for large uploads
+		Timeout: timeout,
 	}
 
 	// Execute the request
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to upload chunk %d: %w", partNumber, err)
+		// A cancelled upload must not be retried
+		return nil, ctx.Err() == nil, fmt.Errorf("failed to upload chunk %d: %w", partNumber, err)
 	}
 	defer resp.Body.Close()
 
 	// Check for successful upload (S3 returns 200 for successful chunk uploads)
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("chunk %d upload failed with status %d: %s", partNumber, resp.StatusCode, string(body))
+		if isExpiredPresignedUrl(resp.StatusCode, body) {
+			return nil, false, fmt.Errorf("chunk %d upload failed: %w", partNumber, errPresignedUrlExpired)
+		}
+		return nil, resp.StatusCode >= http.StatusInternalServerError,
+			fmt.Errorf("chunk %d upload failed with status %d: %s", partNumber, resp.StatusCode, string(body))
 	}
 
 	// Extract ETag from response headers (required for multipart upload completion)
 	etag := resp.Header.Get("ETag")
 	if etag == "" {
-		return nil, fmt.Errorf("missing ETag in response for chunk %d", partNumber)
+		return nil, false, fmt.Errorf("missing ETag in response for chunk %d", partNumber)
 	}
 
 	uploadPart := &UploadPart{
@@ -495,7 +1225,7 @@ func (a *gaiaApi) uploadChunk(ctx context.Context, chunk []byte, url string, par
 		PartNumber: partNumber,
 	}
 
-	return uploadPart, nil
+	return uploadPart, false, nil
 }
 
 // completeUpload finalizes a multipart upload by combining all uploaded chunks.
@@ -528,23 +1258,25 @@ func (a *gaiaApi) completeUpload(ctx context.Context, key, uploadId string, part
 		},
 	}
 
-	// Send the request
-	res, err := a.client.POST(ctx, "/api/upload/complete", payload, map[string]string{})
-	if err != nil {
-		return fmt.Errorf("failed to send request: %w", err)
-	}
-	defer res.Body.Close()
+	// Send the request with the key that initialized the upload
+	return a.keys.withKey(ctx, func(ctx context.Context) error {
+		res, err := a.client.POST(ctx, "/api/upload/complete", payload, map[string]string{})
+		if err != nil {
+			return fmt.Errorf("failed to send request: %w", err)
+		}
+		defer res.Body.Close()
 
-	// Read the response body for proper error handling
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read response body: %w", err)
-	}
+		// Read the response body for proper error handling
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read response body: %w", err)
+		}
 
-	// Check for successful completion
-	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated {
-		return fmt.Errorf("failed to complete upload (status %d): %s", res.StatusCode, string(body))
-	}
+		// Check for successful completion
+		if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated {
+			return fmt.Errorf("failed to complete upload (status %d): %s", res.StatusCode, string(body))
+		}
 
-	return nil
+		return nil
+	})
 }
@@ -2,13 +2,17 @@ package api
 
 import (
 	"context"
+	"errors"
 	"gaia-mcp-go/internal/testutil"
+	"gaia-mcp-go/pkg/httpclient"
 	"gaia-mcp-go/pkg/shared"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewGaiaApi(t *testing.T) {
@@ -43,6 +47,40 @@ func TestNewGaiaApi(t *testing.T) {
 	}
 }
 
+func TestNewGaiaApi_Timeout(t *testing.T) {
+	server := testutil.NewTestServer()
+	defer server.Close()
+
+	server.AddResponse("GET", "/api/users/me", testutil.MockResponse{
+		StatusCode: 200,
+		Body:       User{Uid: "user-123"},
+		Delay:      300 * time.Millisecond,
+	})
+
+	t.Run("Slow response times out", func(t *testing.T) {
+		client := NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key", Timeout: 50 * time.Millisecond})
+
+		// The deadline leaves no room for retries, so only the first attempt runs
+		ctx, cancel := context.WithTimeout(context.Background(), 250*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		_, err := client.WhoAmI(ctx)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Client.Timeout exceeded")
+		assert.Less(t, time.Since(start), 250*time.Millisecond)
+	})
+
+	t.Run("Response within the timeout", func(t *testing.T) {
+		client := NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key", Timeout: 5 * time.Second})
+
+		user, err := client.WhoAmI(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, "user-123", user.Uid)
+	})
+}
+
 func TestGaiaApi_CreateStyle(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -159,6 +197,183 @@ func TestGaiaApi_CreateStyle(t *testing.T) {
 	}
 }
 
+func TestGaiaApi_GetStyle(t *testing.T) {
+	t.Run("Returns the style with its images", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		server.AddResponse("GET", "/api/sd-styles/style-123", testutil.MockResponse{
+			StatusCode: 200,
+			Body: SdStyle{
+				Id:     "style-123",
+				Name:   "Watercolor",
+				Images: []SdStyleImage{{Url: "https://cdn.protogaia.com/a.png", Weight: 0.5}},
+			},
+		})
+
+		client := NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key"})
+		style, err := client.GetStyle(context.Background(), "style-123")
+
+		require.NoError(t, err)
+		assert.Equal(t, "Watercolor", style.Name)
+		assert.Equal(t, []SdStyleImage{{Url: "https://cdn.protogaia.com/a.png", Weight: 0.5}}, style.Images)
+	})
+
+	t.Run("Missing style", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		server.AddResponse("GET", "/api/sd-styles/missing", testutil.MockResponse{
+			StatusCode: 404,
+			Body:       map[string]interface{}{"message": "Style not found"},
+		})
+
+		client := NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key"})
+		_, err := client.GetStyle(context.Background(), "missing")
+
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrInvalidStyle))
+		assert.Contains(t, err.Error(), "The style ID is invalid or no longer exists")
+	})
+}
+
+func TestGaiaApi_ListStyles(t *testing.T) {
+	t.Run("Returns the requested page", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		server.AddResponse("GET", "/api/sd-styles", testutil.MockResponse{
+			StatusCode: 200,
+			Body: httpclient.PaginatedResponse[SdStyle]{
+				Data: []SdStyle{
+					{Id: "style-3", Name: "Ink"},
+					{Id: "style-4", Name: "Pastel"},
+				},
+				Page:       2,
+				PerPage:    2,
+				Total:      5,
+				TotalPages: 3,
+			},
+		})
+
+		client := NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key"})
+		styles, err := client.ListStyles(context.Background(), 2, 2)
+
+		require.NoError(t, err)
+		require.Len(t, styles.Data, 2)
+		assert.Equal(t, "style-3", styles.Data[0].Id)
+		assert.Equal(t, "Pastel", styles.Data[1].Name)
+		assert.Equal(t, 2, styles.Page)
+		assert.Equal(t, 5, styles.Total)
+		assert.Equal(t, 3, styles.TotalPages)
+
+		requests := server.Requests("GET", "/api/sd-styles")
+		require.Len(t, requests, 1)
+		assert.Equal(t, "2", requests[0].Query.Get("page"))
+		assert.Equal(t, "2", requests[0].Query.Get("per_page"))
+	})
+
+	t.Run("Defaults to the first page", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		server.AddResponse("GET", "/api/sd-styles", testutil.MockResponse{
+			StatusCode: 200,
+			Body:       httpclient.PaginatedResponse[SdStyle]{Page: 1},
+		})
+
+		client := NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key"})
+		_, err := client.ListStyles(context.Background(), 0, 0)
+
+		require.NoError(t, err)
+		requests := server.Requests("GET", "/api/sd-styles")
+		require.Len(t, requests, 1)
+		assert.Equal(t, "1", requests[0].Query.Get("page"))
+		assert.Equal(t, strconv.Itoa(DefaultStylesPerPage), requests[0].Query.Get("per_page"))
+	})
+
+	t.Run("Safe mode withholds flagged thumbnails", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		server.AddResponse("GET", "/api/sd-styles", testutil.MockResponse{
+			StatusCode: 200,
+			Body: httpclient.PaginatedResponse[SdStyle]{
+				Data: []SdStyle{
+					{Id: "style-1", ThumbnailUrl: "https://cdn.protogaia.com/safe.png", ThumbnailModerationRating: ThumbnailModerationSafe},
+					{Id: "style-2", ThumbnailUrl: "https://cdn.protogaia.com/unsafe.png", ThumbnailModerationRating: ThumbnailModerationUnsafe},
+				},
+			},
+		})
+
+		client := NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key", SafeMode: true})
+		styles, err := client.ListStyles(context.Background(), 1, 10)
+
+		require.NoError(t, err)
+		require.Len(t, styles.Data, 2)
+		assert.Equal(t, "https://cdn.protogaia.com/safe.png", styles.Data[0].ThumbnailUrl)
+		assert.Empty(t, styles.Data[1].ThumbnailUrl)
+	})
+}
+
+func TestGaiaApi_DeleteStyle(t *testing.T) {
+	t.Run("Deletes the style", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		server.AddResponse("DELETE", "/api/sd-styles/style-123", testutil.MockResponse{StatusCode: 204})
+
+		client := NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key"})
+		err := client.DeleteStyle(context.Background(), "style-123")
+
+		require.NoError(t, err)
+		assert.Len(t, server.Requests("DELETE", "/api/sd-styles/style-123"), 1)
+	})
+
+	t.Run("Accepts an empty success body", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		server.AddResponse("DELETE", "/api/sd-styles/style-123", testutil.MockResponse{StatusCode: 200, Body: ""})
+
+		client := NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key"})
+		err := client.DeleteStyle(context.Background(), "style-123")
+
+		require.NoError(t, err)
+	})
+
+	t.Run("Forbidden delete returns the server's reason", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		server.AddResponse("DELETE", "/api/sd-styles/style-123", testutil.MockResponse{
+			StatusCode: 403,
+			Body:       map[string]interface{}{"message": "Only the owner can delete this style"},
+		})
+
+		client := NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key"})
+		err := client.DeleteStyle(context.Background(), "style-123")
+
+		require.Error(t, err)
+		assert.Equal(t, "Style style-123 cannot be deleted: Only the owner can delete this style", err.Error())
+
+		var statusErr *APIStatusError
+		require.True(t, errors.As(err, &statusErr))
+		assert.Equal(t, 403, statusErr.StatusCode)
+
+		var apiErr *httpclient.APIError
+		require.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, "Only the owner can delete this style", apiErr.Message)
+	})
+
+	t.Run("Missing style", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		server.AddResponse("DELETE", "/api/sd-styles/missing", testutil.MockResponse{
+			StatusCode: 404,
+			Body:       map[string]interface{}{"message": "Style not found"},
+		})
+
+		client := NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key"})
+		err := client.DeleteStyle(context.Background(), "missing")
+
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrInvalidStyle))
+	})
+}
+
 func TestGaiaApi_GenerateImages(t *testing.T) {
 	tests := []struct {
 		name             string
@@ -189,22 +404,49 @@ func TestGaiaApi_GenerateImages(t *testing.T) {
 				Images:  []string{"image-url-1", "image-url-2"},
 			},
 		},
+		{
+			name: "Queue type and priority are reported",
+			request: GenerateImagesRequest{
+				RecipeId: shared.RecipeIdImageGeneratorSimple,
+				Params: map[string]interface{}{
+					"prompt": "A beautiful sunset",
+				},
+			},
+			mockResponse: testutil.MockResponse{
+				StatusCode: 200,
+				Body: map[string]interface{}{
+					"success":   true,
+					"images":    []string{"image-url-1"},
+					"taskId":    "task-123",
+					"queueType": "fast",
+					"priority":  5,
+					"status":    "COMPLETED",
+				},
+			},
+			expectedResponse: ImageGeneratedResponse{
+				Success:   true,
+				Images:    []string{"image-url-1"},
+				TaskId:    "task-123",
+				QueueType: shared.QueueTypeFast,
+				Priority:  5,
+			},
+		},
 		{
 			name: "Invalid request parameters",
 			request: GenerateImagesRequest{
 				RecipeId: shared.RecipeIdImageGeneratorSimple,
 				Params: map[string]interface{}{
-					"prompt": "", // Empty prompt should cause error
+					"prompt": "Test prompt",
 				},
 			},
 			mockResponse: testutil.MockResponse{
 				StatusCode: 400,
 				Body: map[string]interface{}{
-					"error": "Prompt cannot be empty",
-					"code":  "INVALID_PROMPT",
+					"error": "Style not found",
+					"code":  "INVALID_STYLE",
 				},
 			},
-			expectedError: "Prompt cannot be empty",
+			expectedError: "The style ID is invalid or no longer exists",
 		},
 		{
 			name: "API rate limit exceeded",
@@ -223,6 +465,20 @@ func TestGaiaApi_GenerateImages(t *testing.T) {
 			},
 			expectedError: "Rate limit exceeded",
 		},
+		{
+			name: "Empty response body",
+			request: GenerateImagesRequest{
+				RecipeId: shared.RecipeIdImageGeneratorSimple,
+				Params: map[string]interface{}{
+					"prompt": "Test prompt",
+				},
+			},
+			mockResponse: testutil.MockResponse{
+				StatusCode: 200,
+				Body:       "",
+			},
+			expectedError: "failed to unmarshal response (status 200)",
+		},
 	}
 
 	for _, tt := range tests {
@@ -307,6 +563,168 @@ func TestGaiaApi_UploadImages(t *testing.T) {
 	}
 }
 
+func TestGaiaApi_WhoAmI(t *testing.T) {
+	tests := []struct {
+		name          string
+		mockResponse  testutil.MockResponse
+		expectedUser  User
+		expectedError string
+	}{
+		{
+			name: "Valid API key",
+			mockResponse: testutil.MockResponse{
+				StatusCode: 200,
+				Body: User{
+					Uid:      "user-123",
+					Name:     "Test User",
+					Email:    "test@example.com",
+					Username: "testuser",
+				},
+			},
+			expectedUser: User{
+				Uid:      "user-123",
+				Name:     "Test User",
+				Email:    "test@example.com",
+				Username: "testuser",
+			},
+		},
+		{
+			name: "Unauthorized API key",
+			mockResponse: testutil.MockResponse{
+				StatusCode: 401,
+				Body: map[string]interface{}{
+					"message": "Unauthorized",
+				},
+			},
+			expectedError: "API key is invalid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup test server
+			server := testutil.NewTestServer()
+			defer server.Close()
+
+			// Configure mock response
+			server.AddResponse("GET", "/api/users/me", tt.mockResponse)
+
+			// Create API client
+			client := NewGaiaApi(GaiaApiConfig{
+				BaseUrl: server.URL,
+				ApiKey:  "test-key",
+			})
+
+			// Execute test
+			user, err := client.WhoAmI(context.Background())
+
+			// Verify results
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				assert.Empty(t, user.Uid)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedUser, user)
+			}
+		})
+	}
+}
+
+func TestGaiaApi_GetTaskStatus(t *testing.T) {
+	taskError := "Out of memory"
+
+	tests := []struct {
+		name           string
+		mockResponse   testutil.MockResponse
+		expectedStatus shared.RecipeTaskStatus
+		expectedImages int
+		expectedError  string
+	}{
+		{
+			name: "Queued task",
+			mockResponse: testutil.MockResponse{
+				StatusCode: 200,
+				Body:       map[string]interface{}{"id": "task-1", "status": "QUEUED", "images": []interface{}{}},
+			},
+			expectedStatus: shared.RecipeTaskStatusQueued,
+		},
+		{
+			name: "Running task",
+			mockResponse: testutil.MockResponse{
+				StatusCode: 200,
+				Body:       map[string]interface{}{"id": "task-1", "status": "RUNNING", "startedAt": "2024-01-01T00:00:00Z"},
+			},
+			expectedStatus: shared.RecipeTaskStatusRunning,
+		},
+		{
+			name: "Completed task",
+			mockResponse: testutil.MockResponse{
+				StatusCode: 200,
+				Body: map[string]interface{}{
+					"id":          "task-1",
+					"status":      "COMPLETED",
+					"completedAt": "2024-01-01T00:01:00Z",
+					"images":      []interface{}{map[string]interface{}{"id": "image-1", "url": "https://cdn.protogaia.com/image-1.png"}},
+				},
+			},
+			expectedStatus: shared.RecipeTaskStatusCompleted,
+			expectedImages: 1,
+		},
+		{
+			name: "Failed task",
+			mockResponse: testutil.MockResponse{
+				StatusCode: 200,
+				Body:       map[string]interface{}{"id": "task-1", "status": "FAILED", "error": taskError},
+			},
+			expectedStatus: shared.RecipeTaskStatusFailed,
+		},
+		{
+			name: "Unauthorized API key",
+			mockResponse: testutil.MockResponse{
+				StatusCode: 401,
+				Body:       map[string]interface{}{"message": "Unauthorized"},
+			},
+			expectedError: "API key is invalid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup test server
+			server := testutil.NewTestServer()
+			defer server.Close()
+
+			server.AddResponse("GET", "/api/recipe/agi-tasks/task-1", tt.mockResponse)
+
+			// Create API client
+			client := NewGaiaApi(GaiaApiConfig{
+				BaseUrl: server.URL,
+				ApiKey:  "test-key",
+			})
+
+			// Execute test
+			task, err := client.GetTaskStatus(context.Background(), "task-1")
+
+			// Verify results
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "task-1", task.Id)
+			assert.Equal(t, tt.expectedStatus, task.Status)
+			assert.Len(t, task.Images, tt.expectedImages)
+			if tt.expectedStatus == shared.RecipeTaskStatusFailed {
+				require.NotNil(t, task.Error)
+				assert.Equal(t, taskError, *task.Error)
+			}
+		})
+	}
+}
+
 // Benchmark tests for performance monitoring
 func BenchmarkGaiaApi_CreateStyle(b *testing.B) {
 	server := testutil.NewTestServer()
@@ -423,3 +841,204 @@ func TestGaiaApiConfig_Validation(t *testing.T) {
 		})
 	}
 }
+
+func TestGaiaApi_GenerateImages_IdempotencyKey(t *testing.T) {
+	const createTaskPath = "/api/recipe/agi-tasks/create-task"
+
+	successResponse := testutil.MockResponse{
+		StatusCode: 200,
+		Body:       ImageGeneratedResponse{Success: true, Images: []string{"image-url-1"}},
+	}
+
+	t.Run("Provided key is sent", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		server.AddResponse("POST", createTaskPath, successResponse)
+
+		api := NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key"})
+		_, err := api.GenerateImages(context.Background(), GenerateImagesRequest{
+			RecipeId:       shared.RecipeIdImageGeneratorSimple,
+			Params:         map[string]interface{}{"prompt": "A fox"},
+			IdempotencyKey: "my-key",
+		})
+		require.NoError(t, err)
+
+		requests := server.Requests("POST", createTaskPath)
+		require.Len(t, requests, 1)
+		assert.Equal(t, "my-key", requests[0].Headers.Get(IdempotencyKeyHeader))
+		assert.NotContains(t, string(requests[0].Body), "my-key")
+	})
+
+	t.Run("Generated key differs per logical request", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		server.AddResponse("POST", createTaskPath, successResponse)
+
+		api := NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key"})
+		for i := 0; i < 2; i++ {
+			_, err := api.GenerateImages(context.Background(), GenerateImagesRequest{RecipeId: shared.RecipeIdImageGeneratorSimple, Params: map[string]interface{}{"prompt": "a cat"}})
+			require.NoError(t, err)
+		}
+
+		requests := server.Requests("POST", createTaskPath)
+		require.Len(t, requests, 2)
+		assert.NotEmpty(t, requests[0].Headers.Get(IdempotencyKeyHeader))
+		assert.NotEqual(t, requests[0].Headers.Get(IdempotencyKeyHeader), requests[1].Headers.Get(IdempotencyKeyHeader))
+	})
+
+	t.Run("Generated key is stable across retries", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		server.AddResponseSequence("POST", createTaskPath,
+			testutil.MockResponse{StatusCode: 503, Body: map[string]interface{}{"message": "unavailable"}},
+			successResponse,
+		)
+
+		api := NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key"})
+		_, err := api.GenerateImages(context.Background(), GenerateImagesRequest{RecipeId: shared.RecipeIdImageGeneratorSimple, Params: map[string]interface{}{"prompt": "a cat"}})
+		require.NoError(t, err)
+
+		requests := server.Requests("POST", createTaskPath)
+		require.Len(t, requests, 2)
+		key := requests[0].Headers.Get(IdempotencyKeyHeader)
+		assert.NotEmpty(t, key)
+		assert.Equal(t, key, requests[1].Headers.Get(IdempotencyKeyHeader))
+	})
+}
+
+func TestGaiaApi_DownloadImageBytes(t *testing.T) {
+	// Arbitrary bytes that must not be decoded or re-encoded
+	original := append(testutil.CreateMockImage(), 0x00, 0xFF, 0x10)
+
+	t.Run("Returns the exact bytes and content type", func(t *testing.T) {
+		cdn := testutil.NewTestServer()
+		defer cdn.Close()
+		cdn.AddResponse("GET", "/images/result.png", testutil.MockResponse{
+			StatusCode: 200,
+			Body:       original,
+			Headers:    map[string]string{"Content-Type": "image/png"},
+		})
+
+		api := NewGaiaApi(GaiaApiConfig{BaseUrl: cdn.URL, ApiKey: "test-key", CdnUrl: cdn.URL})
+		data, contentType, err := api.DownloadImageBytes(context.Background(), cdn.URL+"/images/result.png")
+
+		require.NoError(t, err)
+		assert.Equal(t, original, data)
+		assert.Equal(t, "image/png", contentType)
+
+		requests := cdn.Requests("GET", "/images/result.png")
+		require.Len(t, requests, 1)
+		assert.Equal(t, "Bearer test-key", requests[0].Headers.Get("Authorization"))
+	})
+
+	t.Run("Rejects non-GAIA hosts", func(t *testing.T) {
+		cdn := testutil.NewTestServer()
+		defer cdn.Close()
+
+		api := NewGaiaApi(GaiaApiConfig{BaseUrl: cdn.URL, ApiKey: "test-key", CdnUrl: cdn.URL})
+		_, _, err := api.DownloadImageBytes(context.Background(), "https://example.com/images/result.png")
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "must start with")
+		assert.Empty(t, cdn.Requests("GET", "/images/result.png"))
+	})
+
+	t.Run("Defaults to the GAIA CDN", func(t *testing.T) {
+		api := NewGaiaApi(GaiaApiConfig{BaseUrl: "http://localhost", ApiKey: "test-key"})
+		_, _, err := api.DownloadImageBytes(context.Background(), "https://cdn.protogaia.com.evil.com/x.png")
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), shared.CDN_URL)
+	})
+
+	t.Run("Reports missing images", func(t *testing.T) {
+		cdn := testutil.NewTestServer()
+		defer cdn.Close()
+		cdn.AddResponse("GET", "/images/missing.png", testutil.MockResponse{StatusCode: 404, Body: "Not Found"})
+
+		api := NewGaiaApi(GaiaApiConfig{BaseUrl: cdn.URL, ApiKey: "test-key", CdnUrl: cdn.URL})
+		_, _, err := api.DownloadImageBytes(context.Background(), cdn.URL+"/images/missing.png")
+
+		var apiErr *httpclient.APIError
+		require.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, 404, apiErr.StatusCode)
+	})
+}
+
+func TestGaiaApi_GenerateImages_SafeMode(t *testing.T) {
+	tests := []struct {
+		name            string
+		safeMode        bool
+		ratings         []ThumbnailModerationRating
+		expectedImages  []string
+		expectedOrigins []string
+		expectUnsafe    bool
+	}{
+		{
+			name:            "Safe mode disabled keeps flagged images",
+			ratings:         []ThumbnailModerationRating{ThumbnailModerationUnsafe, ThumbnailModerationSafe},
+			expectedImages:  []string{"image-1", "image-2"},
+			expectedOrigins: []string{"origin-1", "origin-2"},
+		},
+		{
+			name:            "Safe mode withholds flagged images",
+			safeMode:        true,
+			ratings:         []ThumbnailModerationRating{ThumbnailModerationSensitive, ThumbnailModerationSafe},
+			expectedImages:  []string{"image-2"},
+			expectedOrigins: []string{"origin-2"},
+		},
+		{
+			name:         "Safe mode rejects when every image is flagged",
+			safeMode:     true,
+			ratings:      []ThumbnailModerationRating{ThumbnailModerationUnsafe, ThumbnailModerationSensitive},
+			expectUnsafe: true,
+		},
+		{
+			name:            "Safe mode keeps unrated images",
+			safeMode:        true,
+			expectedImages:  []string{"image-1", "image-2"},
+			expectedOrigins: []string{"origin-1", "origin-2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := testutil.NewTestServer()
+			defer server.Close()
+			server.AddResponse("POST", "/api/recipe/agi-tasks/create-task", testutil.MockResponse{
+				StatusCode: 200,
+				Body: ImageGeneratedResponse{
+					Success:           true,
+					Images:            []string{"image-1", "image-2"},
+					OriginImages:      []string{"origin-1", "origin-2"},
+					ModerationRatings: tt.ratings,
+				},
+			})
+
+			api := NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key", SafeMode: tt.safeMode})
+			res, err := api.GenerateImages(context.Background(), GenerateImagesRequest{RecipeId: shared.RecipeIdImageGeneratorSimple, Params: map[string]interface{}{"prompt": "a cat"}})
+
+			if tt.expectUnsafe {
+				assert.ErrorIs(t, err, ErrUnsafeContent)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedImages, res.Images)
+			assert.Equal(t, tt.expectedOrigins, res.OriginImages)
+		})
+	}
+}
+
+func TestImageGeneratedResponse_Fallbacks(t *testing.T) {
+	res := ImageGeneratedResponse{
+		Images:       []string{"cdn-1", "cdn-2", "cdn-3"},
+		OriginImages: []string{"origin-1", "cdn-2"},
+	}
+
+	assert.Equal(t, []string{"origin-1"}, res.Fallbacks(0))
+	assert.Nil(t, res.Fallbacks(1), "an origin equal to the primary url is not a fallback")
+	assert.Nil(t, res.Fallbacks(2), "images without an origin have no fallback")
+	assert.Nil(t, res.Fallbacks(-1))
+	assert.Nil(t, ImageGeneratedResponse{Images: []string{"cdn-1"}}.Fallbacks(0))
+}
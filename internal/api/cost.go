@@ -0,0 +1,192 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gaia-mcp-go/pkg/shared"
+)
+
+// DefaultCreditsPerImage is the estimated cost of one output image of a recipe
+// without a configured cost. The GAIA API does not publish its prices, so the
+// estimate is the minimum any generation costs.
+const DefaultCreditsPerImage = 1
+
+// CreditCosts configures the estimated credit cost of the calls that spend
+// credits. The zero value charges DefaultCreditsPerImage per generated image
+// and per created style, and treats uploads as free.
+type CreditCosts struct {
+	// PerImage is the cost of one output image per recipe. Recipes not listed
+	// cost DefaultCreditsPerImage.
+	PerImage map[shared.RecipeId]int
+	// PerStyle is the cost of creating a style. Zero uses DefaultCreditsPerImage.
+	PerStyle int
+	// PerUpload is the cost of uploading one image. Zero means uploads are free
+	// and are not checked.
+	PerUpload int
+}
+
+// Generation estimates the credits a generation request will consume: the
+// recipe's per-image cost multiplied by numberOfImages (default 1)
+func (c CreditCosts) Generation(req GenerateImagesRequest) int {
+	perImage, ok := c.PerImage[req.RecipeId]
+	if !ok || perImage <= 0 {
+		perImage = DefaultCreditsPerImage
+	}
+
+	images := 1
+	if count, ok := numberParam(req.Params, "numberOfImages"); ok && count >= 1 {
+		images = int(count)
+	}
+
+	return perImage * images
+}
+
+// Style estimates the credits creating a style will consume
+func (c CreditCosts) Style() int {
+	if c.PerStyle <= 0 {
+		return DefaultCreditsPerImage
+	}
+	return c.PerStyle
+}
+
+// Upload estimates the credits uploading the given number of images will consume
+func (c CreditCosts) Upload(images int) int {
+	if c.PerUpload <= 0 {
+		return 0
+	}
+	return c.PerUpload * images
+}
+
+// ErrInsufficientCredits is matched (via errors.Is) by every InsufficientCreditsError
+var ErrInsufficientCredits = errors.New("insufficient credits")
+
+// InsufficientCreditsError is returned when the estimated cost of a call
+// exceeds the account's credit balance, before the call is made
+type InsufficientCreditsError struct {
+	// Required is the estimated cost of the call
+	Required int
+	// Available is the account's credit balance
+	Available int
+	// HomepageUrl is the GAIA web app linked to buy credits. Empty uses shared.HOMEPAGE_URL.
+	HomepageUrl string
+}
+
+// Error implements the error interface for InsufficientCreditsError
+func (e *InsufficientCreditsError) Error() string {
+	homepageUrl := e.HomepageUrl
+	if homepageUrl == "" {
+		homepageUrl = shared.HOMEPAGE_URL
+	}
+
+	return fmt.Sprintf(
+		"%s (estimated cost: %d, balance: %d)",
+		ErrorResponses(homepageUrl)[ErrorKeyWordCreditsExhausted], e.Required, e.Available,
+	)
+}
+
+// Is reports whether the target is ErrInsufficientCredits
+func (e *InsufficientCreditsError) Is(target error) bool {
+	return target == ErrInsufficientCredits
+}
+
+// EstimateCost estimates the credits a generation request will consume.
+//
+// Without published prices, the estimate is the minimum of
+// DefaultCreditsPerImage per output image. A CostGuard estimates with its
+// configured CreditCosts instead.
+//
+// Parameters:
+//   - ctx: Request context, unused by the local estimator
+//   - req: The generation request to estimate
+//
+// Returns the estimated credit cost.
+func (a *gaiaApi) EstimateCost(ctx context.Context, req GenerateImagesRequest) (int, error) {
+	return CreditCosts{}.Generation(req), nil
+}
+
+// numberParam reads a numeric recipe param, accepting both Go and JSON number types
+func numberParam(params map[string]interface{}, key string) (float64, bool) {
+	switch v := params[key].(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+// CostGuard wraps a GaiaApi and checks the account balance against the
+// estimated cost before every call that spends credits (generations, style
+// creation and, when they have a cost, uploads), so calls that would fail for
+// lack of credits are rejected without being made. With several API keys, the
+// balance is checked with the key the call is then made with.
+type CostGuard struct {
+	GaiaApi
+	costs       CreditCosts
+	homepageUrl string
+}
+
+// NewCostGuard creates a CostGuard around the given API client, estimating
+// with the given costs. The homepage is linked from InsufficientCreditsError
+// messages; empty uses shared.HOMEPAGE_URL.
+func NewCostGuard(api GaiaApi, costs CreditCosts, homepageUrl string) *CostGuard {
+	return &CostGuard{GaiaApi: api, costs: costs, homepageUrl: homepageUrl}
+}
+
+// EstimateCost estimates the credits a generation request will consume with the configured costs
+func (g *CostGuard) EstimateCost(ctx context.Context, req GenerateImagesRequest) (int, error) {
+	return g.costs.Generation(req), nil
+}
+
+// GenerateImages submits the task only when the balance covers its estimated cost.
+// It returns an *InsufficientCreditsError otherwise.
+func (g *CostGuard) GenerateImages(ctx context.Context, req GenerateImagesRequest) (ImageGeneratedResponse, error) {
+	ctx = withKeySession(ctx)
+	if err := g.checkBalance(ctx, g.costs.Generation(req)); err != nil {
+		return ImageGeneratedResponse{}, err
+	}
+	return g.GaiaApi.GenerateImages(ctx, req)
+}
+
+// CreateStyle creates the style only when the balance covers its estimated cost.
+// It returns an *InsufficientCreditsError otherwise.
+func (g *CostGuard) CreateStyle(ctx context.Context, imageUrls []string, name string, description *string, opts ...CreateStyleOptions) (SdStyle, error) {
+	ctx = withKeySession(ctx)
+	if err := g.checkBalance(ctx, g.costs.Style()); err != nil {
+		return SdStyle{}, err
+	}
+	return g.GaiaApi.CreateStyle(ctx, imageUrls, name, description, opts...)
+}
+
+// UploadImages uploads the images only when the balance covers their estimated
+// cost. Uploads without a configured cost are not checked.
+// It returns an *InsufficientCreditsError otherwise.
+func (g *CostGuard) UploadImages(ctx context.Context, imageUrls []string, associatedResource shared.FileAssociatedResource, opts ...UploadOptions) ([]UploadFile, error) {
+	ctx = withKeySession(ctx)
+	if err := g.checkBalance(ctx, g.costs.Upload(len(imageUrls))); err != nil {
+		return nil, err
+	}
+	return g.GaiaApi.UploadImages(ctx, imageUrls, associatedResource, opts...)
+}
+
+// checkBalance returns an *InsufficientCreditsError when the credit balance is
+// below cost. A zero cost is not checked.
+func (g *CostGuard) checkBalance(ctx context.Context, cost int) error {
+	if cost <= 0 {
+		return nil
+	}
+
+	account, err := g.GetAccount(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check credit balance: %w", err)
+	}
+
+	if account.Credits < cost {
+		return &InsufficientCreditsError{Required: cost, Available: account.Credits, HomepageUrl: g.homepageUrl}
+	}
+	return nil
+}
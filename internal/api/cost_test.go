@@ -0,0 +1,187 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"gaia-mcp-go/internal/testutil"
+	"gaia-mcp-go/pkg/shared"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreditCosts(t *testing.T) {
+	costs := CreditCosts{PerImage: map[shared.RecipeId]int{shared.RecipeIdImageGeneratorSimple: 3}}
+
+	tests := []struct {
+		name     string
+		costs    CreditCosts
+		req      GenerateImagesRequest
+		expected int
+	}{
+		{
+			name:     "Default is one credit per image",
+			req:      GenerateImagesRequest{RecipeId: shared.RecipeIdImageGeneratorSimple, Params: map[string]interface{}{"numberOfImages": 1}},
+			expected: 1,
+		},
+		{
+			name:     "Several images from JSON params",
+			req:      GenerateImagesRequest{RecipeId: shared.RecipeIdImageGeneratorSimple, Params: map[string]interface{}{"numberOfImages": float64(4)}},
+			expected: 4,
+		},
+		{
+			name:     "Missing numberOfImages counts one image",
+			req:      GenerateImagesRequest{RecipeId: shared.RecipeIdRemix},
+			expected: 1,
+		},
+		{
+			name:     "Configured recipe cost",
+			costs:    costs,
+			req:      GenerateImagesRequest{RecipeId: shared.RecipeIdImageGeneratorSimple, Params: map[string]interface{}{"numberOfImages": 2}},
+			expected: 6,
+		},
+		{
+			name:     "Recipes without a configured cost use the default",
+			costs:    costs,
+			req:      GenerateImagesRequest{RecipeId: shared.RecipeIdUpscaler},
+			expected: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.costs.Generation(tt.req))
+		})
+	}
+
+	t.Run("Styles cost the minimum by default", func(t *testing.T) {
+		assert.Equal(t, DefaultCreditsPerImage, CreditCosts{}.Style())
+		assert.Equal(t, 5, CreditCosts{PerStyle: 5}.Style())
+	})
+
+	t.Run("Uploads are free by default", func(t *testing.T) {
+		assert.Zero(t, CreditCosts{}.Upload(3))
+		assert.Equal(t, 6, CreditCosts{PerUpload: 2}.Upload(3))
+	})
+}
+
+func TestGaiaApi_EstimateCost(t *testing.T) {
+	api := NewGaiaApi(GaiaApiConfig{BaseUrl: "http://localhost", ApiKey: "test-key"})
+
+	cost, err := api.EstimateCost(context.Background(), GenerateImagesRequest{
+		RecipeId: "unknown",
+		Params:   map[string]interface{}{"numberOfImages": 2},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2*DefaultCreditsPerImage, cost)
+}
+
+func TestCostGuard(t *testing.T) {
+	const (
+		accountPath    = "/api/users/me"
+		createTaskPath = "/api/recipe/agi-tasks/create-task"
+		stylesPath     = "/api/sd-styles"
+	)
+	costs := CreditCosts{PerImage: map[shared.RecipeId]int{shared.RecipeIdImageGeneratorSimple: 2}}
+	req := GenerateImagesRequest{
+		RecipeId: shared.RecipeIdImageGeneratorSimple,
+		Params:   map[string]interface{}{"prompt": "a cat", "numberOfImages": 2},
+	}
+
+	t.Run("Submits when the balance covers the cost", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		server.AddResponse("GET", accountPath, testutil.MockResponse{StatusCode: 200, Body: Account{Credits: 4}})
+		server.AddResponse("POST", createTaskPath, testutil.MockResponse{
+			StatusCode: 200,
+			Body:       ImageGeneratedResponse{Success: true, Images: []string{"image-url"}},
+		})
+
+		guard := NewCostGuard(NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key"}), costs, "")
+		res, err := guard.GenerateImages(context.Background(), req)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"image-url"}, res.Images)
+		assert.Len(t, server.Requests("POST", createTaskPath), 1)
+	})
+
+	t.Run("Short-circuits when the balance is insufficient", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		server.AddResponse("GET", accountPath, testutil.MockResponse{StatusCode: 200, Body: Account{Credits: 3}})
+
+		guard := NewCostGuard(NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key"}), costs, "")
+		_, err := guard.GenerateImages(context.Background(), req)
+
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrInsufficientCredits))
+		var creditsErr *InsufficientCreditsError
+		require.ErrorAs(t, err, &creditsErr)
+		assert.Equal(t, 4, creditsErr.Required)
+		assert.Equal(t, 3, creditsErr.Available)
+		assert.Contains(t, err.Error(), "GAIA CREDITS balance is not enough")
+		assert.Contains(t, err.Error(), shared.HOMEPAGE_URL+"/settings/account")
+		assert.Empty(t, server.Requests("POST", createTaskPath))
+	})
+
+	t.Run("Links the configured homepage", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		server.AddResponse("GET", accountPath, testutil.MockResponse{StatusCode: 200, Body: Account{Credits: 0}})
+
+		guard := NewCostGuard(NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key"}), costs, "https://staging.protogaia.com")
+		_, err := guard.GenerateImages(context.Background(), req)
+
+		assert.ErrorContains(t, err, "https://staging.protogaia.com/settings/account?tab=Plans&plan=credits")
+	})
+
+	t.Run("Style creation is checked", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		server.AddResponse("GET", accountPath, testutil.MockResponse{StatusCode: 200, Body: Account{Credits: 0}})
+
+		guard := NewCostGuard(NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key"}), CreditCosts{}, "")
+		_, err := guard.CreateStyle(context.Background(), []string{"https://cdn.protogaia.com/a.png"}, "Watercolor", nil)
+
+		assert.ErrorIs(t, err, ErrInsufficientCredits)
+		assert.Empty(t, server.Requests("POST", stylesPath))
+	})
+
+	t.Run("Uploads are checked when they have a cost", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		server.AddResponse("GET", accountPath, testutil.MockResponse{StatusCode: 200, Body: Account{Credits: 1}})
+
+		guard := NewCostGuard(NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key"}), CreditCosts{PerUpload: 1}, "")
+		_, err := guard.UploadImages(context.Background(), []string{"https://cdn.protogaia.com/a.png", "https://cdn.protogaia.com/b.png"}, shared.FileAssociatedResourceWorkspace)
+
+		var creditsErr *InsufficientCreditsError
+		require.ErrorAs(t, err, &creditsErr)
+		assert.Equal(t, 2, creditsErr.Required)
+	})
+
+	t.Run("Free uploads skip the balance check", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		guard := NewCostGuard(NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key"}), CreditCosts{}, "")
+		_, err := guard.UploadImages(context.Background(), []string{"not-a-url"}, shared.FileAssociatedResourceWorkspace)
+
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrInsufficientCredits)
+		assert.Empty(t, server.Requests("GET", accountPath))
+	})
+
+	t.Run("Balance check failure does not submit", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		server.AddResponse("GET", accountPath, testutil.MockResponse{StatusCode: 404, Body: map[string]string{"message": "not found"}})
+
+		guard := NewCostGuard(NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key"}), costs, "")
+		_, err := guard.GenerateImages(context.Background(), req)
+
+		assert.ErrorContains(t, err, "failed to check credit balance")
+		assert.Empty(t, server.Requests("POST", createTaskPath))
+	})
+}
@@ -1,10 +1,13 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"gaia-mcp-go/pkg/httpclient"
 	"gaia-mcp-go/pkg/shared"
+	"net/http"
 	"strings"
+	"time"
 )
 
 // ErrorKeyWord represents the error key words
@@ -13,32 +16,232 @@ type ErrorKeyWord string
 const (
 	ErrorKeyWordSubscriptionEnded ErrorKeyWord = "your subscription has ended"
 	ErrorKeyWordCreditsExhausted  ErrorKeyWord = "no available credits"
+	ErrorKeyWordContentRejected   ErrorKeyWord = "content moderation"
+	ErrorKeyWordInvalidStyle      ErrorKeyWord = "invalid style"
+	ErrorKeyWordStyleNotFound     ErrorKeyWord = "style not found"
 )
 
+// errorKeyWords lists the key words in the order they are matched, so a
+// message containing several is reported as the first one
+var errorKeyWords = []ErrorKeyWord{
+	ErrorKeyWordSubscriptionEnded,
+	ErrorKeyWordCreditsExhausted,
+	ErrorKeyWordContentRejected,
+	ErrorKeyWordInvalidStyle,
+	ErrorKeyWordStyleNotFound,
+}
+
+// invalidStyleFormat is the error message of both invalid style key words
+const invalidStyleFormat = "The style ID is invalid or no longer exists. Please use a style ID returned by create_style or upload_and_create_style, or check your styles here: %s"
+
+// errorResponseFormats holds the error messages per key word, with a %s
+// placeholder for the homepage URL
+var errorResponseFormats = map[ErrorKeyWord]string{
+	ErrorKeyWordSubscriptionEnded: "Your subscription has ended. Please update to access features here: %s/settings/account?tab=Plans&plan=subscription",
+	ErrorKeyWordCreditsExhausted:  "Your GAIA CREDITS balance is not enough to generate images for this task. Please buy more CREDITS here: %s/settings/account?tab=Plans&plan=credits",
+	ErrorKeyWordContentRejected:   "Your request was rejected by content moderation. Please rephrase the prompt or use a different image, then try again. Content rules apply to every generation on %s",
+	ErrorKeyWordInvalidStyle:      invalidStyleFormat,
+	ErrorKeyWordStyleNotFound:     invalidStyleFormat,
+}
+
+// statusErrorResponseFormats holds the error messages per HTTP status code,
+// with a %s placeholder for the homepage URL
+var statusErrorResponseFormats = map[int]string{
+	http.StatusUnauthorized: "Your API key is invalid or has expired. Please create a new API key here: %s/settings/account?tab=Security",
+	http.StatusForbidden:    "Your API key lacks permission for this action. Please check your account access here: %s/settings/account",
+}
+
 // ErrorResponseMap maps the error key words to the error messages
-var ErrorResponseMap = map[ErrorKeyWord]string{
-	ErrorKeyWordSubscriptionEnded: fmt.Sprintf(
-		"Your subscription has ended. Please update to access features here: %s/settings/account?tab=Plans&plan=subscription",
-		shared.HOMEPAGE_URL,
-	),
-	ErrorKeyWordCreditsExhausted: fmt.Sprintf(
-		"Your GAIA CREDITS balance is not enough to generate images for this task. Please buy more CREDITS here: %s/settings/account?tab=Plans&plan=credits",
-		shared.HOMEPAGE_URL,
-	),
+var ErrorResponseMap = ErrorResponses(shared.HOMEPAGE_URL)
+
+// StatusErrorResponseMap maps HTTP status codes to user-friendly error messages
+var StatusErrorResponseMap = StatusErrorResponses(shared.HOMEPAGE_URL)
+
+// ErrorResponses returns the error messages per key word, linking to the given homepage
+func ErrorResponses(homepageUrl string) map[ErrorKeyWord]string {
+	messages := make(map[ErrorKeyWord]string, len(errorResponseFormats))
+	for keyWord, format := range errorResponseFormats {
+		messages[keyWord] = fmt.Sprintf(format, homepageUrl)
+	}
+	return messages
+}
+
+// StatusErrorResponses returns the error messages per HTTP status code, linking to the given homepage
+func StatusErrorResponses(homepageUrl string) map[int]string {
+	messages := make(map[int]string, len(statusErrorResponseFormats))
+	for statusCode, format := range statusErrorResponseFormats {
+		messages[statusCode] = fmt.Sprintf(format, homepageUrl)
+	}
+	return messages
+}
+
+// APIStatusError is a user-friendly error for an API status code that keeps
+// the original error available via errors.As/errors.Is
+type APIStatusError struct {
+	// StatusCode is the HTTP status code returned by the API
+	StatusCode int
+	// Message is the user-friendly message
+	Message string
+	// Err is the original error returned by the HTTP client
+	Err error
+}
+
+// Error implements the error interface for APIStatusError
+func (e *APIStatusError) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the original error
+func (e *APIStatusError) Unwrap() error {
+	return e.Err
+}
+
+// ErrSubscriptionEnded is matched (via errors.Is) by the error ProcessError
+// returns when the account's subscription has ended
+var ErrSubscriptionEnded = errors.New("subscription ended")
+
+// ErrCreditsExhausted is matched (via errors.Is) by the error ProcessError
+// returns when the account has run out of credits
+var ErrCreditsExhausted = errors.New("credits exhausted")
+
+// ErrContentRejected is matched (via errors.Is) by the error ProcessError
+// returns when content moderation rejected the request
+var ErrContentRejected = errors.New("content rejected by moderation")
+
+// ErrInvalidStyle is matched (via errors.Is) by the error ProcessError
+// returns when the request used an unknown style ID
+var ErrInvalidStyle = errors.New("invalid style")
+
+// errorKeyWordSentinels maps the error key words to the errors their KeyWordError matches
+var errorKeyWordSentinels = map[ErrorKeyWord]error{
+	ErrorKeyWordSubscriptionEnded: ErrSubscriptionEnded,
+	ErrorKeyWordCreditsExhausted:  ErrCreditsExhausted,
+	ErrorKeyWordContentRejected:   ErrContentRejected,
+	ErrorKeyWordInvalidStyle:      ErrInvalidStyle,
+	ErrorKeyWordStyleNotFound:     ErrInvalidStyle,
+}
+
+// KeyWordError is a user-friendly error for an API error whose message contains
+// a known ErrorKeyWord, such as an ended subscription. It matches its Kind via
+// errors.Is and keeps the original error available via errors.As.
+type KeyWordError struct {
+	// Kind is the sentinel error of the problem, such as ErrSubscriptionEnded
+	Kind error
+	// Message is the user-friendly message, with a link to fix the problem
+	Message string
+	// Err is the original error returned by the HTTP client
+	Err error
+}
+
+// Error implements the error interface for KeyWordError
+func (e *KeyWordError) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the original error
+func (e *KeyWordError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the target is the error's Kind
+func (e *KeyWordError) Is(target error) bool {
+	return target == e.Kind
+}
+
+// matchErrorKeyWord returns the first known key word in an API error message.
+// Matching ignores case and collapses runs of whitespace, so the key word may
+// be surrounded by other text.
+func matchErrorKeyWord(message string) (ErrorKeyWord, bool) {
+	normalized := strings.Join(strings.Fields(strings.ToLower(message)), " ")
+	for _, keyWord := range errorKeyWords {
+		if strings.Contains(normalized, string(keyWord)) {
+			return keyWord, true
+		}
+	}
+	return "", false
+}
+
+// ErrUnsafeContent is returned in safe mode when every result was flagged by moderation
+var ErrUnsafeContent = errors.New("generated content was withheld because content moderation flagged it as sensitive or unsafe (safe mode is enabled)")
+
+// ErrRateLimited is matched (via errors.Is) by every RateLimitError
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// RateLimitError is returned when the API responds with 429 Too Many Requests.
+// It carries the time at which the rate limit resets, when the API provides it.
+type RateLimitError struct {
+	// ResetAt is when the rate limit resets. Zero if unknown.
+	ResetAt time.Time
+	// Err is the original error returned by the HTTP client
+	Err error
+}
+
+// Error implements the error interface for RateLimitError
+func (e *RateLimitError) Error() string {
+	if e.ResetAt.IsZero() {
+		return "Rate limit exceeded. Please try again later."
+	}
+
+	wait := time.Until(e.ResetAt).Round(time.Second)
+	if wait < time.Second {
+		wait = time.Second
+	}
+	return fmt.Sprintf(
+		"Rate limit exceeded. Please try again in %s (after %s).",
+		wait, e.ResetAt.UTC().Format(time.RFC3339),
+	)
+}
+
+// Unwrap returns the original error
+func (e *RateLimitError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the target is ErrRateLimited
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
 }
 
 // ProcessError processes the error and returns a new error with the appropriate message
 func ProcessError(err error) error {
+	return ProcessErrorWithHomepage(err, shared.HOMEPAGE_URL)
+}
+
+// ProcessErrorWithHomepage is like ProcessError, but the links in the messages
+// point at homepageUrl. An empty homepageUrl falls back to shared.HOMEPAGE_URL.
+func ProcessErrorWithHomepage(err error, homepageUrl string) error {
+	if homepageUrl == "" {
+		homepageUrl = shared.HOMEPAGE_URL
+	}
+
 	if err != nil {
 		// Check if the error is an API error
 		if apiErr, ok := err.(*httpclient.APIError); ok {
 			// Handle API errors
-			msg := strings.ToLower(apiErr.Message)
-			if strings.Contains(msg, string(ErrorKeyWordSubscriptionEnded)) {
-				return fmt.Errorf(ErrorResponseMap[ErrorKeyWordSubscriptionEnded])
+			if keyWord, ok := matchErrorKeyWord(apiErr.Message); ok {
+				return &KeyWordError{
+					Kind:    errorKeyWordSentinels[keyWord],
+					Message: fmt.Sprintf(errorResponseFormats[keyWord], homepageUrl),
+					Err:     err,
+				}
 			}
-			if strings.Contains(msg, string(ErrorKeyWordCreditsExhausted)) {
-				return fmt.Errorf(ErrorResponseMap[ErrorKeyWordCreditsExhausted])
+
+			// Surface rate limits with the reset time so users know when to retry
+			if apiErr.StatusCode == http.StatusTooManyRequests {
+				resetAt, _ := httpclient.ParseRateLimitReset(apiErr.Headers, time.Now())
+				return &RateLimitError{
+					ResetAt: resetAt,
+					Err:     err,
+				}
+			}
+
+			// Map authentication/permission failures by status code
+			if format, ok := statusErrorResponseFormats[apiErr.StatusCode]; ok {
+				return &APIStatusError{
+					StatusCode: apiErr.StatusCode,
+					Message:    fmt.Sprintf(format, homepageUrl),
+					Err:        err,
+				}
 			}
 		}
 
@@ -47,3 +250,8 @@ func ProcessError(err error) error {
 
 	return nil
 }
+
+// processError processes the error with the messages linking to the client's homepage
+func (a *gaiaApi) processError(err error) error {
+	return ProcessErrorWithHomepage(err, a.homepageUrl)
+}
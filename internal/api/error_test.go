@@ -0,0 +1,262 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gaia-mcp-go/internal/testutil"
+	"gaia-mcp-go/pkg/httpclient"
+	"gaia-mcp-go/pkg/shared"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessError(t *testing.T) {
+	t.Run("Nil error", func(t *testing.T) {
+		assert.NoError(t, ProcessError(nil))
+	})
+
+	t.Run("Subscription ended", func(t *testing.T) {
+		original := &httpclient.APIError{StatusCode: http.StatusPaymentRequired, Message: "Your subscription has ended"}
+		err := ProcessError(original)
+
+		assert.Equal(t, ErrorResponseMap[ErrorKeyWordSubscriptionEnded], err.Error())
+		assert.True(t, errors.Is(err, ErrSubscriptionEnded))
+		assert.False(t, errors.Is(err, ErrCreditsExhausted))
+
+		// The original API error should be preserved
+		var apiErr *httpclient.APIError
+		require.True(t, errors.As(err, &apiErr))
+		assert.Same(t, original, apiErr)
+	})
+
+	t.Run("Credits exhausted", func(t *testing.T) {
+		original := &httpclient.APIError{StatusCode: http.StatusBadRequest, Message: "No available credits"}
+		err := ProcessError(original)
+
+		assert.Equal(t, ErrorResponseMap[ErrorKeyWordCreditsExhausted], err.Error())
+		assert.True(t, errors.Is(err, ErrCreditsExhausted))
+		assert.False(t, errors.Is(err, ErrSubscriptionEnded))
+
+		var keyWordErr *KeyWordError
+		require.True(t, errors.As(err, &keyWordErr))
+		assert.Same(t, original, keyWordErr.Err)
+	})
+
+	t.Run("Matches through further wrapping", func(t *testing.T) {
+		err := fmt.Errorf("generate images: %w", ProcessError(&httpclient.APIError{StatusCode: http.StatusBadRequest, Message: "No available credits"}))
+		assert.True(t, errors.Is(err, ErrCreditsExhausted))
+	})
+
+	t.Run("Unrelated error passes through", func(t *testing.T) {
+		original := errors.New("connection refused")
+		err := ProcessError(original)
+
+		assert.Equal(t, original, err)
+		assert.False(t, errors.Is(err, ErrSubscriptionEnded))
+		assert.False(t, errors.Is(err, ErrCreditsExhausted))
+	})
+
+	t.Run("Unrelated API error passes through", func(t *testing.T) {
+		original := &httpclient.APIError{StatusCode: http.StatusBadRequest, Message: "Invalid prompt"}
+		err := ProcessError(original)
+
+		assert.Same(t, original, err)
+		assert.False(t, errors.Is(err, ErrSubscriptionEnded))
+		assert.False(t, errors.Is(err, ErrCreditsExhausted))
+	})
+}
+
+func TestProcessError_KeyWords(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  string
+		keyWord  ErrorKeyWord
+		expected error
+	}{
+		{name: "Subscription ended", message: "Your subscription has ended", keyWord: ErrorKeyWordSubscriptionEnded, expected: ErrSubscriptionEnded},
+		{name: "Credits exhausted", message: "No available credits", keyWord: ErrorKeyWordCreditsExhausted, expected: ErrCreditsExhausted},
+		{name: "Content moderation", message: "Prompt was rejected by content moderation", keyWord: ErrorKeyWordContentRejected, expected: ErrContentRejected},
+		{name: "Invalid style", message: "Invalid style id: 123", keyWord: ErrorKeyWordInvalidStyle, expected: ErrInvalidStyle},
+		{name: "Style not found", message: "Style not found", keyWord: ErrorKeyWordStyleNotFound, expected: ErrInvalidStyle},
+		{name: "Upper case", message: "NO AVAILABLE CREDITS", keyWord: ErrorKeyWordCreditsExhausted, expected: ErrCreditsExhausted},
+		{name: "Surrounding text and whitespace", message: "Error 4021:  request blocked by\nContent   Moderation (nsfw).", keyWord: ErrorKeyWordContentRejected, expected: ErrContentRejected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ProcessError(&httpclient.APIError{StatusCode: http.StatusBadRequest, Message: tt.message})
+
+			assert.Equal(t, ErrorResponseMap[tt.keyWord], err.Error())
+			assert.True(t, errors.Is(err, tt.expected))
+			assert.Contains(t, err.Error(), shared.HOMEPAGE_URL)
+		})
+	}
+
+	t.Run("Unmatched message passes through", func(t *testing.T) {
+		original := &httpclient.APIError{StatusCode: http.StatusBadRequest, Message: "Style transfer is temporarily disabled"}
+		err := ProcessError(original)
+
+		assert.Same(t, original, err)
+		for _, sentinel := range []error{ErrSubscriptionEnded, ErrCreditsExhausted, ErrContentRejected, ErrInvalidStyle} {
+			assert.False(t, errors.Is(err, sentinel))
+		}
+	})
+}
+
+func TestProcessError_StatusCodes(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+	}{
+		{name: "Unauthorized", statusCode: http.StatusUnauthorized},
+		{name: "Forbidden", statusCode: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := &httpclient.APIError{StatusCode: tt.statusCode, Message: "denied"}
+
+			err := ProcessError(original)
+
+			// The message should be user-friendly
+			assert.Equal(t, StatusErrorResponseMap[tt.statusCode], err.Error())
+
+			// The status error should be available to callers
+			var statusErr *APIStatusError
+			require.True(t, errors.As(err, &statusErr))
+			assert.Equal(t, tt.statusCode, statusErr.StatusCode)
+
+			// The original API error should be preserved
+			var apiErr *httpclient.APIError
+			require.True(t, errors.As(err, &apiErr))
+			assert.Same(t, original, apiErr)
+		})
+	}
+
+	t.Run("Keyword match takes precedence over status code", func(t *testing.T) {
+		err := ProcessError(&httpclient.APIError{StatusCode: http.StatusForbidden, Message: "Your subscription has ended"})
+		assert.Equal(t, ErrorResponseMap[ErrorKeyWordSubscriptionEnded], err.Error())
+	})
+}
+
+func TestProcessError_RateLimited(t *testing.T) {
+	resetAt := time.Now().Add(90 * time.Second).Truncate(time.Second)
+
+	tests := []struct {
+		name    string
+		headers http.Header
+		want    time.Time
+	}{
+		{
+			name:    "Retry-After seconds",
+			headers: http.Header{"Retry-After": []string{"90"}},
+			want:    resetAt,
+		},
+		{
+			name:    "Retry-After HTTP date",
+			headers: http.Header{"Retry-After": []string{resetAt.UTC().Format(http.TimeFormat)}},
+			want:    resetAt,
+		},
+		{
+			name:    "X-RateLimit-Reset unix seconds",
+			headers: http.Header{"X-Ratelimit-Reset": []string{strconv.FormatInt(resetAt.Unix(), 10)}},
+			want:    resetAt,
+		},
+		{
+			name:    "X-RateLimit-Reset unix milliseconds",
+			headers: http.Header{"X-Ratelimit-Reset": []string{strconv.FormatInt(resetAt.UnixMilli(), 10)}},
+			want:    resetAt,
+		},
+		{
+			name:    "No reset headers",
+			headers: http.Header{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := &httpclient.APIError{StatusCode: http.StatusTooManyRequests, Message: "Too many requests", Headers: tt.headers}
+
+			err := ProcessError(original)
+
+			assert.True(t, errors.Is(err, ErrRateLimited))
+
+			var rateErr *RateLimitError
+			require.True(t, errors.As(err, &rateErr))
+			if tt.want.IsZero() {
+				assert.True(t, rateErr.ResetAt.IsZero())
+				assert.Contains(t, err.Error(), "try again later")
+			} else {
+				assert.WithinDuration(t, tt.want, rateErr.ResetAt, 2*time.Second)
+				assert.Contains(t, err.Error(), rateErr.ResetAt.UTC().Format(time.RFC3339))
+			}
+
+			// The original API error should be preserved
+			var apiErr *httpclient.APIError
+			require.True(t, errors.As(err, &apiErr))
+			assert.Same(t, original, apiErr)
+		})
+	}
+}
+
+func TestProcessErrorWithHomepage(t *testing.T) {
+	const homepage = "https://gaia.example.com"
+
+	tests := []struct {
+		name     string
+		err      *httpclient.APIError
+		expected string
+	}{
+		{
+			name:     "Subscription ended",
+			err:      &httpclient.APIError{StatusCode: http.StatusPaymentRequired, Message: "Your subscription has ended"},
+			expected: homepage + "/settings/account?tab=Plans&plan=subscription",
+		},
+		{
+			name:     "Credits exhausted",
+			err:      &httpclient.APIError{StatusCode: http.StatusBadRequest, Message: "No available credits"},
+			expected: homepage + "/settings/account?tab=Plans&plan=credits",
+		},
+		{
+			name:     "Unauthorized",
+			err:      &httpclient.APIError{StatusCode: http.StatusUnauthorized, Message: "denied"},
+			expected: homepage + "/settings/account?tab=Security",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ProcessErrorWithHomepage(tt.err, homepage)
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expected)
+			assert.NotContains(t, err.Error(), shared.HOMEPAGE_URL)
+		})
+	}
+
+	t.Run("Empty homepage uses the default", func(t *testing.T) {
+		err := ProcessErrorWithHomepage(&httpclient.APIError{StatusCode: http.StatusBadRequest, Message: "No available credits"}, "")
+		assert.Equal(t, ErrorResponseMap[ErrorKeyWordCreditsExhausted], err.Error())
+	})
+
+	t.Run("Configured on the client", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		server.AddResponse("GET", "/api/users/me", testutil.MockResponse{
+			StatusCode: http.StatusPaymentRequired,
+			Body:       map[string]string{"message": "Your subscription has ended"},
+		})
+
+		client := NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key", HomepageUrl: homepage + "/"})
+		_, err := client.WhoAmI(context.Background())
+
+		require.Error(t, err)
+		assert.Equal(t, ErrorResponses(homepage)[ErrorKeyWordSubscriptionEnded], err.Error())
+	})
+}
@@ -0,0 +1,294 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"gaia-mcp-go/pkg/httpclient"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// KeyStrategy controls how a request picks one of several API keys
+type KeyStrategy string
+
+const (
+	// KeyStrategyFailover uses the first key until it is rate limited or out of credits
+	KeyStrategyFailover KeyStrategy = "failover"
+	// KeyStrategyRoundRobin rotates through the keys on every operation. The requests
+	// of one operation, such as an upload or the polling of a task, share a key.
+	KeyStrategyRoundRobin KeyStrategy = "round-robin"
+)
+
+// defaultKeyCooldown is how long a limited key is skipped when the API gives no reset time
+const defaultKeyCooldown = time.Minute
+
+// apiKeyContextKey carries the key chosen for a logical request through its retries
+type apiKeyContextKey struct{}
+
+// keySessionContextKey carries the keySession of a logical operation
+type keySessionContextKey struct{}
+
+// keySession pins one key for a logical operation made of several requests,
+// such as an upload session or a balance check and the generation it guards,
+// so every request of the operation is made with the same account.
+//
+// Until one of its requests succeeds, a session fails over to the next key
+// like a single request. Afterwards it keeps its key, since later requests
+// refer to state created with that account.
+type keySession struct {
+	mu   sync.Mutex
+	idx  int
+	key  string
+	set  bool
+	used bool
+}
+
+// withKeySession returns a context whose requests made through withKey share
+// one key. A context that already carries a session is returned as is, so
+// nested operations join the outer one.
+func withKeySession(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(keySessionContextKey{}).(*keySession); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, keySessionContextKey{}, &keySession{})
+}
+
+// keySessionFrom returns the session carried by ctx, or nil
+func keySessionFrom(ctx context.Context) *keySession {
+	session, _ := ctx.Value(keySessionContextKey{}).(*keySession)
+	return session
+}
+
+// keyPool selects API keys and remembers which ones are temporarily limited.
+// It is safe for concurrent use.
+type keyPool struct {
+	mu       sync.Mutex
+	keys     []string
+	strategy KeyStrategy
+	next     int
+	limited  map[int]time.Time
+	// tasks maps the ID of a pending task to the key that created it
+	tasks map[string]int
+	now   func() time.Time
+}
+
+// newKeyPool creates a pool for the given keys. Blank keys are ignored.
+func newKeyPool(keys []string, strategy KeyStrategy) *keyPool {
+	filtered := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if key = strings.TrimSpace(key); key != "" {
+			filtered = append(filtered, key)
+		}
+	}
+	if len(filtered) == 0 {
+		// Keep a single empty key so requests still go out and fail with a clear 401
+		filtered = append(filtered, "")
+	}
+	if strategy == "" {
+		strategy = KeyStrategyFailover
+	}
+
+	return &keyPool{
+		keys:     filtered,
+		strategy: strategy,
+		limited:  make(map[int]time.Time),
+		tasks:    make(map[string]int),
+		now:      time.Now,
+	}
+}
+
+// size returns the number of keys in the pool
+func (p *keyPool) size() int {
+	return len(p.keys)
+}
+
+// pick returns the index and value of the key to use for the next request.
+// Limited keys are skipped unless every key is limited.
+func (p *keyPool) pick() (int, string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	start := 0
+	if p.strategy == KeyStrategyRoundRobin {
+		start = p.next
+		p.next = (p.next + 1) % len(p.keys)
+	}
+
+	for i := 0; i < len(p.keys); i++ {
+		idx := (start + i) % len(p.keys)
+		if until, ok := p.limited[idx]; ok {
+			if now.Before(until) {
+				continue
+			}
+			delete(p.limited, idx)
+		}
+		return idx, p.keys[idx]
+	}
+
+	// Every key is limited: fall back to the one that would have been picked
+	return start, p.keys[start]
+}
+
+// markLimited skips the key until the given time (or the default cooldown when zero)
+func (p *keyPool) markLimited(idx int, until time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if until.IsZero() {
+		until = p.now().Add(defaultKeyCooldown)
+	}
+	p.limited[idx] = until
+}
+
+// pickFor returns the key pinned by the session, pinning the next key from the
+// pool when there is none yet. A nil session picks from the pool.
+// The returned bool reports whether the key may still be failed over.
+func (p *keyPool) pickFor(session *keySession) (int, string, bool) {
+	if session == nil {
+		idx, key := p.pick()
+		return idx, key, true
+	}
+
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	if !session.set {
+		session.idx, session.key = p.pick()
+		session.set = true
+	}
+	return session.idx, session.key, !session.used
+}
+
+// settle records the outcome of a request made with the key at idx: a limited
+// key is unpinned so the next request picks another one, any other outcome
+// pins the key for the rest of the session
+func (s *keySession) settle(idx int, limited bool) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.idx != idx {
+		return
+	}
+	if limited && !s.used {
+		s.set = false
+		return
+	}
+	s.used = true
+}
+
+// pinned returns the index of the key pinned by the session
+func (s *keySession) pinned() (int, bool) {
+	if s == nil {
+		return 0, false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.idx, s.set
+}
+
+// rememberTask records that the pending task was created with the key pinned
+// by the session in ctx, so its status checks and cancellation use the same account
+func (p *keyPool) rememberTask(ctx context.Context, taskId string) {
+	idx, ok := keySessionFrom(ctx).pinned()
+	if !ok || taskId == "" || p.size() == 1 {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.tasks[taskId] = idx
+}
+
+// forgetTask drops the key recorded for a task that will no longer be followed up
+func (p *keyPool) forgetTask(taskId string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.tasks, taskId)
+}
+
+// withTaskKey returns a context whose requests use the key that created the
+// task, or a new key session when the task is unknown
+func (p *keyPool) withTaskKey(ctx context.Context, taskId string) context.Context {
+	p.mu.Lock()
+	idx, ok := p.tasks[taskId]
+	p.mu.Unlock()
+
+	if !ok {
+		return withKeySession(ctx)
+	}
+	return context.WithValue(ctx, keySessionContextKey{}, &keySession{
+		idx:  idx,
+		key:  p.keys[idx],
+		set:  true,
+		used: true,
+	})
+}
+
+// interceptor sets the Authorization header from the key chosen for the request,
+// or picks one when the request was not made through withKey
+func (p *keyPool) interceptor(req *http.Request) error {
+	key, ok := req.Context().Value(apiKeyContextKey{}).(string)
+	if !ok {
+		_, key = p.pick()
+	}
+	req.Header.Set("Authorization", "Bearer "+key)
+	return nil
+}
+
+// withKey runs call with a key from the pool, or with the key pinned by the
+// key session in ctx. When the key is rate limited or out of credits, it is
+// marked as limited and the call is retried with the next available key, up
+// to once per key. A session whose key was already used is not failed over.
+//
+// call receives a context carrying the chosen key and must return the raw
+// HTTP client error (before ProcessError) so limits can be detected.
+func (p *keyPool) withKey(ctx context.Context, call func(ctx context.Context) error) error {
+	session := keySessionFrom(ctx)
+
+	var err error
+	for attempt := 0; attempt < p.size(); attempt++ {
+		idx, key, canFailover := p.pickFor(session)
+		err = call(context.WithValue(ctx, apiKeyContextKey{}, key))
+
+		limited, resetAt := isKeyLimited(err)
+		if limited {
+			p.markLimited(idx, resetAt)
+		}
+		session.settle(idx, limited)
+		if !limited || !canFailover {
+			return err
+		}
+	}
+	return err
+}
+
+// isKeyLimited reports whether err means the key hit a rate limit or ran out of credits,
+// along with the reset time when the API provided one
+func isKeyLimited(err error) (bool, time.Time) {
+	var apiErr *httpclient.APIError
+	if !errors.As(err, &apiErr) {
+		return false, time.Time{}
+	}
+
+	if apiErr.StatusCode == http.StatusTooManyRequests {
+		resetAt, _ := httpclient.ParseRateLimitReset(apiErr.Headers, time.Now())
+		return true, resetAt
+	}
+
+	if strings.Contains(strings.ToLower(apiErr.Message), string(ErrorKeyWordCreditsExhausted)) {
+		return true, time.Time{}
+	}
+
+	return false, time.Time{}
+}
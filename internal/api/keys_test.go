@@ -0,0 +1,282 @@
+package api
+
+import (
+	"context"
+	"gaia-mcp-go/internal/testutil"
+	"gaia-mcp-go/pkg/shared"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyPool_Pick(t *testing.T) {
+	t.Run("Round robin rotates keys", func(t *testing.T) {
+		pool := newKeyPool([]string{"key-a", "key-b", "key-c"}, KeyStrategyRoundRobin)
+
+		var picked []string
+		for i := 0; i < 4; i++ {
+			_, key := pool.pick()
+			picked = append(picked, key)
+		}
+
+		assert.Equal(t, []string{"key-a", "key-b", "key-c", "key-a"}, picked)
+	})
+
+	t.Run("Failover sticks to the first available key", func(t *testing.T) {
+		pool := newKeyPool([]string{"key-a", "key-b"}, KeyStrategyFailover)
+
+		_, key := pool.pick()
+		assert.Equal(t, "key-a", key)
+
+		pool.markLimited(0, time.Time{})
+		_, key = pool.pick()
+		assert.Equal(t, "key-b", key)
+	})
+
+	t.Run("Limited keys come back after their reset time", func(t *testing.T) {
+		now := time.Now()
+		pool := newKeyPool([]string{"key-a", "key-b"}, KeyStrategyFailover)
+		pool.now = func() time.Time { return now }
+
+		pool.markLimited(0, now.Add(time.Minute))
+		_, key := pool.pick()
+		assert.Equal(t, "key-b", key)
+
+		now = now.Add(2 * time.Minute)
+		_, key = pool.pick()
+		assert.Equal(t, "key-a", key)
+	})
+
+	t.Run("Blank keys are ignored", func(t *testing.T) {
+		pool := newKeyPool([]string{" ", "key-a", ""}, KeyStrategyRoundRobin)
+		assert.Equal(t, 1, pool.size())
+	})
+
+	t.Run("Concurrent picks are safe", func(t *testing.T) {
+		pool := newKeyPool([]string{"key-a", "key-b"}, KeyStrategyRoundRobin)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				idx, _ := pool.pick()
+				if i%10 == 0 {
+					pool.markLimited(idx, time.Now().Add(time.Millisecond))
+				}
+			}(i)
+		}
+		wg.Wait()
+	})
+}
+
+func TestGaiaApi_MultipleApiKeys(t *testing.T) {
+	const createTaskPath = "/api/recipe/agi-tasks/create-task"
+
+	successResponse := testutil.MockResponse{
+		StatusCode: 200,
+		Body:       ImageGeneratedResponse{Success: true, Images: []string{"image-url"}},
+	}
+
+	authHeaders := func(server *testutil.TestServer) []string {
+		var headers []string
+		for _, req := range server.Requests("POST", createTaskPath) {
+			headers = append(headers, req.Headers.Get("Authorization"))
+		}
+		return headers
+	}
+
+	t.Run("Round robin across requests", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		server.AddResponse("POST", createTaskPath, successResponse)
+
+		api := NewGaiaApi(GaiaApiConfig{
+			BaseUrl:     server.URL,
+			ApiKeys:     []string{"key-a", "key-b"},
+			KeyStrategy: KeyStrategyRoundRobin,
+		})
+		for i := 0; i < 3; i++ {
+			_, err := api.GenerateImages(context.Background(), GenerateImagesRequest{RecipeId: shared.RecipeIdImageGeneratorSimple, Params: map[string]interface{}{"prompt": "a cat"}})
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, []string{"Bearer key-a", "Bearer key-b", "Bearer key-a"}, authHeaders(server))
+	})
+
+	t.Run("Fails over when a key runs out of credits", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		server.AddResponseSequence("POST", createTaskPath,
+			testutil.MockResponse{StatusCode: 400, Body: map[string]interface{}{"message": "No available credits"}},
+			successResponse,
+		)
+
+		api := NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKeys: []string{"key-a", "key-b"}})
+		for i := 0; i < 2; i++ {
+			_, err := api.GenerateImages(context.Background(), GenerateImagesRequest{RecipeId: shared.RecipeIdImageGeneratorSimple, Params: map[string]interface{}{"prompt": "a cat"}})
+			require.NoError(t, err)
+		}
+
+		// The exhausted key is retried on the next key and then skipped
+		assert.Equal(t, []string{"Bearer key-a", "Bearer key-b", "Bearer key-b"}, authHeaders(server))
+	})
+
+	t.Run("Fails over when a key is rate limited", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		rateLimited := testutil.MockResponse{
+			StatusCode: 429,
+			Body:       map[string]interface{}{"message": "Too many requests"},
+			Headers:    map[string]string{"Retry-After": "60"},
+		}
+		// The wait is too long for the HTTP client to retry, so the key is failed over at once
+		server.AddResponseSequence("POST", createTaskPath, rateLimited, successResponse)
+
+		api := NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKeys: []string{"key-a", "key-b"}})
+		_, err := api.GenerateImages(context.Background(), GenerateImagesRequest{RecipeId: shared.RecipeIdImageGeneratorSimple, Params: map[string]interface{}{"prompt": "a cat"}})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"Bearer key-a", "Bearer key-b"}, authHeaders(server))
+	})
+
+	t.Run("Single key is unaffected", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		server.AddResponse("POST", createTaskPath, successResponse)
+
+		api := NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key"})
+		_, err := api.GenerateImages(context.Background(), GenerateImagesRequest{RecipeId: shared.RecipeIdImageGeneratorSimple, Params: map[string]interface{}{"prompt": "a cat"}})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"Bearer test-key"}, authHeaders(server))
+	})
+}
+
+func TestGaiaApi_KeySessions(t *testing.T) {
+	const createTaskPath = "/api/recipe/agi-tasks/create-task"
+
+	authHeaders := func(server *testutil.TestServer, method, path string) []string {
+		var headers []string
+		for _, req := range server.Requests(method, path) {
+			headers = append(headers, req.Headers.Get("Authorization"))
+		}
+		return headers
+	}
+	newRoundRobinApi := func(server *testutil.TestServer) GaiaApi {
+		return NewGaiaApi(GaiaApiConfig{
+			BaseUrl:     server.URL,
+			ApiKeys:     []string{"key-a", "key-b"},
+			KeyStrategy: KeyStrategyRoundRobin,
+		})
+	}
+	request := GenerateImagesRequest{RecipeId: shared.RecipeIdImageGeneratorSimple, Params: map[string]interface{}{"prompt": "a cat"}}
+
+	t.Run("Tasks are followed up with the key that created them", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		server.AddResponse("POST", createTaskPath, testutil.MockResponse{
+			StatusCode: 200,
+			Body:       ImageGeneratedResponse{Success: true, TaskId: "task-1"},
+		})
+		server.AddResponse("GET", "/api/recipe/agi-tasks/task-1", testutil.MockResponse{
+			StatusCode: 200,
+			Body:       RecipeTask{Id: "task-1", Status: shared.RecipeTaskStatusRunning},
+		})
+		server.AddResponse("POST", "/api/recipe/agi-tasks/task-1/cancel", testutil.MockResponse{StatusCode: 200})
+
+		api := newRoundRobinApi(server)
+		_, err := api.WhoAmI(context.Background())
+		require.Error(t, err)
+
+		// key-a went to WhoAmI, so the task is created with key-b
+		_, err = api.GenerateImages(context.Background(), request)
+		require.NoError(t, err)
+		for i := 0; i < 2; i++ {
+			_, err = api.GetTaskStatus(context.Background(), "task-1")
+			require.NoError(t, err)
+		}
+		require.NoError(t, api.CancelTask(context.Background(), "task-1"))
+
+		assert.Equal(t, []string{"Bearer key-b"}, authHeaders(server, "POST", createTaskPath))
+		assert.Equal(t, []string{"Bearer key-b", "Bearer key-b"}, authHeaders(server, "GET", "/api/recipe/agi-tasks/task-1"))
+		assert.Equal(t, []string{"Bearer key-b"}, authHeaders(server, "POST", "/api/recipe/agi-tasks/task-1/cancel"))
+	})
+
+	t.Run("An upload session uses one key", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		server.AddResponse("GET", "/image.png", testutil.MockResponse{StatusCode: 200, Body: testutil.CreateMockImage()})
+		server.AddResponse("POST", "/api/upload/initialize", initUploadResponse(server, "upload-1", "/chunks/upload-1"))
+		server.AddResponse("PUT", "/chunks/upload-1", testutil.MockResponse{StatusCode: 200, Headers: map[string]string{"ETag": "etag-1"}})
+		server.AddResponse("POST", "/api/upload/complete", testutil.MockResponse{StatusCode: 200})
+
+		api := newRoundRobinApi(server)
+		_, err := api.UploadImages(context.Background(), []string{server.URL + "/image.png", server.URL + "/image.png"}, shared.FileAssociatedResourceNone)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"Bearer key-a", "Bearer key-a"}, authHeaders(server, "POST", "/api/upload/initialize"))
+		assert.Equal(t, []string{"Bearer key-a", "Bearer key-a"}, authHeaders(server, "POST", "/api/upload/complete"))
+	})
+
+	t.Run("The balance is checked with the key that is charged", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		server.AddResponse("GET", "/api/users/me", testutil.MockResponse{StatusCode: 200, Body: Account{Credits: 10}})
+		server.AddResponse("POST", createTaskPath, testutil.MockResponse{
+			StatusCode: 200,
+			Body:       ImageGeneratedResponse{Success: true, Images: []string{"image-url"}},
+		})
+
+		guard := NewCostGuard(newRoundRobinApi(server), CreditCosts{}, "")
+		for i := 0; i < 2; i++ {
+			_, err := guard.GenerateImages(context.Background(), request)
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, []string{"Bearer key-a", "Bearer key-b"}, authHeaders(server, "GET", "/api/users/me"))
+		assert.Equal(t, []string{"Bearer key-a", "Bearer key-b"}, authHeaders(server, "POST", createTaskPath))
+	})
+
+	t.Run("A used key is not failed over", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		server.AddResponse("GET", "/api/users/me", testutil.MockResponse{StatusCode: 200, Body: Account{Credits: 10}})
+		server.AddResponse("POST", createTaskPath, testutil.MockResponse{
+			StatusCode: 429,
+			Body:       map[string]interface{}{"message": "Too many requests"},
+			Headers:    map[string]string{"Retry-After": "60"},
+		})
+
+		guard := NewCostGuard(newRoundRobinApi(server), CreditCosts{}, "")
+		_, err := guard.GenerateImages(context.Background(), request)
+		require.Error(t, err)
+
+		// The balance was read for key-a, so the generation is not moved to key-b
+		assert.Equal(t, []string{"Bearer key-a"}, authHeaders(server, "POST", createTaskPath))
+	})
+
+	t.Run("Other calls fail over when a key is rate limited", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		server.AddResponseSequence("GET", "/api/sd-styles/style-1",
+			testutil.MockResponse{
+				StatusCode: 429,
+				Body:       map[string]interface{}{"message": "Too many requests"},
+				Headers:    map[string]string{"Retry-After": "60"},
+			},
+			testutil.MockResponse{StatusCode: 200, Body: SdStyle{Id: "style-1"}},
+		)
+
+		api := NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKeys: []string{"key-a", "key-b"}})
+		style, err := api.GetStyle(context.Background(), "style-1")
+		require.NoError(t, err)
+
+		assert.Equal(t, "style-1", style.Id)
+		assert.Equal(t, []string{"Bearer key-a", "Bearer key-b"}, authHeaders(server, "GET", "/api/sd-styles/style-1"))
+	})
+}
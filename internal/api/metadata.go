@@ -0,0 +1,111 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ImageMetadata is the parsed form of Image.FullMetadata.
+//
+// The metadata is free-form JSON, so values are kept as decoded and the
+// accessors convert common fields, accepting both numbers and numeric strings.
+type ImageMetadata map[string]interface{}
+
+// ParsedMetadata unmarshals the image's FullMetadata JSON string.
+//
+// An empty FullMetadata yields an empty map and no error. Invalid JSON, or
+// JSON that is not an object, returns an error.
+func (i Image) ParsedMetadata() (ImageMetadata, error) {
+	if strings.TrimSpace(i.FullMetadata) == "" {
+		return ImageMetadata{}, nil
+	}
+
+	var metadata ImageMetadata
+	if err := json.Unmarshal([]byte(i.FullMetadata), &metadata); err != nil {
+		return nil, fmt.Errorf("failed to parse image metadata: %w", err)
+	}
+	if metadata == nil {
+		metadata = ImageMetadata{}
+	}
+
+	return metadata, nil
+}
+
+// Prompt returns the prompt used to generate the image
+func (m ImageMetadata) Prompt() string {
+	return m.String("prompt")
+}
+
+// NegativePrompt returns the negative prompt used to generate the image
+func (m ImageMetadata) NegativePrompt() string {
+	return m.String("negativePrompt")
+}
+
+// Seed returns the seed as a string, since seeds can exceed float64 precision
+func (m ImageMetadata) Seed() string {
+	return m.String("seed")
+}
+
+// ModelName returns the name of the model used for generation
+func (m ImageMetadata) ModelName() string {
+	return m.String("modelName")
+}
+
+// Sampler returns the sampling method used for generation
+func (m ImageMetadata) Sampler() string {
+	return m.String("sampler")
+}
+
+// Steps returns the number of inference steps, or 0 if unknown
+func (m ImageMetadata) Steps() int {
+	return int(m.Number("steps"))
+}
+
+// CfgScale returns the classifier-free guidance scale, or 0 if unknown
+func (m ImageMetadata) CfgScale() float64 {
+	return m.Number("cfgScale")
+}
+
+// Width returns the image width in pixels, or 0 if unknown
+func (m ImageMetadata) Width() int {
+	return int(m.Number("width"))
+}
+
+// Height returns the image height in pixels, or 0 if unknown
+func (m ImageMetadata) Height() int {
+	return int(m.Number("height"))
+}
+
+// String returns the value for key as a string. Numbers are formatted
+// without exponent; missing or non-scalar values yield "".
+func (m ImageMetadata) String(key string) string {
+	switch value := m[key].(type) {
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(value)
+	default:
+		return ""
+	}
+}
+
+// Number returns the value for key as a float64. Numeric strings are parsed;
+// missing or non-numeric values yield 0.
+func (m ImageMetadata) Number(key string) float64 {
+	switch value := m[key].(type) {
+	case float64:
+		return value
+	case string:
+		number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return 0
+		}
+		return number
+	default:
+		return 0
+	}
+}
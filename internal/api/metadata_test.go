@@ -0,0 +1,69 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImage_ParsedMetadata(t *testing.T) {
+	t.Run("Realistic metadata", func(t *testing.T) {
+		image := Image{FullMetadata: `{
+			"prompt": "A red fox in the snow",
+			"negativePrompt": "blurry",
+			"seed": "1234567890123",
+			"steps": 30,
+			"cfgScale": "7.5",
+			"sampler": "DPM++ 2M Karras",
+			"width": 1024,
+			"height": 768,
+			"modelName": "sdxl-base",
+			"extra": {"lora": ["a", "b"]}
+		}`}
+
+		metadata, err := image.ParsedMetadata()
+		require.NoError(t, err)
+
+		assert.Equal(t, "A red fox in the snow", metadata.Prompt())
+		assert.Equal(t, "blurry", metadata.NegativePrompt())
+		assert.Equal(t, "1234567890123", metadata.Seed())
+		assert.Equal(t, 30, metadata.Steps())
+		assert.Equal(t, 7.5, metadata.CfgScale())
+		assert.Equal(t, "DPM++ 2M Karras", metadata.Sampler())
+		assert.Equal(t, 1024, metadata.Width())
+		assert.Equal(t, 768, metadata.Height())
+		assert.Equal(t, "sdxl-base", metadata.ModelName())
+		assert.Contains(t, metadata, "extra")
+	})
+
+	t.Run("Numeric seed is formatted without exponent", func(t *testing.T) {
+		metadata, err := Image{FullMetadata: `{"seed": 123456789}`}.ParsedMetadata()
+		require.NoError(t, err)
+		assert.Equal(t, "123456789", metadata.Seed())
+	})
+
+	t.Run("Empty metadata", func(t *testing.T) {
+		metadata, err := Image{}.ParsedMetadata()
+		require.NoError(t, err)
+		assert.Empty(t, metadata)
+		assert.Equal(t, "", metadata.Prompt())
+		assert.Equal(t, 0, metadata.Steps())
+	})
+
+	t.Run("JSON null", func(t *testing.T) {
+		metadata, err := Image{FullMetadata: "null"}.ParsedMetadata()
+		require.NoError(t, err)
+		assert.NotNil(t, metadata)
+	})
+
+	t.Run("Invalid JSON", func(t *testing.T) {
+		_, err := Image{FullMetadata: "{not json"}.ParsedMetadata()
+		assert.Error(t, err)
+	})
+
+	t.Run("Non-object JSON", func(t *testing.T) {
+		_, err := Image{FullMetadata: `["a"]`}.ParsedMetadata()
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,76 @@
+package api
+
+import "sync"
+
+// ProgressFunc receives upload progress in bytes. total grows as the images
+// of an upload are downloaded and prepared, so uploaded only reaches total
+// once every image has been sent.
+type ProgressFunc func(uploaded, total int64)
+
+// UploadOptions tunes a call to UploadImages
+type UploadOptions struct {
+	// Progress is called after each chunk is uploaded, with the bytes sent so
+	// far across all chunks and images. It may be called from several
+	// goroutines, but never concurrently, and is not called again once a
+	// failure has aborted the upload. Nil disables progress reporting.
+	Progress ProgressFunc
+}
+
+// uploadProgress aggregates the progress of every chunk of an upload.
+// A nil *uploadProgress reports nothing.
+type uploadProgress struct {
+	mu       sync.Mutex
+	report   ProgressFunc
+	uploaded int64
+	total    int64
+	failed   bool
+}
+
+// newUploadProgress returns a tracker for the options' progress callback, or
+// nil when no callback is set
+func newUploadProgress(opts []UploadOptions) *uploadProgress {
+	for _, opt := range opts {
+		if opt.Progress != nil {
+			return &uploadProgress{report: opt.Progress}
+		}
+	}
+	return nil
+}
+
+// expect adds bytes that are about to be uploaded to the total
+func (p *uploadProgress) expect(n int64) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.total += n
+}
+
+// advance records uploaded bytes and reports the new progress
+func (p *uploadProgress) advance(n int64) {
+	if p == nil {
+		return
+	}
+
+	// Report under the lock so callbacks are serialized and see increasing values
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.failed {
+		return
+	}
+	p.uploaded += n
+	p.report(p.uploaded, p.total)
+}
+
+// fail stops all further progress reports
+func (p *uploadProgress) fail() {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.failed = true
+}
@@ -0,0 +1,89 @@
+package api
+
+import "fmt"
+
+// DefaultStyleImageWeight is the weight of each reference image of a style
+// when no weights or preset are given
+const DefaultStyleImageWeight = 0.5
+
+// StyleWeightPreset distributes the weights of a style's reference images
+type StyleWeightPreset string
+
+const (
+	// StyleWeightPresetEqual gives every image DefaultStyleImageWeight
+	StyleWeightPresetEqual StyleWeightPreset = "equal"
+	// StyleWeightPresetEmphasizeFirst gives the first image full weight and
+	// the others DefaultStyleImageWeight
+	StyleWeightPresetEmphasizeFirst StyleWeightPreset = "emphasize-first"
+	// StyleWeightPresetDecay lowers the weight linearly from 1 for the first
+	// image, so earlier images have more influence
+	StyleWeightPresetDecay StyleWeightPreset = "decay"
+)
+
+// StyleWeightPresets lists every weight preset
+var StyleWeightPresets = []StyleWeightPreset{
+	StyleWeightPresetEqual,
+	StyleWeightPresetEmphasizeFirst,
+	StyleWeightPresetDecay,
+}
+
+// CreateStyleOptions tunes a call to CreateStyle
+type CreateStyleOptions struct {
+	// Weights sets the weight (0 to 1) of each image, in the order of the
+	// image urls. It overrides Preset when set.
+	Weights []float64
+	// Preset distributes the weights when Weights is not set.
+	// Empty uses StyleWeightPresetEqual.
+	Preset StyleWeightPreset
+}
+
+// StyleWeights returns the weights of n reference images under the preset.
+// An empty preset is StyleWeightPresetEqual. For 4 images:
+//   - equal: 0.5, 0.5, 0.5, 0.5
+//   - emphasize-first: 1, 0.5, 0.5, 0.5
+//   - decay: 1, 0.75, 0.5, 0.25
+//
+// It returns an error when the preset is unknown.
+func StyleWeights(preset StyleWeightPreset, n int) ([]float64, error) {
+	weights := make([]float64, n)
+	for i := range weights {
+		switch preset {
+		case "", StyleWeightPresetEqual:
+			weights[i] = DefaultStyleImageWeight
+		case StyleWeightPresetEmphasizeFirst:
+			weights[i] = DefaultStyleImageWeight
+			if i == 0 {
+				weights[i] = 1
+			}
+		case StyleWeightPresetDecay:
+			weights[i] = 1 - float64(i)/float64(n)
+		default:
+			return nil, fmt.Errorf("unknown weight preset %q", preset)
+		}
+	}
+	return weights, nil
+}
+
+// styleImageWeights resolves the weights of n reference images from the
+// options: raw weights first, then the preset. It returns an error when the
+// raw weights do not match the images or fall outside 0 to 1.
+func styleImageWeights(n int, opts []CreateStyleOptions) ([]float64, error) {
+	var options CreateStyleOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+
+	if options.Weights == nil {
+		return StyleWeights(options.Preset, n)
+	}
+
+	if len(options.Weights) != n {
+		return nil, fmt.Errorf("got %d weights for %d images, expected one weight per image", len(options.Weights), n)
+	}
+	for i, weight := range options.Weights {
+		if weight < 0 || weight > 1 {
+			return nil, fmt.Errorf("weights[%d] must be between 0 and 1, got %g", i, weight)
+		}
+	}
+	return options.Weights, nil
+}
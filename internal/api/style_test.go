@@ -0,0 +1,113 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"gaia-mcp-go/internal/testutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStyleWeights(t *testing.T) {
+	tests := []struct {
+		name     string
+		preset   StyleWeightPreset
+		n        int
+		expected []float64
+	}{
+		{"Default with 3 images", "", 3, []float64{0.5, 0.5, 0.5}},
+		{"Equal with 1 image", StyleWeightPresetEqual, 1, []float64{0.5}},
+		{"Equal with 4 images", StyleWeightPresetEqual, 4, []float64{0.5, 0.5, 0.5, 0.5}},
+		{"Emphasize first with 1 image", StyleWeightPresetEmphasizeFirst, 1, []float64{1}},
+		{"Emphasize first with 4 images", StyleWeightPresetEmphasizeFirst, 4, []float64{1, 0.5, 0.5, 0.5}},
+		{"Decay with 1 image", StyleWeightPresetDecay, 1, []float64{1}},
+		{"Decay with 2 images", StyleWeightPresetDecay, 2, []float64{1, 0.5}},
+		{"Decay with 4 images", StyleWeightPresetDecay, 4, []float64{1, 0.75, 0.5, 0.25}},
+		{"No images", StyleWeightPresetDecay, 0, []float64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			weights, err := StyleWeights(tt.preset, tt.n)
+
+			require.NoError(t, err)
+			assert.InDeltaSlice(t, tt.expected, weights, 1e-9)
+		})
+	}
+
+	t.Run("Unknown preset", func(t *testing.T) {
+		_, err := StyleWeights("random", 2)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `unknown weight preset "random"`)
+	})
+}
+
+func TestStyleImageWeights(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          []CreateStyleOptions
+		expected      []float64
+		expectedError string
+	}{
+		{"No options", nil, []float64{0.5, 0.5, 0.5}, ""},
+		{"Preset", []CreateStyleOptions{{Preset: StyleWeightPresetDecay}}, []float64{1, 2.0 / 3, 1.0 / 3}, ""},
+		{"Raw weights override the preset", []CreateStyleOptions{{Weights: []float64{0.2, 0, 1}, Preset: StyleWeightPresetDecay}}, []float64{0.2, 0, 1}, ""},
+		{"Too few weights", []CreateStyleOptions{{Weights: []float64{0.2}}}, nil, "got 1 weights for 3 images"},
+		{"Weight out of range", []CreateStyleOptions{{Weights: []float64{0.2, 1.5, 1}}}, nil, "weights[1] must be between 0 and 1"},
+		{"Unknown preset", []CreateStyleOptions{{Preset: "random"}}, nil, "unknown weight preset"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			weights, err := styleImageWeights(3, tt.opts)
+
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.InDeltaSlice(t, tt.expected, weights, 1e-9)
+		})
+	}
+}
+
+func TestGaiaApi_CreateStyle_Weights(t *testing.T) {
+	imageUrls := []string{"https://example.com/image1.jpg", "https://example.com/image2.jpg"}
+
+	t.Run("Sends the preset weights", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		server.AddResponse("POST", "/api/sd-styles", testutil.MockResponse{StatusCode: 200, Body: SdStyle{Id: "style-123"}})
+
+		client := NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key"})
+		_, err := client.CreateStyle(context.Background(), imageUrls, "Test Style", nil, CreateStyleOptions{Preset: StyleWeightPresetEmphasizeFirst})
+		require.NoError(t, err)
+
+		requests := server.Requests("POST", "/api/sd-styles")
+		require.Len(t, requests, 1)
+
+		var payload struct {
+			Images []SdStyleImage `json:"images"`
+		}
+		require.NoError(t, json.Unmarshal(requests[0].Body, &payload))
+		assert.Equal(t, []SdStyleImage{
+			{Url: imageUrls[0], Weight: 1},
+			{Url: imageUrls[1], Weight: 0.5},
+		}, payload.Images)
+	})
+
+	t.Run("Invalid weights are not sent", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		client := NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key"})
+		_, err := client.CreateStyle(context.Background(), imageUrls, "Test Style", nil, CreateStyleOptions{Weights: []float64{1}})
+
+		require.Error(t, err)
+		assert.Empty(t, server.Requests("POST", "/api/sd-styles"))
+	})
+}
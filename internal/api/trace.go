@@ -0,0 +1,52 @@
+package api
+
+import (
+	"context"
+	"net/http"
+)
+
+const (
+	// RequestIdHeader carries the ID of the MCP tool call that triggered an API request
+	RequestIdHeader = "X-Request-Id"
+	// SessionIdHeader carries the ID of the MCP session that triggered an API request
+	SessionIdHeader = "X-Mcp-Session-Id"
+)
+
+// requestTraceContextKey carries the RequestTrace of the current MCP tool call
+type requestTraceContextKey struct{}
+
+// RequestTrace identifies the MCP tool call behind an API request, so the Gaia API
+// logs can be correlated with the MCP request that caused them
+type RequestTrace struct {
+	// SessionId is the MCP session ID. Empty when the transport has no session.
+	SessionId string
+	// RequestId uniquely identifies the tool call
+	RequestId string
+}
+
+// WithRequestTrace returns a copy of ctx carrying the trace.
+// API requests made with the returned context send the trace as headers.
+func WithRequestTrace(ctx context.Context, trace RequestTrace) context.Context {
+	return context.WithValue(ctx, requestTraceContextKey{}, trace)
+}
+
+// RequestTraceFromContext returns the trace stored in ctx, if any
+func RequestTraceFromContext(ctx context.Context) (RequestTrace, bool) {
+	trace, ok := ctx.Value(requestTraceContextKey{}).(RequestTrace)
+	return trace, ok
+}
+
+// requestTraceInterceptor sets the trace headers from the request context
+func requestTraceInterceptor(req *http.Request) error {
+	trace, ok := RequestTraceFromContext(req.Context())
+	if !ok {
+		return nil
+	}
+	if trace.RequestId != "" {
+		req.Header.Set(RequestIdHeader, trace.RequestId)
+	}
+	if trace.SessionId != "" {
+		req.Header.Set(SessionIdHeader, trace.SessionId)
+	}
+	return nil
+}
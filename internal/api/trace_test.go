@@ -0,0 +1,68 @@
+package api
+
+import (
+	"context"
+	"gaia-mcp-go/internal/testutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestTrace_Context(t *testing.T) {
+	_, ok := RequestTraceFromContext(context.Background())
+	assert.False(t, ok)
+
+	trace := RequestTrace{SessionId: "session-1", RequestId: "request-1"}
+	got, ok := RequestTraceFromContext(WithRequestTrace(context.Background(), trace))
+	require.True(t, ok)
+	assert.Equal(t, trace, got)
+}
+
+func TestGaiaApi_RequestTraceHeaders(t *testing.T) {
+	tests := []struct {
+		name              string
+		ctx               context.Context
+		expectedRequestId string
+		expectedSessionId string
+	}{
+		{
+			name: "Trace in context",
+			ctx: WithRequestTrace(context.Background(), RequestTrace{
+				SessionId: "session-1",
+				RequestId: "request-1",
+			}),
+			expectedRequestId: "request-1",
+			expectedSessionId: "session-1",
+		},
+		{
+			name:              "Request ID only",
+			ctx:               WithRequestTrace(context.Background(), RequestTrace{RequestId: "request-1"}),
+			expectedRequestId: "request-1",
+		},
+		{
+			name: "No trace",
+			ctx:  context.Background(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := testutil.NewTestServer()
+			defer server.Close()
+			server.AddResponse("GET", "/api/users/me", testutil.MockResponse{
+				StatusCode: 200,
+				Body:       User{Uid: "user-1"},
+			})
+
+			api := NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key"})
+			_, err := api.WhoAmI(tt.ctx)
+			require.NoError(t, err)
+
+			requests := server.Requests("GET", "/api/users/me")
+			require.Len(t, requests, 1)
+			assert.Equal(t, tt.expectedRequestId, requests[0].Headers.Get(RequestIdHeader))
+			assert.Equal(t, tt.expectedSessionId, requests[0].Headers.Get(SessionIdHeader))
+		})
+	}
+}
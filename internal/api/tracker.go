@@ -0,0 +1,120 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// TaskTracker wraps a GaiaApi and remembers the tasks created through it that
+// have not finished yet, so they can be cancelled when the server shuts down.
+//
+// A task is tracked when GenerateImages reports a task ID without returning
+// images, and untracked once it is observed in a terminal status or cancelled.
+//
+// Only tasks that are polled for their result (such as describe tasks) are
+// covered. The image recipes answer create-task once the images are ready, so
+// their task ID is unknown while they are in flight and they cannot be cancelled.
+type TaskTracker struct {
+	GaiaApi
+
+	mu      sync.Mutex
+	pending map[string]struct{}
+}
+
+// NewTaskTracker creates a TaskTracker around the given API client
+func NewTaskTracker(api GaiaApi) *TaskTracker {
+	return &TaskTracker{
+		GaiaApi: api,
+		pending: make(map[string]struct{}),
+	}
+}
+
+// GenerateImages submits the task and tracks it while it is still in progress
+func (t *TaskTracker) GenerateImages(ctx context.Context, req GenerateImagesRequest) (ImageGeneratedResponse, error) {
+	res, err := t.GaiaApi.GenerateImages(ctx, req)
+	if err == nil && res.TaskId != "" && res.Success && len(res.Images) == 0 {
+		t.track(res.TaskId)
+	}
+	return res, err
+}
+
+// GetTaskStatus fetches the task and stops tracking it once it has finished
+func (t *TaskTracker) GetTaskStatus(ctx context.Context, taskId string) (RecipeTask, error) {
+	task, err := t.GaiaApi.GetTaskStatus(ctx, taskId)
+	if err == nil && IsTerminalTaskStatus(task.Status) {
+		t.untrack(taskId)
+	}
+	return task, err
+}
+
+// WaitForTask waits for the task and stops tracking it once it has finished
+func (t *TaskTracker) WaitForTask(ctx context.Context, taskId string, pollInterval time.Duration) (RecipeTask, error) {
+	task, err := t.GaiaApi.WaitForTask(ctx, taskId, pollInterval)
+	if err == nil && IsTerminalTaskStatus(task.Status) {
+		t.untrack(taskId)
+	}
+	return task, err
+}
+
+// WaitForTaskWithOptions waits for the task and stops tracking it once it has finished
+func (t *TaskTracker) WaitForTaskWithOptions(ctx context.Context, taskId string, opts WaitOptions) (RecipeTask, error) {
+	task, err := t.GaiaApi.WaitForTaskWithOptions(ctx, taskId, opts)
+	if err == nil && IsTerminalTaskStatus(task.Status) {
+		t.untrack(taskId)
+	}
+	return task, err
+}
+
+// CancelTask cancels the task and stops tracking it
+func (t *TaskTracker) CancelTask(ctx context.Context, taskId string) error {
+	if err := t.GaiaApi.CancelTask(ctx, taskId); err != nil {
+		return err
+	}
+	t.untrack(taskId)
+	return nil
+}
+
+// PendingTasks returns the IDs of the tracked tasks, sorted for stable output
+func (t *TaskTracker) PendingTasks() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ids := make([]string, 0, len(t.pending))
+	for id := range t.pending {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// CancelPending attempts to cancel every tracked task.
+//
+// All tasks are attempted even if some cancellations fail; the failures are
+// joined into the returned error.
+func (t *TaskTracker) CancelPending(ctx context.Context) error {
+	var errs []error
+	for _, taskId := range t.PendingTasks() {
+		if err := t.CancelTask(ctx, taskId); err != nil {
+			errs = append(errs, fmt.Errorf("failed to cancel task %s: %w", taskId, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// track marks a task as pending
+func (t *TaskTracker) track(taskId string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending[taskId] = struct{}{}
+}
+
+// untrack removes a task from the pending set
+func (t *TaskTracker) untrack(taskId string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.pending, taskId)
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"context"
+	"gaia-mcp-go/internal/testutil"
+	"gaia-mcp-go/pkg/shared"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskTracker(t *testing.T) {
+	const createTaskPath = "/api/recipe/agi-tasks/create-task"
+
+	pendingResponse := func(taskId string) testutil.MockResponse {
+		return testutil.MockResponse{
+			StatusCode: 200,
+			Body:       ImageGeneratedResponse{Success: true, TaskId: taskId},
+		}
+	}
+
+	t.Run("Cancels pending tasks", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		server.AddResponseSequence("POST", createTaskPath, pendingResponse("task-1"), pendingResponse("task-2"))
+		server.AddResponse("POST", "/api/recipe/agi-tasks/task-1/cancel", testutil.MockResponse{StatusCode: 200})
+		server.AddResponse("POST", "/api/recipe/agi-tasks/task-2/cancel", testutil.MockResponse{StatusCode: 200})
+
+		tracker := NewTaskTracker(NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key"}))
+		for i := 0; i < 2; i++ {
+			_, err := tracker.GenerateImages(context.Background(), GenerateImagesRequest{RecipeId: shared.RecipeIdImageGeneratorSimple, Params: map[string]interface{}{"prompt": "a cat"}})
+			require.NoError(t, err)
+		}
+		assert.Equal(t, []string{"task-1", "task-2"}, tracker.PendingTasks())
+
+		require.NoError(t, tracker.CancelPending(context.Background()))
+
+		assert.Len(t, server.Requests("POST", "/api/recipe/agi-tasks/task-1/cancel"), 1)
+		assert.Len(t, server.Requests("POST", "/api/recipe/agi-tasks/task-2/cancel"), 1)
+		assert.Empty(t, tracker.PendingTasks())
+	})
+
+	t.Run("Completed tasks are not tracked", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		server.AddResponse("POST", createTaskPath, testutil.MockResponse{
+			StatusCode: 200,
+			Body:       ImageGeneratedResponse{Success: true, TaskId: "task-1", Images: []string{"image-url"}},
+		})
+
+		tracker := NewTaskTracker(NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key"}))
+		_, err := tracker.GenerateImages(context.Background(), GenerateImagesRequest{RecipeId: shared.RecipeIdImageGeneratorSimple, Params: map[string]interface{}{"prompt": "a cat"}})
+		require.NoError(t, err)
+
+		assert.Empty(t, tracker.PendingTasks())
+	})
+
+	t.Run("Generations in flight are not tracked", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		server.AddResponse("POST", createTaskPath, testutil.MockResponse{
+			StatusCode: 200,
+			Body:       ImageGeneratedResponse{Success: true, TaskId: "task-1", Images: []string{"image-url"}},
+			Delay:      200 * time.Millisecond,
+		})
+
+		tracker := NewTaskTracker(NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key"}))
+		done := make(chan error, 1)
+		go func() {
+			_, err := tracker.GenerateImages(context.Background(), GenerateImagesRequest{RecipeId: shared.RecipeIdImageGeneratorSimple, Params: map[string]interface{}{"prompt": "a cat"}})
+			done <- err
+		}()
+
+		// The task ID is only known once the images are ready, so there is nothing to cancel
+		require.Eventually(t, func() bool { return len(server.Requests("POST", createTaskPath)) == 1 }, time.Second, 5*time.Millisecond)
+		assert.Empty(t, tracker.PendingTasks())
+		require.NoError(t, tracker.CancelPending(context.Background()))
+
+		require.NoError(t, <-done)
+		assert.Empty(t, tracker.PendingTasks())
+		assert.Empty(t, server.Requests("POST", "/api/recipe/agi-tasks/task-1/cancel"))
+	})
+
+	t.Run("Tasks observed as finished are untracked", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		server.AddResponse("POST", createTaskPath, pendingResponse("task-1"))
+		server.AddResponse("GET", "/api/recipe/agi-tasks/task-1", testutil.MockResponse{
+			StatusCode: 200,
+			Body:       RecipeTask{Id: "task-1", Status: shared.RecipeTaskStatusCompleted},
+		})
+
+		tracker := NewTaskTracker(NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key"}))
+		_, err := tracker.GenerateImages(context.Background(), GenerateImagesRequest{RecipeId: shared.RecipeIdImageGeneratorSimple, Params: map[string]interface{}{"prompt": "a cat"}})
+		require.NoError(t, err)
+		require.Len(t, tracker.PendingTasks(), 1)
+
+		_, err = tracker.GetTaskStatus(context.Background(), "task-1")
+		require.NoError(t, err)
+
+		assert.Empty(t, tracker.PendingTasks())
+	})
+}
@@ -58,6 +58,33 @@ type InitUploadResponse struct {
 	File UploadFile `json:"file"`
 }
 
+// User represents the authenticated Gaia user that owns an API key
+type User struct {
+	// Uid is the unique identifier for the user
+	Uid string `json:"uid"`
+
+	// Name is the user's display name
+	Name string `json:"name"`
+
+	// Email is the user's email address
+	Email string `json:"email"`
+
+	// Username is the user's unique username
+	Username string `json:"username"`
+
+	// Picture is the URL to the user's profile picture
+	Picture string `json:"picture"`
+}
+
+// Account holds the billing state of the user that owns an API key
+type Account struct {
+	// Uid is the unique identifier for the user
+	Uid string `json:"uid"`
+
+	// Credits is the user's remaining GAIA CREDITS balance
+	Credits int `json:"credits"`
+}
+
 // SdStyleCreator represents a user who created a style
 type SdStyleCreator struct {
 	// Uid is the unique identifier for the user
@@ -152,6 +179,11 @@ const (
 	ThumbnailModerationUnsafe    ThumbnailModerationRating = "unsafe"
 )
 
+// IsFlagged reports whether the rating marks content as sensitive or unsafe
+func (r ThumbnailModerationRating) IsFlagged() bool {
+	return r == ThumbnailModerationSensitive || r == ThumbnailModerationUnsafe
+}
+
 // SharingMode represents how a style can be shared
 type SharingMode string
 
@@ -449,10 +481,45 @@ type RecipeTask struct {
 type ImageGeneratedResponse struct {
 	Success bool     `json:"success"`
 	Images  []string `json:"images"`
-	Error   *string  `json:"error,omitempty"`
+	// OriginImages holds the origin url of each image, in the same order as Images,
+	// when reported by the API. Images served from the CDN can briefly 404 until they
+	// propagate, while the origin already has them.
+	OriginImages []string `json:"originImages,omitempty"`
+	Error        *string  `json:"error,omitempty"`
+	// TaskId is the ID of the created task, when reported by the API
+	TaskId string `json:"taskId,omitempty"`
+	// ModerationRatings holds the moderation rating of each image, in the same order as Images
+	ModerationRatings []ThumbnailModerationRating `json:"moderationRatings,omitempty"`
+	// QueueType is the processing queue the task was assigned to, when reported by the API.
+	// Faster queues such as "fast" or "dedicated" usually mean a shorter wait.
+	QueueType shared.QueueType `json:"queueType,omitempty"`
+	// Priority is the execution priority of the task (higher numbers = higher priority),
+	// when reported by the API
+	Priority int `json:"priority,omitempty"`
+}
+
+// Fallbacks returns the alternate urls of the image at index i, to try in
+// order when its primary url in Images cannot be downloaded
+func (r ImageGeneratedResponse) Fallbacks(i int) []string {
+	if i < 0 || i >= len(r.OriginImages) {
+		return nil
+	}
+
+	origin := r.OriginImages[i]
+	if origin == "" || (i < len(r.Images) && origin == r.Images[i]) {
+		return nil
+	}
+	return []string{origin}
 }
 
 type GenerateImagesRequest struct {
 	RecipeId shared.RecipeId        `json:"recipeId"`
 	Params   map[string]interface{} `json:"params"`
+	// RecipeType selects a variant of the recipe, such as turbo. Empty uses the recipe's default.
+	RecipeType shared.RecipeType `json:"recipeType,omitempty"`
+	// QueueType requests a processing queue, such as fast. Empty uses the default queue.
+	QueueType shared.QueueType `json:"queueType,omitempty"`
+	// IdempotencyKey is sent as the Idempotency-Key header so the server can
+	// dedupe retried submissions. Generated automatically when empty.
+	IdempotencyKey string `json:"-"`
 }
@@ -0,0 +1,617 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"gaia-mcp-go/internal/testutil"
+	"gaia-mcp-go/pkg/imageutil"
+	"gaia-mcp-go/pkg/retry"
+	"gaia-mcp-go/pkg/shared"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGaiaApi_UploadChunks_CancelsOnFirstFailure(t *testing.T) {
+	cancelled := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Drain the body so the server notices when the client disconnects
+		io.Copy(io.Discard, r.Body)
+
+		switch r.URL.Path {
+		case "/part-1":
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("expired"))
+		case "/part-2":
+			// Hold the chunk upload open until the client gives up on it
+			select {
+			case <-r.Context().Done():
+				close(cancelled)
+			case <-time.After(10 * time.Second):
+				w.Header().Set("ETag", "etag-2")
+			}
+		}
+	}))
+	defer server.Close()
+
+	api := &gaiaApi{}
+	imageData := make([]byte, shared.UPLOAD_CHUNK_SIZE+1)
+
+	start := time.Now()
+	parts, err := api.uploadChunks(context.Background(), imageData, []string{
+		server.URL + "/part-1",
+		server.URL + "/part-2",
+	}, nil)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "chunk 1")
+	assert.Nil(t, parts)
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("remaining chunk upload was not cancelled")
+	}
+}
+
+func TestGaiaApi_UploadChunks_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", "etag"+r.URL.Path)
+	}))
+	defer server.Close()
+
+	api := &gaiaApi{}
+	imageData := make([]byte, shared.UPLOAD_CHUNK_SIZE+1)
+
+	parts, err := api.uploadChunks(context.Background(), imageData, []string{
+		server.URL + "/1",
+		server.URL + "/2",
+	}, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, []UploadPart{
+		{ETag: "etag/1", PartNumber: 1},
+		{ETag: "etag/2", PartNumber: 2},
+	}, parts)
+}
+
+// expiredUrlBody is the error S3 returns for a presigned URL used after it expired
+const expiredUrlBody = `<?xml version="1.0" encoding="UTF-8"?><Error><Code>AccessDenied</Code><Message>Request has expired</Message></Error>`
+
+// initUploadResponse returns an upload initialization mock whose single chunk uploads to chunkPath
+func initUploadResponse(server *testutil.TestServer, uploadId, chunkPath string) testutil.MockResponse {
+	fileUrl := shared.CDN_URL + "/uploads/" + uploadId + ".png"
+	return testutil.MockResponse{
+		StatusCode: http.StatusOK,
+		Body: []InitUploadResponse{{
+			Key:        "upload-key",
+			UploadId:   uploadId,
+			UploadUrls: []string{server.URL + chunkPath},
+			File:       UploadFile{Id: uploadId, Url: &fileUrl},
+		}},
+	}
+}
+
+func TestGaiaApi_UploadImages_ReinitializesExpiredUrls(t *testing.T) {
+	expired := testutil.MockResponse{StatusCode: http.StatusForbidden, Body: expiredUrlBody}
+	uploaded := testutil.MockResponse{StatusCode: http.StatusOK, Headers: map[string]string{"ETag": "etag-1"}}
+
+	t.Run("Expired chunk URL is replaced by a fresh upload", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		server.AddResponse("GET", "/image.png", testutil.MockResponse{StatusCode: http.StatusOK, Body: testutil.CreateMockImage()})
+		server.AddResponseSequence("POST", "/api/upload/initialize",
+			initUploadResponse(server, "upload-1", "/chunks/upload-1"),
+			initUploadResponse(server, "upload-2", "/chunks/upload-2"),
+		)
+		server.AddResponse("PUT", "/chunks/upload-1", expired)
+		server.AddResponse("PUT", "/chunks/upload-2", uploaded)
+		server.AddResponse("POST", "/api/upload/complete", testutil.MockResponse{StatusCode: http.StatusOK})
+
+		client := NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key"})
+		files, err := client.UploadImages(context.Background(), []string{server.URL + "/image.png"}, shared.FileAssociatedResourceStyle)
+
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+		assert.Equal(t, "upload-2", files[0].Id)
+		assert.Len(t, server.Requests("POST", "/api/upload/initialize"), 2)
+
+		completes := server.Requests("POST", "/api/upload/complete")
+		require.Len(t, completes, 1)
+		assert.Contains(t, string(completes[0].Body), `"uploadId":"upload-2"`)
+	})
+
+	t.Run("Re-initialization is bounded", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		server.AddResponse("GET", "/image.png", testutil.MockResponse{StatusCode: http.StatusOK, Body: testutil.CreateMockImage()})
+		server.AddResponse("POST", "/api/upload/initialize", initUploadResponse(server, "upload-1", "/chunks/upload-1"))
+		server.AddResponse("PUT", "/chunks/upload-1", expired)
+
+		client := NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key"})
+		_, err := client.UploadImages(context.Background(), []string{server.URL + "/image.png"}, shared.FileAssociatedResourceStyle)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "presigned upload URL expired")
+		assert.Len(t, server.Requests("POST", "/api/upload/initialize"), maxUploadReinits+1)
+		assert.Empty(t, server.Requests("POST", "/api/upload/complete"))
+	})
+
+	t.Run("Other forbidden responses are not retried", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		server.AddResponse("GET", "/image.png", testutil.MockResponse{StatusCode: http.StatusOK, Body: testutil.CreateMockImage()})
+		server.AddResponse("POST", "/api/upload/initialize", initUploadResponse(server, "upload-1", "/chunks/upload-1"))
+		server.AddResponse("PUT", "/chunks/upload-1", testutil.MockResponse{StatusCode: http.StatusForbidden, Body: "<Error><Code>AccessDenied</Code><Message>Access Denied</Message></Error>"})
+
+		client := NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key"})
+		_, err := client.UploadImages(context.Background(), []string{server.URL + "/image.png"}, shared.FileAssociatedResourceStyle)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "status 403")
+		assert.Len(t, server.Requests("POST", "/api/upload/initialize"), 1)
+	})
+}
+
+func TestIsExpiredPresignedUrl(t *testing.T) {
+	assert.True(t, isExpiredPresignedUrl(http.StatusForbidden, []byte(expiredUrlBody)))
+	assert.True(t, isExpiredPresignedUrl(http.StatusForbidden, []byte("<Error><Code>ExpiredToken</Code></Error>")))
+	assert.False(t, isExpiredPresignedUrl(http.StatusForbidden, []byte("Access Denied")))
+	assert.False(t, isExpiredPresignedUrl(http.StatusBadRequest, []byte(expiredUrlBody)))
+}
+
+// uploadServer serves images of width 1..n at /image-<width>.png and accepts
+// their uploads, recording the peak number of images downloaded at once
+type uploadServer struct {
+	*httptest.Server
+	inFlight atomic.Int32
+	peak     atomic.Int32
+
+	// uploadedBytes counts the chunk bytes received
+	uploadedBytes atomic.Int64
+}
+
+// newUploadServer starts an uploadServer whose image downloads take delay
+func newUploadServer(tb testing.TB, delay time.Duration) *uploadServer {
+	tb.Helper()
+
+	s := &uploadServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet:
+			var width int
+			if _, err := fmt.Sscanf(r.URL.Path, "/image-%d.png", &width); err != nil {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			current := s.inFlight.Add(1)
+			defer s.inFlight.Add(-1)
+			for {
+				peak := s.peak.Load()
+				if current <= peak || s.peak.CompareAndSwap(peak, current) {
+					break
+				}
+			}
+			time.Sleep(delay)
+
+			img := image.NewRGBA(image.Rect(0, 0, width, 1))
+			w.Header().Set("Content-Type", "image/png")
+			png.Encode(w, img)
+		case r.URL.Path == "/api/upload/initialize":
+			var payload struct {
+				Files []struct {
+					Metadata struct {
+						Width int `json:"width"`
+					} `json:"metadata"`
+				} `json:"files"`
+			}
+			json.NewDecoder(r.Body).Decode(&payload)
+
+			id := fmt.Sprintf("upload-%d", payload.Files[0].Metadata.Width)
+			json.NewEncoder(w).Encode([]InitUploadResponse{{
+				Key:        id,
+				UploadId:   id,
+				UploadUrls: []string{s.URL + "/chunks/" + id},
+				File:       UploadFile{Id: id},
+			}})
+		case r.Method == http.MethodPut:
+			n, _ := io.Copy(io.Discard, r.Body)
+			s.uploadedBytes.Add(n)
+			w.Header().Set("ETag", "etag")
+		}
+	}))
+	tb.Cleanup(s.Close)
+	return s
+}
+
+// imageUrls returns the urls of the images with widths 1..n
+func (s *uploadServer) imageUrls(n int) []string {
+	urls := make([]string, n)
+	for i := range urls {
+		urls[i] = fmt.Sprintf("%s/image-%d.png", s.URL, i+1)
+	}
+	return urls
+}
+
+func TestGaiaApi_UploadImages_Concurrency(t *testing.T) {
+	t.Run("Images are uploaded concurrently up to the limit", func(t *testing.T) {
+		server := newUploadServer(t, 20*time.Millisecond)
+
+		client := NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key", MaxConcurrentImages: 3})
+		files, err := client.UploadImages(context.Background(), server.imageUrls(8), shared.FileAssociatedResourceStyle)
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(3), server.peak.Load())
+
+		// Files are returned in input order whichever finished first
+		require.Len(t, files, 8)
+		for i, file := range files {
+			assert.Equal(t, fmt.Sprintf("upload-%d", i+1), file.Id)
+		}
+	})
+
+	t.Run("A limit of one uploads sequentially", func(t *testing.T) {
+		server := newUploadServer(t, 5*time.Millisecond)
+
+		client := NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key", MaxConcurrentImages: 1})
+		_, err := client.UploadImages(context.Background(), server.imageUrls(4), shared.FileAssociatedResourceStyle)
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), server.peak.Load())
+	})
+
+	t.Run("Failures are reported in input order", func(t *testing.T) {
+		server := newUploadServer(t, 0)
+		urls := append(server.imageUrls(2), "ftp://example.com/image.png", server.URL+"/missing.png")
+
+		client := NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key"})
+		files, err := client.UploadImages(context.Background(), urls, shared.FileAssociatedResourceStyle)
+
+		require.Error(t, err)
+		assert.Nil(t, files)
+		assert.Contains(t, err.Error(), "failed to upload some files")
+
+		ftp := strings.Index(err.Error(), "ftp://example.com/image.png")
+		missing := strings.Index(err.Error(), "/missing.png")
+		require.NotEqual(t, -1, ftp)
+		require.NotEqual(t, -1, missing)
+		assert.Less(t, ftp, missing)
+		assert.NotContains(t, err.Error(), "image-1.png")
+	})
+}
+
+func BenchmarkGaiaApi_UploadImages(b *testing.B) {
+	for _, limit := range []int{1, 10} {
+		b.Run(fmt.Sprintf("MaxConcurrentImages=%d", limit), func(b *testing.B) {
+			server := newUploadServer(b, 2*time.Millisecond)
+			urls := server.imageUrls(10)
+
+			client := NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "benchmark-key", MaxConcurrentImages: limit})
+			ctx := context.Background()
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := client.UploadImages(ctx, urls, shared.FileAssociatedResourceStyle); err != nil {
+					b.Fatalf("Benchmark failed: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestGaiaApi_UploadImages_PreservesMimeType(t *testing.T) {
+	var jpegImage bytes.Buffer
+	require.NoError(t, jpeg.Encode(&jpegImage, image.NewRGBA(image.Rect(0, 0, 4, 2)), nil))
+
+	tests := []struct {
+		name             string
+		path             string
+		body             []byte
+		expectedMimeType string
+		expectedExt      string
+	}{
+		{name: "JPEG", path: "/photo.jpg", body: jpegImage.Bytes(), expectedMimeType: "image/jpeg", expectedExt: ".jpg"},
+		{name: "PNG", path: "/image.png", body: testutil.CreateMockImage(), expectedMimeType: "image/png", expectedExt: ".png"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := testutil.NewTestServer()
+			defer server.Close()
+
+			server.AddResponse("GET", tt.path, testutil.MockResponse{StatusCode: http.StatusOK, Body: tt.body})
+			server.AddResponse("POST", "/api/upload/initialize", initUploadResponse(server, "upload-1", "/chunks/upload-1"))
+			server.AddResponse("PUT", "/chunks/upload-1", testutil.MockResponse{StatusCode: http.StatusOK, Headers: map[string]string{"ETag": "etag-1"}})
+			server.AddResponse("POST", "/api/upload/complete", testutil.MockResponse{StatusCode: http.StatusOK})
+
+			client := NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key"})
+			_, err := client.UploadImages(context.Background(), []string{server.URL + tt.path}, shared.FileAssociatedResourceStyle)
+			require.NoError(t, err)
+
+			inits := server.Requests("POST", "/api/upload/initialize")
+			require.Len(t, inits, 1)
+
+			var payload struct {
+				Files []struct {
+					Filename string `json:"filename"`
+					Mimetype string `json:"mimetype"`
+				} `json:"files"`
+			}
+			require.NoError(t, json.Unmarshal(inits[0].Body, &payload))
+			require.Len(t, payload.Files, 1)
+			assert.Equal(t, tt.expectedMimeType, payload.Files[0].Mimetype)
+			assert.True(t, strings.HasSuffix(payload.Files[0].Filename, tt.expectedExt), payload.Files[0].Filename)
+		})
+	}
+}
+
+func TestGaiaApi_UploadImages_AppliesOrientation(t *testing.T) {
+	tests := []struct {
+		name        string
+		orientation int
+	}{
+		{name: "rotated clockwise", orientation: 6},
+		{name: "rotated counter-clockwise", orientation: 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := testutil.NewTestServer()
+			defer server.Close()
+
+			// A landscape 8x4 photo whose EXIF tag says it is displayed as a 4x8 portrait
+			server.AddResponse("GET", "/photo.jpg", testutil.MockResponse{
+				StatusCode: http.StatusOK,
+				Body:       testutil.CreateMockOrientedJPEG(8, 4, tt.orientation),
+			})
+			server.AddResponse("POST", "/api/upload/initialize", initUploadResponse(server, "upload-1", "/chunks/upload-1"))
+			server.AddResponse("PUT", "/chunks/upload-1", testutil.MockResponse{StatusCode: http.StatusOK, Headers: map[string]string{"ETag": "etag-1"}})
+			server.AddResponse("POST", "/api/upload/complete", testutil.MockResponse{StatusCode: http.StatusOK})
+
+			client := NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key"})
+			_, err := client.UploadImages(context.Background(), []string{server.URL + "/photo.jpg"}, shared.FileAssociatedResourceStyle)
+			require.NoError(t, err)
+
+			inits := server.Requests("POST", "/api/upload/initialize")
+			require.Len(t, inits, 1)
+
+			var payload struct {
+				Files []struct {
+					Metadata struct {
+						Width  int `json:"width"`
+						Height int `json:"height"`
+					} `json:"metadata"`
+				} `json:"files"`
+			}
+			require.NoError(t, json.Unmarshal(inits[0].Body, &payload))
+			require.Len(t, payload.Files, 1)
+			assert.Equal(t, 4, payload.Files[0].Metadata.Width)
+			assert.Equal(t, 8, payload.Files[0].Metadata.Height)
+
+			// The stored image is upright and no longer relies on an orientation tag
+			chunks := server.Requests("PUT", "/chunks/upload-1")
+			require.Len(t, chunks, 1)
+			assert.Equal(t, 1, imageutil.ReadOrientation(chunks[0].Body))
+			stored, err := jpeg.DecodeConfig(bytes.NewReader(chunks[0].Body))
+			require.NoError(t, err)
+			assert.Equal(t, 4, stored.Width)
+			assert.Equal(t, 8, stored.Height)
+		})
+	}
+}
+
+func TestGaiaApi_UploadChunk_Retries(t *testing.T) {
+	// chunkServer fails the first len(failures) PUTs with the given statuses
+	// (0 drops the connection) and records the bodies it received
+	chunkServer := func(t *testing.T, failures ...int) (*httptest.Server, *[]string) {
+		var mu sync.Mutex
+		var bodies []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+
+			mu.Lock()
+			attempt := len(bodies)
+			bodies = append(bodies, string(body))
+			mu.Unlock()
+
+			if attempt < len(failures) {
+				if failures[attempt] == 0 {
+					conn, _, _ := w.(http.Hijacker).Hijack()
+					conn.Close()
+					return
+				}
+				w.WriteHeader(failures[attempt])
+				return
+			}
+			w.Header().Set("ETag", "etag-1")
+		}))
+		t.Cleanup(server.Close)
+		return server, &bodies
+	}
+
+	chunk := []byte("chunk data")
+
+	t.Run("Transient failures are retried", func(t *testing.T) {
+		server, bodies := chunkServer(t, http.StatusInternalServerError, http.StatusBadGateway)
+
+		api := &gaiaApi{}
+		part, err := api.uploadChunk(context.Background(), chunk, server.URL, 1)
+
+		require.NoError(t, err)
+		assert.Equal(t, &UploadPart{ETag: "etag-1", PartNumber: 1}, part)
+
+		// The whole chunk is sent on every attempt
+		assert.Equal(t, []string{"chunk data", "chunk data", "chunk data"}, *bodies)
+	})
+
+	t.Run("Network errors are retried", func(t *testing.T) {
+		server, bodies := chunkServer(t, 0)
+
+		api := &gaiaApi{}
+		part, err := api.uploadChunk(context.Background(), chunk, server.URL, 1)
+
+		require.NoError(t, err)
+		assert.Equal(t, "etag-1", part.ETag)
+		assert.Len(t, *bodies, 2)
+	})
+
+	t.Run("Client errors are not retried", func(t *testing.T) {
+		server, bodies := chunkServer(t, http.StatusBadRequest)
+
+		api := &gaiaApi{}
+		_, err := api.uploadChunk(context.Background(), chunk, server.URL, 1)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "status 400")
+		assert.Len(t, *bodies, 1)
+	})
+
+	t.Run("Attempts are bounded", func(t *testing.T) {
+		server, bodies := chunkServer(t, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+
+		api := &gaiaApi{chunkUploadAttempts: 2}
+		_, err := api.uploadChunk(context.Background(), chunk, server.URL, 1)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "status 503")
+		assert.Len(t, *bodies, 2)
+	})
+
+	t.Run("Slow uploads time out", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(200 * time.Millisecond)
+			w.Header().Set("ETag", "etag-1")
+		}))
+		t.Cleanup(server.Close)
+
+		api := &gaiaApi{uploadTimeout: 20 * time.Millisecond, chunkUploadAttempts: 1}
+		_, err := api.uploadChunk(context.Background(), chunk, server.URL, 1)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Client.Timeout exceeded")
+
+		api.uploadTimeout = 5 * time.Second
+		part, err := api.uploadChunk(context.Background(), chunk, server.URL, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "etag-1", part.ETag)
+	})
+
+	t.Run("Cancellation stops the retries", func(t *testing.T) {
+		server, bodies := chunkServer(t, http.StatusInternalServerError)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(20*time.Millisecond, cancel)
+
+		api := &gaiaApi{chunkBackoff: retry.Backoff{Initial: time.Hour}}
+		_, err := api.uploadChunk(ctx, chunk, server.URL, 1)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Len(t, *bodies, 1)
+	})
+}
+
+func TestGaiaApi_UploadImages_Progress(t *testing.T) {
+	t.Run("Reports increasing progress up to the total", func(t *testing.T) {
+		server := newUploadServer(t, 0)
+
+		var mu sync.Mutex
+		var uploaded, totals []int64
+		client := NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key"})
+		_, err := client.UploadImages(context.Background(), server.imageUrls(5), shared.FileAssociatedResourceStyle, UploadOptions{
+			Progress: func(sent, total int64) {
+				mu.Lock()
+				defer mu.Unlock()
+				uploaded = append(uploaded, sent)
+				totals = append(totals, total)
+			},
+		})
+		require.NoError(t, err)
+
+		// One event per chunk; every test image fits in one chunk
+		require.Len(t, uploaded, 5)
+		for i := 1; i < len(uploaded); i++ {
+			assert.Greater(t, uploaded[i], uploaded[i-1])
+			assert.GreaterOrEqual(t, totals[i], totals[i-1])
+		}
+		for i := range uploaded {
+			assert.LessOrEqual(t, uploaded[i], totals[i])
+		}
+
+		last := len(uploaded) - 1
+		assert.Equal(t, totals[last], uploaded[last])
+		assert.Equal(t, server.uploadedBytes.Load(), uploaded[last])
+	})
+
+	t.Run("Chunks of a large image are aggregated", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.Copy(io.Discard, r.Body)
+			w.Header().Set("ETag", "etag"+r.URL.Path)
+		}))
+		defer server.Close()
+
+		var events [][2]int64
+		progress := &uploadProgress{report: func(uploaded, total int64) {
+			events = append(events, [2]int64{uploaded, total})
+		}}
+
+		imageData := make([]byte, 2*shared.UPLOAD_CHUNK_SIZE+10)
+		progress.expect(int64(len(imageData)))
+
+		api := &gaiaApi{}
+		_, err := api.uploadChunks(context.Background(), imageData, []string{
+			server.URL + "/1",
+			server.URL + "/2",
+			server.URL + "/3",
+		}, progress)
+
+		require.NoError(t, err)
+		require.Len(t, events, 3)
+		assert.Equal(t, [2]int64{int64(len(imageData)), int64(len(imageData))}, events[2])
+	})
+
+	t.Run("No progress is reported after a failure", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.Copy(io.Discard, r.Body)
+			if r.URL.Path == "/1" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			// Hold the other chunk until the failure cancels it
+			<-r.Context().Done()
+		}))
+		defer server.Close()
+
+		var events int
+		progress := &uploadProgress{report: func(uploaded, total int64) { events++ }}
+
+		api := &gaiaApi{}
+		_, err := api.uploadChunks(context.Background(), make([]byte, shared.UPLOAD_CHUNK_SIZE+1), []string{
+			server.URL + "/1",
+			server.URL + "/2",
+		}, progress)
+
+		require.Error(t, err)
+		progress.advance(1)
+		assert.Zero(t, events)
+	})
+}
@@ -0,0 +1,157 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"gaia-mcp-go/pkg/shared"
+	"strings"
+)
+
+// ErrInvalidParams is matched (via errors.Is) by every InvalidParamError
+var ErrInvalidParams = errors.New("invalid recipe params")
+
+// InvalidParamError is returned when a generation request fails local
+// validation, before the task is submitted
+type InvalidParamError struct {
+	// RecipeId is the recipe of the rejected request
+	RecipeId shared.RecipeId
+	// Param is the name of the invalid param
+	Param string
+	// Reason describes the violated constraint, e.g. "is required"
+	Reason string
+}
+
+// Error implements the error interface for InvalidParamError
+func (e *InvalidParamError) Error() string {
+	return fmt.Sprintf("invalid %s params: %s %s", e.RecipeId, e.Param, e.Reason)
+}
+
+// Is reports whether the target is ErrInvalidParams
+func (e *InvalidParamError) Is(target error) bool {
+	return target == ErrInvalidParams
+}
+
+// paramRule constrains one recipe param
+type paramRule struct {
+	// name is the param key
+	name string
+	// required rejects requests without the param, or with an empty string
+	required bool
+	// text requires a string value
+	text bool
+	// enum lists the allowed string values, when set
+	enum []string
+	// number requires a numeric value within min and max
+	number   bool
+	min, max float64
+	// integer requires a whole number
+	integer bool
+}
+
+// recipeParamRules lists the param constraints per known recipe. Params not
+// listed are passed through unchecked, so new backend params keep working.
+var recipeParamRules = map[shared.RecipeId][]paramRule{
+	shared.RecipeIdImageGeneratorSimple: {
+		{name: "prompt", required: true, text: true},
+		{name: "numberOfImages", number: true, integer: true, min: 1, max: 4},
+		{name: "width", number: true, integer: true, min: 256, max: 2048},
+		{name: "height", number: true, integer: true, min: 256, max: 2048},
+		{name: "seed", number: true, integer: true, min: 0, max: 1 << 53},
+	},
+	shared.RecipeIdRemix: {
+		{name: "inputImage", required: true, text: true},
+		{name: "variationControl", text: true, enum: []string{"subtle", "medium", "strong"}},
+		{name: "numberOfImages", number: true, integer: true, min: 1, max: 4},
+	},
+	shared.RecipeIdFaceEnhancer: {
+		{name: "imageUrl", required: true, text: true},
+	},
+	shared.RecipeIdUpscaler: {
+		{name: "image", required: true, text: true},
+		{name: "upscale_ratio", number: true, min: 1, max: 4},
+	},
+}
+
+// recipeTypeParamRules lists the param constraints of recipe types whose params
+// differ from those of their recipe. They replace the recipe's rules.
+var recipeTypeParamRules = map[shared.RecipeType][]paramRule{
+	shared.RecipeTypeDescribe: {
+		{name: "image", required: true, text: true},
+	},
+	shared.RecipeTypeInpaint: {
+		{name: "image", required: true, text: true},
+		{name: "mask", required: true, text: true},
+		{name: "prompt", text: true},
+	},
+}
+
+// ValidateGenerateRequest checks the params of a generation request against
+// the known constraints of its recipe: required params and value ranges, such
+// as an upscale_ratio between 1 and 4.
+//
+// Requests for recipes without known constraints are not checked, so that
+// integrators can use recipes this client does not know about yet.
+//
+// Returns an *InvalidParamError for the first invalid param, or nil.
+func ValidateGenerateRequest(req GenerateImagesRequest) error {
+	rules, ok := recipeTypeParamRules[req.RecipeType]
+	if !ok {
+		rules = recipeParamRules[req.RecipeId]
+	}
+
+	for _, rule := range rules {
+		if reason := rule.check(req.Params); reason != "" {
+			return &InvalidParamError{RecipeId: req.RecipeId, Param: rule.name, Reason: reason}
+		}
+	}
+	return nil
+}
+
+// check returns why the param violates the rule, or "" when it is valid
+func (r paramRule) check(params map[string]interface{}) string {
+	value, exists := params[r.name]
+	if !exists || value == nil {
+		if r.required {
+			return "is required"
+		}
+		return ""
+	}
+
+	if r.text {
+		text, ok := value.(string)
+		if !ok {
+			return "must be a string"
+		}
+		if r.required && strings.TrimSpace(text) == "" {
+			return "is required"
+		}
+		if len(r.enum) > 0 && !contains(r.enum, text) {
+			return fmt.Sprintf("must be one of: %s", strings.Join(r.enum, ", "))
+		}
+	}
+
+	if r.number {
+		number, ok := numberParam(params, r.name)
+		if !ok {
+			return "must be a number"
+		}
+		if r.integer && number != float64(int64(number)) {
+			return "must be an integer"
+		}
+		if number < r.min || number > r.max {
+			return fmt.Sprintf("must be between %g and %g", r.min, r.max)
+		}
+	}
+
+	return ""
+}
+
+// contains reports whether values contains value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"gaia-mcp-go/internal/testutil"
+	"gaia-mcp-go/pkg/shared"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateGenerateRequest(t *testing.T) {
+	tests := []struct {
+		name          string
+		recipeId      shared.RecipeId
+		recipeType    shared.RecipeType
+		params        map[string]interface{}
+		expectedParam string
+	}{
+		// Required params of each known recipe
+		{"Simple with prompt", shared.RecipeIdImageGeneratorSimple, "", map[string]interface{}{"prompt": "a cat"}, ""},
+		{"Simple without prompt", shared.RecipeIdImageGeneratorSimple, "", map[string]interface{}{}, "prompt"},
+		{"Simple with blank prompt", shared.RecipeIdImageGeneratorSimple, "", map[string]interface{}{"prompt": "  "}, "prompt"},
+		{"Remix with input image", shared.RecipeIdRemix, "", map[string]interface{}{"inputImage": "https://cdn.protogaia.com/a.png"}, ""},
+		{"Remix without input image", shared.RecipeIdRemix, "", map[string]interface{}{"variationControl": "subtle"}, "inputImage"},
+		{"Face enhancer with image url", shared.RecipeIdFaceEnhancer, "", map[string]interface{}{"imageUrl": "https://cdn.protogaia.com/a.png"}, ""},
+		{"Face enhancer without image url", shared.RecipeIdFaceEnhancer, "", map[string]interface{}{}, "imageUrl"},
+		{"Upscaler with image", shared.RecipeIdUpscaler, "", map[string]interface{}{"image": "https://cdn.protogaia.com/a.png"}, ""},
+		{"Upscaler without image", shared.RecipeIdUpscaler, "", map[string]interface{}{"upscale_ratio": 2}, "image"},
+		{"Describe with image", shared.RecipeIdImageGeneratorSimple, shared.RecipeTypeDescribe, map[string]interface{}{"image": "https://cdn.protogaia.com/a.png"}, ""},
+		{"Describe without image", shared.RecipeIdImageGeneratorSimple, shared.RecipeTypeDescribe, map[string]interface{}{}, "image"},
+		{"Turbo uses the recipe's rules", shared.RecipeIdImageGeneratorSimple, shared.RecipeTypeTurbo, map[string]interface{}{}, "prompt"},
+		{"Inpaint with image and mask", shared.RecipeIdImageGeneratorSimple, shared.RecipeTypeInpaint, map[string]interface{}{"image": "u", "mask": "m", "prompt": "a hat"}, ""},
+		{"Inpaint without mask", shared.RecipeIdImageGeneratorSimple, shared.RecipeTypeInpaint, map[string]interface{}{"image": "u"}, "mask"},
+
+		// Value ranges and types
+		{"Upscale ratio in range", shared.RecipeIdUpscaler, "", map[string]interface{}{"image": "u", "upscale_ratio": 2.5}, ""},
+		{"Upscale ratio below range", shared.RecipeIdUpscaler, "", map[string]interface{}{"image": "u", "upscale_ratio": 0.5}, "upscale_ratio"},
+		{"Upscale ratio above range", shared.RecipeIdUpscaler, "", map[string]interface{}{"image": "u", "upscale_ratio": 5}, "upscale_ratio"},
+		{"Upscale ratio not a number", shared.RecipeIdUpscaler, "", map[string]interface{}{"image": "u", "upscale_ratio": "2"}, "upscale_ratio"},
+		{"Number of images from JSON", shared.RecipeIdImageGeneratorSimple, "", map[string]interface{}{"prompt": "p", "numberOfImages": float64(4)}, ""},
+		{"Too many images", shared.RecipeIdImageGeneratorSimple, "", map[string]interface{}{"prompt": "p", "numberOfImages": 5}, "numberOfImages"},
+		{"Fractional number of images", shared.RecipeIdImageGeneratorSimple, "", map[string]interface{}{"prompt": "p", "numberOfImages": 1.5}, "numberOfImages"},
+		{"Width below range", shared.RecipeIdImageGeneratorSimple, "", map[string]interface{}{"prompt": "p", "width": 64}, "width"},
+		{"Negative seed", shared.RecipeIdImageGeneratorSimple, "", map[string]interface{}{"prompt": "p", "seed": int64(-1)}, "seed"},
+		{"Unknown variation control", shared.RecipeIdRemix, "", map[string]interface{}{"inputImage": "u", "variationControl": "wild"}, "variationControl"},
+		{"Non-string prompt", shared.RecipeIdImageGeneratorSimple, "", map[string]interface{}{"prompt": 42}, "prompt"},
+
+		// Recipes without known constraints
+		{"Unknown recipe", shared.RecipeId("future-recipe"), "", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateGenerateRequest(GenerateImagesRequest{RecipeId: tt.recipeId, RecipeType: tt.recipeType, Params: tt.params})
+
+			if tt.expectedParam == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, ErrInvalidParams))
+
+			var invalid *InvalidParamError
+			require.True(t, errors.As(err, &invalid))
+			assert.Equal(t, tt.recipeId, invalid.RecipeId)
+			assert.Equal(t, tt.expectedParam, invalid.Param)
+		})
+	}
+
+	t.Run("Error message names the recipe, param and constraint", func(t *testing.T) {
+		err := ValidateGenerateRequest(GenerateImagesRequest{
+			RecipeId: shared.RecipeIdUpscaler,
+			Params:   map[string]interface{}{"image": "u", "upscale_ratio": 8},
+		})
+
+		require.Error(t, err)
+		assert.Equal(t, "invalid upscaler params: upscale_ratio must be between 1 and 4", err.Error())
+	})
+}
+
+func TestGaiaApi_GenerateImages_ValidatesParams(t *testing.T) {
+	server := testutil.NewTestServer()
+	defer server.Close()
+
+	api := NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key"})
+	_, err := api.GenerateImages(context.Background(), GenerateImagesRequest{
+		RecipeId: shared.RecipeIdUpscaler,
+		Params:   map[string]interface{}{"image": "https://cdn.protogaia.com/a.png", "upscale_ratio": 8},
+	})
+
+	require.ErrorIs(t, err, ErrInvalidParams)
+	assert.Empty(t, server.Requests("POST", "/api/recipe/agi-tasks/create-task"))
+}
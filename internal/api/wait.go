@@ -0,0 +1,197 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"gaia-mcp-go/pkg/retry"
+	"gaia-mcp-go/pkg/shared"
+	"time"
+)
+
+// PollStrategy controls how the interval between task status polls evolves
+type PollStrategy string
+
+const (
+	// PollStrategyFixed polls at a constant Interval
+	PollStrategyFixed PollStrategy = "fixed"
+	// PollStrategyLinear adds Interval after every poll (Interval, 2*Interval, ...)
+	PollStrategyLinear PollStrategy = "linear"
+	// PollStrategyExponential multiplies the interval by Multiplier after every poll
+	PollStrategyExponential PollStrategy = "exponential"
+)
+
+const (
+	// DefaultPollInterval is the first interval used when WaitOptions.Interval is not set
+	DefaultPollInterval = 2 * time.Second
+	// DefaultMaxPollInterval caps growing intervals when WaitOptions.MaxInterval is not set
+	DefaultMaxPollInterval = 30 * time.Second
+	// DefaultPollMultiplier is the exponential growth factor when WaitOptions.Multiplier is not set
+	DefaultPollMultiplier = 2.0
+)
+
+// WaitOptions configures how WaitForTaskWithOptions polls a task.
+//
+// The zero value polls every DefaultPollInterval until the context is done.
+type WaitOptions struct {
+	// Strategy is the backoff strategy between polls. Defaults to PollStrategyFixed.
+	Strategy PollStrategy
+	// Interval is the first interval (and the step for linear backoff)
+	Interval time.Duration
+	// MaxInterval caps the interval for linear and exponential backoff
+	MaxInterval time.Duration
+	// Multiplier is the growth factor for exponential backoff
+	Multiplier float64
+	// Deadline is the overall time budget for waiting. Zero means only the context applies.
+	Deadline time.Duration
+	// OnPoll is called after every successful poll with the 1-based poll number and the task
+	OnPoll func(attempt int, task RecipeTask)
+}
+
+// withDefaults returns a copy of the options with unset fields filled in
+func (o WaitOptions) withDefaults() WaitOptions {
+	if o.Strategy == "" {
+		o.Strategy = PollStrategyFixed
+	}
+	if o.Interval <= 0 {
+		o.Interval = DefaultPollInterval
+	}
+	if o.MaxInterval <= 0 {
+		o.MaxInterval = DefaultMaxPollInterval
+	}
+	if o.MaxInterval < o.Interval {
+		o.MaxInterval = o.Interval
+	}
+	if o.Multiplier <= 1 {
+		o.Multiplier = DefaultPollMultiplier
+	}
+	return o
+}
+
+// backoff converts the options into the shared retry backoff schedule
+func (o WaitOptions) backoff() retry.Backoff {
+	o = o.withDefaults()
+	return retry.Backoff{
+		Strategy:   retry.Strategy(o.Strategy),
+		Initial:    o.Interval,
+		Max:        o.MaxInterval,
+		Multiplier: o.Multiplier,
+	}
+}
+
+// NextInterval returns how long to wait after the given 1-based poll attempt
+func (o WaitOptions) NextInterval(attempt int) time.Duration {
+	return o.backoff().Delay(attempt)
+}
+
+// IsTerminalTaskStatus reports whether a task status will no longer change
+func IsTerminalTaskStatus(status shared.RecipeTaskStatus) bool {
+	switch status {
+	case shared.RecipeTaskStatusCompleted,
+		shared.RecipeTaskStatusFailed,
+		shared.RecipeTaskStatusCancelled,
+		shared.RecipeTaskStatusCanceled:
+		return true
+	default:
+		return false
+	}
+}
+
+// WaitForTask polls GetTaskStatus every pollInterval until the task reaches a
+// terminal status.
+//
+// Polls are driven by a ticker, so the interval is measured from the start of
+// one poll to the next rather than after each response, and cancellation of
+// the context is noticed immediately between polls.
+//
+// Parameters:
+//   - ctx: Request context for cancellation and timeout
+//   - taskId: The unique identifier of the task
+//   - pollInterval: Time between polls. Defaults to DefaultPollInterval when not positive.
+//
+// Returns the task in its terminal status, or an error wrapping the polling
+// failure or the context error.
+func (a *gaiaApi) WaitForTask(ctx context.Context, taskId string, pollInterval time.Duration) (RecipeTask, error) {
+	if pollInterval <= 0 {
+		pollInterval = DefaultPollInterval
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	return a.waitForTask(ctx, taskId, nil, func(ctx context.Context, _ int) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			return nil
+		}
+	})
+}
+
+// waitForTask is the polling loop shared by WaitForTask and WaitForTaskWithOptions.
+// It polls the task, reports it to onPoll (when not nil) and returns once the
+// status is terminal; otherwise it calls wait with the 1-based poll number and
+// polls again, stopping with the context error if wait fails.
+func (a *gaiaApi) waitForTask(
+	ctx context.Context,
+	taskId string,
+	onPoll func(attempt int, task RecipeTask),
+	wait func(ctx context.Context, attempt int) error,
+) (RecipeTask, error) {
+	for attempt := 1; ; attempt++ {
+		task, err := a.pollTask(ctx, taskId)
+		if err != nil {
+			return RecipeTask{}, err
+		}
+
+		if onPoll != nil {
+			onPoll(attempt, task)
+		}
+
+		if IsTerminalTaskStatus(task.Status) {
+			return task, nil
+		}
+
+		if err := wait(ctx, attempt); err != nil {
+			return RecipeTask{}, fmt.Errorf("stopped waiting for task %s: %w", taskId, err)
+		}
+	}
+}
+
+// pollTask fetches the task status once, preferring the context error over
+// the request error so callers can tell a timeout from an API failure
+func (a *gaiaApi) pollTask(ctx context.Context, taskId string) (RecipeTask, error) {
+	task, err := a.GetTaskStatus(ctx, taskId)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return RecipeTask{}, fmt.Errorf("stopped waiting for task %s: %w", taskId, ctxErr)
+		}
+		return RecipeTask{}, fmt.Errorf("failed to poll task %s: %w", taskId, err)
+	}
+	return task, nil
+}
+
+// WaitForTaskWithOptions polls GetTaskStatus until the task reaches a terminal status.
+//
+// The interval between polls follows opts.Strategy. Waiting stops as soon as the
+// context is cancelled or opts.Deadline elapses, whichever comes first.
+//
+// Parameters:
+//   - ctx: Request context for cancellation and timeout
+//   - taskId: The unique identifier of the task
+//   - opts: Polling configuration
+//
+// Returns the task in its terminal status, or an error wrapping the polling
+// failure or the context error.
+func (a *gaiaApi) WaitForTaskWithOptions(ctx context.Context, taskId string, opts WaitOptions) (RecipeTask, error) {
+	opts = opts.withDefaults()
+	backoff := opts.backoff()
+
+	if opts.Deadline > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Deadline)
+		defer cancel()
+	}
+
+	return a.waitForTask(ctx, taskId, opts.OnPoll, backoff.Wait)
+}
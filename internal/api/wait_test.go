@@ -0,0 +1,182 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"gaia-mcp-go/internal/testutil"
+	"gaia-mcp-go/pkg/shared"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func taskResponse(status shared.RecipeTaskStatus) testutil.MockResponse {
+	return testutil.MockResponse{
+		StatusCode: 200,
+		Body:       RecipeTask{Id: "task-123", Status: status},
+	}
+}
+
+func TestWaitOptions_NextInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     WaitOptions
+		expected []time.Duration
+	}{
+		{
+			name:     "Fixed",
+			opts:     WaitOptions{Strategy: PollStrategyFixed, Interval: time.Second},
+			expected: []time.Duration{time.Second, time.Second, time.Second},
+		},
+		{
+			name:     "Linear with cap",
+			opts:     WaitOptions{Strategy: PollStrategyLinear, Interval: time.Second, MaxInterval: 3 * time.Second},
+			expected: []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second},
+		},
+		{
+			name:     "Exponential with cap",
+			opts:     WaitOptions{Strategy: PollStrategyExponential, Interval: time.Second, MaxInterval: 10 * time.Second},
+			expected: []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second},
+		},
+		{
+			name:     "Exponential with custom multiplier",
+			opts:     WaitOptions{Strategy: PollStrategyExponential, Interval: time.Second, Multiplier: 3},
+			expected: []time.Duration{time.Second, 3 * time.Second, 9 * time.Second, 27 * time.Second, DefaultMaxPollInterval},
+		},
+		{
+			name:     "Zero value uses defaults",
+			opts:     WaitOptions{},
+			expected: []time.Duration{DefaultPollInterval, DefaultPollInterval},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i, expected := range tt.expected {
+				assert.Equal(t, expected, tt.opts.NextInterval(i+1), "attempt %d", i+1)
+			}
+		})
+	}
+}
+
+func TestGaiaApi_WaitForTaskWithOptions(t *testing.T) {
+	const taskPath = "/api/recipe/agi-tasks/task-123"
+
+	t.Run("Polls until completed and reports progress", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		server.AddResponseSequence("GET", taskPath,
+			taskResponse(shared.RecipeTaskStatusQueued),
+			taskResponse(shared.RecipeTaskStatusRunning),
+			taskResponse(shared.RecipeTaskStatusCompleted),
+		)
+
+		var polled []shared.RecipeTaskStatus
+		api := NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key"})
+		task, err := api.WaitForTaskWithOptions(context.Background(), "task-123", WaitOptions{
+			Strategy: PollStrategyExponential,
+			Interval: 5 * time.Millisecond,
+			OnPoll: func(attempt int, task RecipeTask) {
+				assert.Equal(t, len(polled)+1, attempt)
+				polled = append(polled, task.Status)
+			},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, shared.RecipeTaskStatusCompleted, task.Status)
+		assert.Equal(t, []shared.RecipeTaskStatus{
+			shared.RecipeTaskStatusQueued,
+			shared.RecipeTaskStatusRunning,
+			shared.RecipeTaskStatusCompleted,
+		}, polled)
+		assert.Len(t, server.Requests("GET", taskPath), 3)
+	})
+
+	t.Run("Failed is terminal", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		server.AddResponse("GET", taskPath, taskResponse(shared.RecipeTaskStatusFailed))
+
+		api := NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key"})
+		task, err := api.WaitForTaskWithOptions(context.Background(), "task-123", WaitOptions{Interval: 5 * time.Millisecond})
+
+		require.NoError(t, err)
+		assert.Equal(t, shared.RecipeTaskStatusFailed, task.Status)
+	})
+
+	t.Run("Deadline is honored", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		server.AddResponse("GET", taskPath, taskResponse(shared.RecipeTaskStatusRunning))
+
+		api := NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key"})
+		start := time.Now()
+		_, err := api.WaitForTaskWithOptions(context.Background(), "task-123", WaitOptions{
+			Strategy: PollStrategyFixed,
+			Interval: 20 * time.Millisecond,
+			Deadline: 100 * time.Millisecond,
+		})
+		elapsed := time.Since(start)
+
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+		assert.Less(t, elapsed, time.Second)
+		assert.GreaterOrEqual(t, len(server.Requests("GET", taskPath)), 2)
+	})
+}
+
+func TestGaiaApi_WaitForTask(t *testing.T) {
+	const taskPath = "/api/recipe/agi-tasks/task-123"
+
+	t.Run("Polls through running until completed", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		server.AddResponseSequence("GET", taskPath,
+			taskResponse(shared.RecipeTaskStatusRunning),
+			taskResponse(shared.RecipeTaskStatusRunning),
+			taskResponse(shared.RecipeTaskStatusRunning),
+			taskResponse(shared.RecipeTaskStatusCompleted),
+		)
+
+		api := NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key"})
+		task, err := api.WaitForTask(context.Background(), "task-123", 5*time.Millisecond)
+
+		require.NoError(t, err)
+		assert.Equal(t, shared.RecipeTaskStatusCompleted, task.Status)
+		assert.Len(t, server.Requests("GET", taskPath), 4)
+	})
+
+	t.Run("Failed is terminal", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		server.AddResponse("GET", taskPath, taskResponse(shared.RecipeTaskStatusFailed))
+
+		api := NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key"})
+		task, err := api.WaitForTask(context.Background(), "task-123", 5*time.Millisecond)
+
+		require.NoError(t, err)
+		assert.Equal(t, shared.RecipeTaskStatusFailed, task.Status)
+		assert.Len(t, server.Requests("GET", taskPath), 1)
+	})
+
+	t.Run("Cancelling mid-poll returns immediately", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		server.AddResponse("GET", taskPath, taskResponse(shared.RecipeTaskStatusRunning))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(50*time.Millisecond, cancel)
+
+		api := NewGaiaApi(GaiaApiConfig{BaseUrl: server.URL, ApiKey: "test-key"})
+		start := time.Now()
+		_, err := api.WaitForTask(ctx, "task-123", time.Hour)
+
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, context.Canceled))
+		assert.Contains(t, err.Error(), "stopped waiting for task task-123")
+		assert.Less(t, time.Since(start), 5*time.Second)
+		assert.Len(t, server.Requests("GET", taskPath), 1)
+	})
+}
@@ -1,26 +1,49 @@
 package testutil
 
 import (
+	"bytes"
 	"context"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/image/bmp"
 )
 
 // TestServer represents a test HTTP server for mocking external APIs
 type TestServer struct {
 	*httptest.Server
-	responses map[string]MockResponse
+	mu        sync.Mutex
+	responses map[string][]MockResponse
+	calls     map[string]int
+	requests  []RecordedRequest
+}
+
+// RecordedRequest captures an incoming request so tests can assert on it
+type RecordedRequest struct {
+	Method  string
+	Path    string
+	Query   url.Values
+	Headers http.Header
+	Body    []byte
 }
 
 // MockResponse represents a mock HTTP response
@@ -34,7 +57,8 @@ type MockResponse struct {
 // NewTestServer creates a new test server with predefined responses
 func NewTestServer() *TestServer {
 	ts := &TestServer{
-		responses: make(map[string]MockResponse),
+		responses: make(map[string][]MockResponse),
+		calls:     make(map[string]int),
 	}
 
 	// Create the actual HTTP server
@@ -44,8 +68,62 @@ func NewTestServer() *TestServer {
 
 // AddResponse adds a mock response for a specific endpoint
 func (ts *TestServer) AddResponse(method, path string, response MockResponse) {
+	ts.AddResponseSequence(method, path, response)
+}
+
+// AddResponseSequence adds mock responses that are returned in order for consecutive
+// calls to the same endpoint. The last response is repeated once the sequence is exhausted.
+func (ts *TestServer) AddResponseSequence(method, path string, responses ...MockResponse) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
 	key := fmt.Sprintf("%s:%s", method, path)
-	ts.responses[key] = response
+	ts.responses[key] = responses
+	ts.calls[key] = 0
+}
+
+// Requests returns the recorded requests for a specific endpoint in arrival order
+func (ts *TestServer) Requests(method, path string) []RecordedRequest {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	var requests []RecordedRequest
+	for _, req := range ts.requests {
+		if req.Method == method && req.Path == path {
+			requests = append(requests, req)
+		}
+	}
+	return requests
+}
+
+// nextResponse records the request and returns the mock response for its endpoint
+func (ts *TestServer) nextResponse(r *http.Request) (MockResponse, bool) {
+	body, _ := io.ReadAll(r.Body)
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	ts.requests = append(ts.requests, RecordedRequest{
+		Method:  r.Method,
+		Path:    r.URL.Path,
+		Query:   r.URL.Query(),
+		Headers: r.Header.Clone(),
+		Body:    body,
+	})
+
+	key := fmt.Sprintf("%s:%s", r.Method, r.URL.Path)
+	responses, exists := ts.responses[key]
+	if !exists || len(responses) == 0 {
+		return MockResponse{}, false
+	}
+
+	idx := ts.calls[key]
+	if idx >= len(responses) {
+		idx = len(responses) - 1
+	}
+	ts.calls[key]++
+
+	return responses[idx], true
 }
 
 // handler handles incoming requests and returns mock responses
@@ -53,7 +131,7 @@ func (ts *TestServer) handler(w http.ResponseWriter, r *http.Request) {
 	key := fmt.Sprintf("%s:%s", r.Method, r.URL.Path)
 
 	// Check if we have a mock response for this endpoint
-	response, exists := ts.responses[key]
+	response, exists := ts.nextResponse(r)
 	if !exists {
 		// Default response for unmocked endpoints
 		w.WriteHeader(http.StatusNotFound)
@@ -210,6 +288,80 @@ func CreateMockImage() []byte {
 	}
 }
 
+// CreateMockWebPImage creates a minimal valid lossless WebP image (1x1 pixel, transparent)
+func CreateMockWebPImage() []byte {
+	return []byte{
+		'R', 'I', 'F', 'F', 0x1A, 0x00, 0x00, 0x00, 'W', 'E', 'B', 'P',
+		'V', 'P', '8', 'L', 0x0D, 0x00, 0x00, 0x00, 0x2F, 0x00, 0x00, 0x00,
+		0x10, 0x07, 0x10, 0x11, 0x11, 0x88, 0x88, 0xFE, 0x07, 0x00,
+	}
+}
+
+// CreateMockOrientedJPEG creates a blue width x height JPEG tagged with the
+// given EXIF orientation, like a photo taken with a rotated phone
+func CreateMockOrientedJPEG(width, height, orientation int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{B: 255, A: 255})
+		}
+	}
+
+	var encoded bytes.Buffer
+	if err := jpeg.Encode(&encoded, img, &jpeg.Options{Quality: 100}); err != nil {
+		panic(fmt.Sprintf("encoding mock JPEG: %v", err))
+	}
+
+	// A big-endian TIFF header with a single IFD entry: orientation, SHORT, count 1
+	tiff := []byte{'M', 'M', 0x00, 0x2A, 0x00, 0x00, 0x00, 0x08, 0x00, 0x01}
+	tiff = binary.BigEndian.AppendUint16(tiff, 0x0112)
+	tiff = binary.BigEndian.AppendUint16(tiff, 3)
+	tiff = binary.BigEndian.AppendUint32(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, uint16(orientation))
+	tiff = append(tiff, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00)
+
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xFF, 0xE1}
+	segment = binary.BigEndian.AppendUint16(segment, uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	// Insert the APP1 segment right after the SOI marker
+	data := encoded.Bytes()
+	result := append([]byte{}, data[:2]...)
+	result = append(result, segment...)
+	return append(result, data[2:]...)
+}
+
+// CreateMockGIF creates an animated width x height GIF with a red first
+// frame and a blue second frame
+func CreateMockGIF(width, height int) []byte {
+	anim := &gif.GIF{}
+	for _, c := range []color.Color{color.RGBA{R: 255, A: 255}, color.RGBA{B: 255, A: 255}} {
+		frame := image.NewPaletted(image.Rect(0, 0, width, height), palette.Plan9)
+		draw.Draw(frame, frame.Bounds(), image.NewUniform(c), image.Point{}, draw.Src)
+		anim.Image = append(anim.Image, frame)
+		anim.Delay = append(anim.Delay, 10)
+	}
+
+	var encoded bytes.Buffer
+	if err := gif.EncodeAll(&encoded, anim); err != nil {
+		panic(fmt.Sprintf("encoding mock GIF: %v", err))
+	}
+	return encoded.Bytes()
+}
+
+// CreateMockBMP creates a green width x height BMP
+func CreateMockBMP(width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.RGBA{G: 255, A: 255}), image.Point{}, draw.Src)
+
+	var encoded bytes.Buffer
+	if err := bmp.Encode(&encoded, img); err != nil {
+		panic(fmt.Sprintf("encoding mock BMP: %v", err))
+	}
+	return encoded.Bytes()
+}
+
 // TestConfig represents common test configuration
 type TestConfig struct {
 	APIKey  string
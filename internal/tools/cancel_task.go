@@ -0,0 +1,70 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"gaia-mcp-go/internal/api"
+	"gaia-mcp-go/pkg/shared"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// CancelTaskTool stops a queued or running generation task
+type CancelTaskTool struct {
+	api  api.GaiaApi
+	tool mcp.Tool
+}
+
+func NewCancelTaskTool(api api.GaiaApi) *CancelTaskTool {
+	return &CancelTaskTool{
+		api: api,
+		tool: mcp.NewTool(
+			"cancel_task",
+			mcp.WithDescription("Cancel a queued or running GAIA generation task. Tasks that already finished cannot be cancelled."),
+			mcp.WithString(
+				"task_id",
+				mcp.Required(),
+				mcp.Description("The ID of the task to cancel, as reported by the tool that started it"),
+			),
+		),
+	}
+}
+
+func (t *CancelTaskTool) ToolName() string {
+	return "cancel_task"
+}
+
+func (t *CancelTaskTool) MCPTool() mcp.Tool {
+	return t.tool
+}
+
+func (t *CancelTaskTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	taskId, err := req.RequireString("task_id")
+	if err != nil || strings.TrimSpace(taskId) == "" {
+		return mcp.NewToolResultError("task_id parameter is required"), nil
+	}
+	taskId = strings.TrimSpace(taskId)
+
+	if err := t.api.CancelTask(ctx, taskId); err != nil {
+		// A finished task is the most common reason a cancel fails, so report its status
+		if task, statusErr := t.api.GetTaskStatus(ctx, taskId); statusErr == nil && api.IsTerminalTaskStatus(task.Status) {
+			return mcp.NewToolResultError(fmt.Sprintf(
+				"Task %s cannot be cancelled because it has already finished. Status: %s", taskId, task.Status,
+			)), nil
+		}
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to cancel task %s: %v", taskId, err)), nil
+	}
+
+	// The cancellation may take effect later, so report the status the task actually has
+	task, err := t.api.GetTaskStatus(ctx, taskId)
+	if err != nil {
+		return mcp.NewToolResultText(fmt.Sprintf(
+			"Cancellation of task %s was requested, but its status could not be read: %v", taskId, err,
+		)), nil
+	}
+	if task.Status == shared.RecipeTaskStatusCancelled {
+		return mcp.NewToolResultText(fmt.Sprintf("Task %s was cancelled. Status: %s", taskId, task.Status)), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Cancellation of task %s was requested. Status: %s", taskId, task.Status)), nil
+}
@@ -0,0 +1,118 @@
+package tools
+
+import (
+	"context"
+	"gaia-mcp-go/internal/api"
+	"gaia-mcp-go/internal/testutil"
+	"gaia-mcp-go/pkg/shared"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCancelTask(t *testing.T) {
+	t.Run("Cancels a running task", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		server.AddResponse("POST", "/api/recipe/agi-tasks/task-123/cancel", testutil.MockResponse{
+			StatusCode: http.StatusOK,
+			Body:       map[string]any{},
+		})
+		server.AddResponse("GET", "/api/recipe/agi-tasks/task-123", testutil.MockResponse{
+			StatusCode: http.StatusOK,
+			Body:       api.RecipeTask{Id: "task-123", Status: shared.RecipeTaskStatusCancelled},
+		})
+
+		tool := NewCancelTaskTool(newTestApi(server))
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"task_id": " task-123 ",
+		}))
+
+		require.NoError(t, err)
+		assert.False(t, result.IsError, resultText(result))
+		assert.Equal(t, "Task task-123 was cancelled. Status: CANCELLED", resultText(result))
+		assert.Len(t, server.Requests("POST", "/api/recipe/agi-tasks/task-123/cancel"), 1)
+	})
+
+	t.Run("Reports the status of a task still stopping", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		server.AddResponse("POST", "/api/recipe/agi-tasks/task-123/cancel", testutil.MockResponse{
+			StatusCode: http.StatusOK,
+			Body:       map[string]any{},
+		})
+		server.AddResponse("GET", "/api/recipe/agi-tasks/task-123", testutil.MockResponse{
+			StatusCode: http.StatusOK,
+			Body:       api.RecipeTask{Id: "task-123", Status: shared.RecipeTaskStatusRunning},
+		})
+
+		tool := NewCancelTaskTool(newTestApi(server))
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"task_id": "task-123",
+		}))
+
+		require.NoError(t, err)
+		assert.False(t, result.IsError, resultText(result))
+		assert.Equal(t, "Cancellation of task task-123 was requested. Status: RUNNING", resultText(result))
+	})
+
+	t.Run("Task that already completed", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		server.AddResponse("POST", "/api/recipe/agi-tasks/task-123/cancel", testutil.MockResponse{
+			StatusCode: http.StatusConflict,
+			Body:       map[string]string{"message": "Task is not cancellable"},
+		})
+		server.AddResponse("GET", "/api/recipe/agi-tasks/task-123", testutil.MockResponse{
+			StatusCode: http.StatusOK,
+			Body:       api.RecipeTask{Id: "task-123", Status: shared.RecipeTaskStatusCompleted},
+		})
+
+		tool := NewCancelTaskTool(newTestApi(server))
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"task_id": "task-123",
+		}))
+
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Equal(t, "Task task-123 cannot be cancelled because it has already finished. Status: COMPLETED", resultText(result))
+	})
+
+	t.Run("Cancel failure for an unfinished task", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		server.AddResponse("POST", "/api/recipe/agi-tasks/task-123/cancel", testutil.MockResponse{
+			StatusCode: http.StatusBadRequest,
+			Body:       map[string]string{"message": "Cancellation is unavailable"},
+		})
+		server.AddResponse("GET", "/api/recipe/agi-tasks/task-123", testutil.MockResponse{
+			StatusCode: http.StatusOK,
+			Body:       api.RecipeTask{Id: "task-123", Status: shared.RecipeTaskStatusRunning},
+		})
+
+		tool := NewCancelTaskTool(newTestApi(server))
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"task_id": "task-123",
+		}))
+
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, resultText(result), "Failed to cancel task task-123")
+		assert.Contains(t, resultText(result), "Cancellation is unavailable")
+	})
+
+	t.Run("Missing task_id", func(t *testing.T) {
+		tool := NewCancelTaskTool(nil)
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{}))
+
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Equal(t, "task_id parameter is required", resultText(result))
+	})
+}
@@ -0,0 +1,111 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"gaia-mcp-go/internal/api"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// CreateStyleTool creates a style from reference images that are already uploaded to GAIA
+type CreateStyleTool struct {
+	api  api.GaiaApi
+	tool mcp.Tool
+}
+
+func NewCreateStyleTool(api api.GaiaApi) *CreateStyleTool {
+	return &CreateStyleTool{
+		api: api,
+		tool: mcp.NewTool(
+			"create_style",
+			mcp.WithDescription("Create a new GAIA style from reference images already uploaded with upload_image. Returns the new style ID, to pass as styleId to generate_image. Use upload_and_create_style for images that are not uploaded yet."),
+			mcp.WithArray(
+				"image_urls",
+				mcp.Items(map[string]any{"type": "string"}),
+				mcp.Required(),
+				mcp.Description("The GAIA file urls of the reference images, as returned by upload_image"),
+			),
+			mcp.WithString(
+				"name",
+				mcp.Required(),
+				mcp.Description("The display name of the new style"),
+			),
+			mcp.WithString(
+				"description",
+				mcp.Description("Optional description of the new style"),
+			),
+			mcp.WithString(
+				"weight_preset",
+				mcp.Enum(weightPresetNames()...),
+				mcp.Description("How to weight the reference images: `equal` (0.5 each, the default), `emphasize-first` (1 for the first image, 0.5 for the others) or `decay` (from 1 for the first image down linearly). Ignored when weights is set."),
+			),
+			mcp.WithArray(
+				"weights",
+				mcp.Items(map[string]any{"type": "number", "minimum": 0, "maximum": 1}),
+				mcp.Description("Optional weight (0 to 1) of each reference image, in the order of image_urls. Overrides weight_preset."),
+			),
+		),
+	}
+}
+
+func (t *CreateStyleTool) ToolName() string {
+	return "create_style"
+}
+
+func (t *CreateStyleTool) MCPTool() mcp.Tool {
+	return t.tool
+}
+
+func (t *CreateStyleTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	imageUrls, err := stringArrayArg(req.GetArguments(), "image_urls")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if len(imageUrls) == 0 {
+		return mcp.NewToolResultError("image_urls must contain at least one URL"), nil
+	}
+
+	name, err := req.RequireString("name")
+	if err != nil || strings.TrimSpace(name) == "" {
+		return mcp.NewToolResultError("name parameter is required"), nil
+	}
+
+	var description *string
+	if value := req.GetString("description", ""); value != "" {
+		description = &value
+	}
+
+	weights, err := floatArrayArg(req.GetArguments(), "weights")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if weights != nil && len(weights) != len(imageUrls) {
+		return mcp.NewToolResultError(fmt.Sprintf("weights must have one weight per image: got %d weights for %d images", len(weights), len(imageUrls))), nil
+	}
+
+	style, err := t.api.CreateStyle(ctx, imageUrls, name, description, api.CreateStyleOptions{
+		Weights: weights,
+		Preset:  api.StyleWeightPreset(req.GetString("weight_preset", "")),
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to create style: %v", err)), nil
+	}
+
+	resultMsg := fmt.Sprintf("Style created successfully. Style ID: %s", style.Id)
+	if style.ThumbnailUrl != "" {
+		resultMsg += fmt.Sprintf("\nThumbnail: %s", style.ThumbnailUrl)
+	}
+
+	return mcp.NewToolResultText(resultMsg), nil
+}
+
+// weightPresetNames lists the values of the weight_preset parameter
+func weightPresetNames() []string {
+	names := make([]string, len(api.StyleWeightPresets))
+	for i, preset := range api.StyleWeightPresets {
+		names[i] = string(preset)
+	}
+	return names
+}
@@ -0,0 +1,158 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"gaia-mcp-go/internal/api"
+	"gaia-mcp-go/internal/testutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateStyle(t *testing.T) {
+	imageUrls := []interface{}{
+		"https://cdn.protogaia.com/styles/first.png",
+		"https://cdn.protogaia.com/styles/second.png",
+	}
+
+	t.Run("Creates the style from the image urls", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		server.AddResponse("POST", createStylePath, testutil.MockResponse{
+			StatusCode: http.StatusOK,
+			Body: api.SdStyle{
+				Id:           "style-123",
+				Name:         "Watercolor",
+				ThumbnailUrl: "https://cdn.protogaia.com/styles/thumbnail.png",
+			},
+		})
+
+		tool := NewCreateStyleTool(newTestApi(server))
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"image_urls":  imageUrls,
+			"name":        "Watercolor",
+			"description": "Soft washes of color",
+		}))
+
+		require.NoError(t, err)
+		assert.False(t, result.IsError, resultText(result))
+		assert.Contains(t, resultText(result), "Style ID: style-123")
+		assert.Contains(t, resultText(result), "Thumbnail: https://cdn.protogaia.com/styles/thumbnail.png")
+
+		requests := server.Requests("POST", createStylePath)
+		require.Len(t, requests, 1)
+		assert.Equal(t, []string{"https://cdn.protogaia.com/styles/first.png", "https://cdn.protogaia.com/styles/second.png"}, decodeStyleImageUrls(t, requests[0]))
+
+		var payload map[string]any
+		require.NoError(t, json.Unmarshal(requests[0].Body, &payload))
+		assert.Equal(t, "Watercolor", payload["name"])
+		assert.Equal(t, "Soft washes of color", payload["description"])
+	})
+
+	t.Run("Weights", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			args     map[string]any
+			expected []float64
+		}{
+			{"Default", map[string]any{}, []float64{0.5, 0.5}},
+			{"Preset", map[string]any{"weight_preset": "emphasize-first"}, []float64{1, 0.5}},
+			{"Raw weights override the preset", map[string]any{"weight_preset": "decay", "weights": []interface{}{0.3, 0.9}}, []float64{0.3, 0.9}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				server := testutil.NewTestServer()
+				defer server.Close()
+				server.AddResponse("POST", createStylePath, testutil.MockResponse{
+					StatusCode: http.StatusOK,
+					Body:       api.SdStyle{Id: "style-123"},
+				})
+
+				args := map[string]any{"image_urls": imageUrls, "name": "Watercolor"}
+				for key, value := range tt.args {
+					args[key] = value
+				}
+
+				tool := NewCreateStyleTool(newTestApi(server))
+				result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), args))
+
+				require.NoError(t, err)
+				assert.False(t, result.IsError, resultText(result))
+
+				requests := server.Requests("POST", createStylePath)
+				require.Len(t, requests, 1)
+				assert.Equal(t, tt.expected, decodeStyleImageWeights(t, requests[0]))
+			})
+		}
+	})
+
+	t.Run("API error", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		server.AddResponse("POST", createStylePath, testutil.MockResponse{
+			StatusCode: http.StatusBadRequest,
+			Body:       map[string]string{"message": "invalid images"},
+		})
+
+		tool := NewCreateStyleTool(newTestApi(server))
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"image_urls": imageUrls,
+			"name":       "Watercolor",
+		}))
+
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, resultText(result), "Failed to create style")
+	})
+
+	t.Run("Invalid arguments", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			args     map[string]any
+			expected string
+		}{
+			{"Missing image_urls", map[string]any{"name": "Watercolor"}, "image_urls parameter is required"},
+			{"Empty image_urls", map[string]any{"image_urls": []interface{}{}, "name": "Watercolor"}, "image_urls must contain at least one URL"},
+			{"Missing name", map[string]any{"image_urls": imageUrls}, "name parameter is required"},
+			{"Weights do not match the images", map[string]any{"image_urls": imageUrls, "name": "Watercolor", "weights": []interface{}{0.3}}, "weights must have one weight per image: got 1 weights for 2 images"},
+			{"Weight is not a number", map[string]any{"image_urls": imageUrls, "name": "Watercolor", "weights": []interface{}{0.3, "high"}}, "weights[1] must be a number"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				server := testutil.NewTestServer()
+				defer server.Close()
+
+				tool := NewCreateStyleTool(newTestApi(server))
+				result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), tt.args))
+
+				require.NoError(t, err)
+				assert.True(t, result.IsError)
+				assert.Equal(t, tt.expected, resultText(result))
+				assert.Empty(t, server.Requests("POST", createStylePath))
+			})
+		}
+	})
+}
+
+// decodeStyleImageWeights decodes the image weights from a recorded create style request
+func decodeStyleImageWeights(t *testing.T, req testutil.RecordedRequest) []float64 {
+	t.Helper()
+
+	var payload struct {
+		Images []api.SdStyleImage `json:"images"`
+	}
+	require.NoError(t, json.Unmarshal(req.Body, &payload))
+
+	weights := make([]float64, len(payload.Images))
+	for i, image := range payload.Images {
+		weights[i] = image.Weight
+	}
+	return weights
+}
@@ -0,0 +1,201 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"gaia-mcp-go/pkg/imageutil"
+	"gaia-mcp-go/pkg/shared"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ServerMode identifies the transport the tools are served over.
+// It selects how much result images are downscaled before being returned.
+type ServerMode string
+
+const (
+	// ServerModeStdio keeps images small to stay under MCP client size limits
+	ServerModeStdio ServerMode = "stdio"
+	// ServerModeHTTP returns full-resolution images for transports that can carry them
+	ServerModeHTTP ServerMode = "http"
+)
+
+// ToolDefaults holds the settings of the tools: the default parameter values
+// advertised in tool schemas and used when a client omits the corresponding
+// argument, and how result images are processed and files saved.
+// Zero-valued fields fall back to the built-in defaults.
+//
+// The runtime dependencies the tools share are passed to NewGaiaTools as ToolDeps.
+type ToolDefaults struct {
+	// AspectRatio is the default aspect ratio for generate_image
+	AspectRatio shared.AspectRatio
+	// PromptStyle is the default prompt style for generate_image
+	PromptStyle shared.PromptStyle
+	// VariationControl is the default variation control for remix
+	VariationControl string
+	// UpscaleRatio is the default upscale ratio for upscaler
+	UpscaleRatio float64
+	// ServerMode selects the image processing used for returned images
+	ServerMode ServerMode
+	// MaxImagePayloadBytes caps the base64 size of an image returned inline.
+	// Larger images are returned as their URL with a warning instead.
+	// Zero uses the server mode's default limit.
+	MaxImagePayloadBytes int
+	// DownloadDir is the directory download_image saves images in. It refuses
+	// to write anywhere else.
+	DownloadDir string
+	// CdnUrl is the base URL of the GAIA CDN that image url arguments must be
+	// hosted on. It should match the api.GaiaApiConfig.CdnUrl of the API client.
+	// Defaults to shared.CDN_URL.
+	CdnUrl string
+	// ImageCacheSize is the number of processed images kept in memory, so an
+	// image returned again, e.g. by upscaler and then face_enhancer, is not
+	// downloaded and encoded twice. Zero disables the cache.
+	ImageCacheSize int
+	// ImageCacheTTL is how long a processed image stays cached. Zero uses
+	// imageutil.DefaultCacheTTL.
+	ImageCacheTTL time.Duration
+
+	// deps are the dependencies NewGaiaTools shares with every tool
+	deps ToolDeps
+}
+
+const (
+	// DefaultStdioImagePayloadBytes is the inline image limit in stdio mode,
+	// which MCP desktop clients reject above roughly 1MB per result
+	DefaultStdioImagePayloadBytes = 1024 * 1024
+	// DefaultHTTPImagePayloadBytes is the inline image limit in HTTP mode
+	DefaultHTTPImagePayloadBytes = 10 * 1024 * 1024
+)
+
+// DefaultToolDefaults returns the built-in default parameter values
+func DefaultToolDefaults() ToolDefaults {
+	return ToolDefaults{
+		AspectRatio:      shared.AspectRatio1_1,
+		PromptStyle:      shared.PromptStyleBase,
+		VariationControl: "subtle",
+		UpscaleRatio:     2,
+		ServerMode:       ServerModeStdio,
+		DownloadDir:      DefaultDownloadDir(),
+		CdnUrl:           shared.CDN_URL,
+	}
+}
+
+// resolveToolDefaults merges the optional constructor defaults over the built-in ones
+func resolveToolDefaults(overrides ...ToolDefaults) ToolDefaults {
+	defaults := DefaultToolDefaults()
+
+	for _, override := range overrides {
+		if override.AspectRatio != "" {
+			defaults.AspectRatio = override.AspectRatio
+		}
+		if override.PromptStyle != "" {
+			defaults.PromptStyle = override.PromptStyle
+		}
+		if override.VariationControl != "" {
+			defaults.VariationControl = override.VariationControl
+		}
+		if override.UpscaleRatio != 0 {
+			defaults.UpscaleRatio = override.UpscaleRatio
+		}
+		if override.ServerMode != "" {
+			defaults.ServerMode = override.ServerMode
+		}
+		if override.MaxImagePayloadBytes != 0 {
+			defaults.MaxImagePayloadBytes = override.MaxImagePayloadBytes
+		}
+		if override.DownloadDir != "" {
+			defaults.DownloadDir = override.DownloadDir
+		}
+		if cdnUrl := strings.TrimSuffix(override.CdnUrl, "/"); cdnUrl != "" {
+			defaults.CdnUrl = cdnUrl
+		}
+		if override.ImageCacheSize != 0 {
+			defaults.ImageCacheSize = override.ImageCacheSize
+		}
+		if override.ImageCacheTTL != 0 {
+			defaults.ImageCacheTTL = override.ImageCacheTTL
+		}
+		defaults.deps = defaults.deps.merge(override.deps)
+	}
+
+	return defaults
+}
+
+// DefaultDownloadDir returns the directory download_image saves images in
+// when ToolDefaults.DownloadDir is not set: gaia-mcp/images in the user's
+// cache directory, which other users cannot write to, unlike the shared temp
+// directory. The temp directory is only used when there is no cache directory.
+func DefaultDownloadDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "gaia-mcp", "images")
+}
+
+// ImageProcessorConfig returns the processing used for images returned by the tools.
+// HTTP mode keeps full resolution; stdio uses the conservative MCP settings.
+func (d ToolDefaults) ImageProcessorConfig() imageutil.ProcessorConfig {
+	config := imageutil.MCPConfig()
+	if d.ServerMode == ServerModeHTTP {
+		config = imageutil.FullResolutionConfig()
+	}
+	config.CacheSize = d.ImageCacheSize
+	config.CacheTTL = d.ImageCacheTTL
+	return config
+}
+
+// imageProcessor returns the shared Processor, or a new one when the tool was
+// created without NewGaiaTools
+func (d ToolDefaults) imageProcessor() *imageutil.Processor {
+	if d.deps.Processor != nil {
+		return d.deps.Processor
+	}
+	return imageutil.NewProcessor(d.ImageProcessorConfig())
+}
+
+// processImage downloads an image and encodes it for an MCP result according to the server mode.
+// The fallback urls are tried in order when imageUrl cannot be downloaded.
+func (d ToolDefaults) processImage(ctx context.Context, imageUrl string, fallbacks ...string) (base64Data string, mimeType string, err error) {
+	return d.imageProcessor().ProcessImageFromURLsForMCP(ctx, append([]string{imageUrl}, fallbacks...))
+}
+
+// ImagePayloadLimit returns the maximum base64 size of an image returned inline
+func (d ToolDefaults) ImagePayloadLimit() int {
+	if d.MaxImagePayloadBytes > 0 {
+		return d.MaxImagePayloadBytes
+	}
+	if d.ServerMode == ServerModeHTTP {
+		return DefaultHTTPImagePayloadBytes
+	}
+	return DefaultStdioImagePayloadBytes
+}
+
+// imageContent returns the image as MCP content, or a text warning with its URL
+// when the encoded image exceeds the payload limit and would not make it through
+// the transport
+func (d ToolDefaults) imageContent(imageUrl, base64Data, mimeType string) mcp.Content {
+	if limit := d.ImagePayloadLimit(); len(base64Data) > limit {
+		return mcp.NewTextContent(fmt.Sprintf(
+			"Warning: the image is too large to return inline (%d bytes encoded, limit %d). Open it from its url instead: %s",
+			len(base64Data), limit, imageUrl,
+		))
+	}
+	return mcp.NewImageContent(base64Data, mimeType)
+}
+
+// imageResult builds a tool result with a message and the image, falling back
+// to the image URL when the image exceeds the payload limit
+func (d ToolDefaults) imageResult(msg, imageUrl, base64Data, mimeType string) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.NewTextContent(msg),
+			d.imageContent(imageUrl, base64Data, mimeType),
+		},
+	}
+}
@@ -0,0 +1,174 @@
+package tools
+
+import (
+	"context"
+	"gaia-mcp-go/internal/testutil"
+	"gaia-mcp-go/pkg/imageutil"
+	"gaia-mcp-go/pkg/shared"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// schemaDefault returns the default value declared for a tool property
+func schemaDefault(t *testing.T, tool mcp.Tool, property string) any {
+	t.Helper()
+
+	prop, ok := tool.InputSchema.Properties[property].(map[string]any)
+	require.True(t, ok, "property %q not found", property)
+	return prop["default"]
+}
+
+func TestToolDefaults_Schema(t *testing.T) {
+	t.Run("Built-in defaults", func(t *testing.T) {
+		generate := NewGenerateImageTool(nil).MCPTool()
+		assert.Equal(t, string(shared.PromptStyleBase), schemaDefault(t, generate, "promptStyle"))
+		assert.Equal(t, string(shared.AspectRatio1_1), schemaDefault(t, generate, "aspectRatio"))
+		assert.Equal(t, "subtle", schemaDefault(t, NewRemixTool(nil).MCPTool(), "variationControl"))
+		assert.Equal(t, 2.0, schemaDefault(t, NewUpscalerTool(nil).MCPTool(), "ratio"))
+	})
+
+	t.Run("Configured defaults", func(t *testing.T) {
+		defaults := ToolDefaults{
+			AspectRatio:      shared.AspectRatio16_9,
+			PromptStyle:      shared.PromptStylePhotographic,
+			VariationControl: "strong",
+			UpscaleRatio:     4,
+		}
+
+		generate := NewGenerateImageTool(nil, defaults).MCPTool()
+		assert.Equal(t, string(shared.PromptStylePhotographic), schemaDefault(t, generate, "promptStyle"))
+		assert.Equal(t, string(shared.AspectRatio16_9), schemaDefault(t, generate, "aspectRatio"))
+		assert.Equal(t, "strong", schemaDefault(t, NewRemixTool(nil, defaults).MCPTool(), "variationControl"))
+		assert.Equal(t, 4.0, schemaDefault(t, NewUpscalerTool(nil, defaults).MCPTool(), "ratio"))
+	})
+
+	t.Run("Partial overrides keep other defaults", func(t *testing.T) {
+		generate := NewGenerateImageTool(nil, ToolDefaults{PromptStyle: shared.PromptStyleAnime}).MCPTool()
+		assert.Equal(t, string(shared.PromptStyleAnime), schemaDefault(t, generate, "promptStyle"))
+		assert.Equal(t, string(shared.AspectRatio1_1), schemaDefault(t, generate, "aspectRatio"))
+	})
+}
+
+func TestToolDefaults_AppliedWhenArgumentOmitted(t *testing.T) {
+	server := testutil.NewTestServer()
+	defer server.Close()
+
+	server.AddResponse("POST", createTaskPath, generatedResponse(addMockImage(server, "/result.png")))
+
+	tool := NewGenerateImageTool(newTestApi(server), ToolDefaults{PromptStyle: shared.PromptStylePhotographic})
+	result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+		"prompt": "A lighthouse at dusk",
+	}))
+
+	require.NoError(t, err)
+	assert.False(t, result.IsError, resultText(result))
+
+	requests := server.Requests("POST", createTaskPath)
+	require.Len(t, requests, 1)
+	assert.Equal(t, string(shared.PromptStylePhotographic), decodeTaskParams(t, requests[0])["promptStyle"])
+}
+
+func TestToolDefaults_ServerMode(t *testing.T) {
+	stdioConfig := ToolDefaults{ServerMode: ServerModeStdio}.ImageProcessorConfig()
+	httpConfig := ToolDefaults{ServerMode: ServerModeHTTP}.ImageProcessorConfig()
+
+	assert.Equal(t, imageutil.MCPConfig(), stdioConfig)
+	assert.Equal(t, imageutil.FullResolutionConfig(), httpConfig)
+	assert.Greater(t, httpConfig.MaxWidth, stdioConfig.MaxWidth)
+	assert.Greater(t, httpConfig.JPEGQuality, stdioConfig.JPEGQuality)
+
+	t.Run("Stdio is the default", func(t *testing.T) {
+		assert.Equal(t, ServerModeStdio, DefaultToolDefaults().ServerMode)
+		assert.Equal(t, stdioConfig, NewGenerateImageTool(nil).defaults.ImageProcessorConfig())
+	})
+
+	t.Run("Mode is threaded through tool construction", func(t *testing.T) {
+		defaults := ToolDefaults{ServerMode: ServerModeHTTP}
+
+		assert.Equal(t, httpConfig, NewGenerateImageTool(nil, defaults).defaults.ImageProcessorConfig())
+		assert.Equal(t, httpConfig, NewRemixTool(nil, defaults).defaults.ImageProcessorConfig())
+		assert.Equal(t, httpConfig, NewUpscalerTool(nil, defaults).defaults.ImageProcessorConfig())
+		assert.Equal(t, httpConfig, NewFaceEnhancerTool(nil, defaults).defaults.ImageProcessorConfig())
+		assert.Equal(t, httpConfig, NewPipelineTool(nil, defaults).defaults.ImageProcessorConfig())
+		assert.Equal(t, httpConfig, NewReplayRecipeTool(nil, defaults).generator.defaults.ImageProcessorConfig())
+	})
+}
+
+func TestToolDefaults_ImagePayloadLimit(t *testing.T) {
+	assert.Equal(t, DefaultStdioImagePayloadBytes, ToolDefaults{}.ImagePayloadLimit())
+	assert.Equal(t, DefaultStdioImagePayloadBytes, DefaultToolDefaults().ImagePayloadLimit())
+	assert.Equal(t, DefaultHTTPImagePayloadBytes, ToolDefaults{ServerMode: ServerModeHTTP}.ImagePayloadLimit())
+	assert.Equal(t, 2048, resolveToolDefaults(ToolDefaults{MaxImagePayloadBytes: 2048}).ImagePayloadLimit())
+
+	t.Run("Oversized images fall back to their url", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		imageUrl := addMockImage(server, "/result.png")
+		server.AddResponse("POST", createTaskPath, generatedResponse(imageUrl))
+
+		tool := NewUpscalerTool(newTestApi(server), ToolDefaults{MaxImagePayloadBytes: 10})
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"image_url": "https://cdn.protogaia.com/input.png",
+		}))
+
+		require.NoError(t, err)
+		assert.False(t, result.IsError)
+		assert.Equal(t, 0, countImages(result))
+
+		text := resultText(result)
+		assert.Contains(t, text, "Upscaled successfully")
+		assert.Contains(t, text, "Warning: the image is too large to return inline")
+		assert.Contains(t, text, "limit 10). Open it from its url instead: "+imageUrl)
+	})
+
+	t.Run("Images within the limit are returned inline", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		server.AddResponse("POST", createTaskPath, generatedResponse(addMockImage(server, "/result.png")))
+
+		tool := NewUpscalerTool(newTestApi(server))
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"image_url": "https://cdn.protogaia.com/input.png",
+		}))
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, countImages(result))
+		assert.NotContains(t, resultText(result), "Warning")
+	})
+}
+
+func TestNewGaiaTools_SharedImageCache(t *testing.T) {
+	server := testutil.NewTestServer()
+	defer server.Close()
+
+	imageUrl := addMockImage(server, "/result.png")
+	server.AddResponse("POST", createTaskPath, generatedResponse(imageUrl))
+
+	gaiaTools := NewGaiaTools(newTestApi(server), ToolDefaults{ImageCacheSize: 8}, ToolDeps{})
+	calls := []struct {
+		tool string
+		args map[string]any
+	}{
+		{tool: "generate_image_turbo", args: map[string]any{"prompt": "A red fox"}},
+		{tool: "upscaler", args: map[string]any{"image_url": "https://cdn.protogaia.com/input.png"}},
+	}
+	for _, call := range calls {
+		for _, tool := range gaiaTools {
+			if tool.ToolName() != call.tool {
+				continue
+			}
+			result, err := tool.Handler(context.Background(), newCallToolRequest(call.tool, call.args))
+			require.NoError(t, err)
+			assert.False(t, result.IsError, resultText(result))
+			assert.Equal(t, 1, countImages(result))
+		}
+	}
+
+	// The second tool returns the image cached by the first one
+	assert.Len(t, server.Requests("GET", "/result.png"), 1)
+}
@@ -0,0 +1,105 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gaia-mcp-go/internal/api"
+	"gaia-mcp-go/pkg/shared"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// DescribeTool describes an image in words with the describe type of Protogaia's simple recipe
+type DescribeTool struct {
+	api      api.GaiaApi
+	tool     mcp.Tool
+	defaults ToolDefaults
+}
+
+func NewDescribeTool(api api.GaiaApi, overrides ...ToolDefaults) *DescribeTool {
+	defaults := resolveToolDefaults(overrides...)
+
+	return &DescribeTool{
+		api:      api,
+		defaults: defaults,
+		tool: mcp.NewTool(
+			"describe_image",
+			mcp.WithDescription("Describe an image in words with Protogaia. The description reads like a prompt, so it can be edited and passed to generate_image."),
+			mcp.WithString(
+				"image_url",
+				mcp.Required(),
+				mcp.Description(fmt.Sprintf("The image URL to describe. It must be GAIA's image url: starts with `%s/`", defaults.CdnUrl)),
+			),
+		),
+	}
+}
+
+func (t *DescribeTool) ToolName() string {
+	return "describe_image"
+}
+
+func (t *DescribeTool) MCPTool() mcp.Tool {
+	return t.tool
+}
+
+func (t *DescribeTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	imageUrl, err := cdnImageUrlArg(req.GetArguments(), "image_url", t.defaults.CdnUrl)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// The result is text, so there is no image to download and encode
+	description, err := describeImage(ctx, t.api, imageUrl)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to describe image: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(description), nil
+}
+
+// errNoDescription is returned when the describe task finished without a description
+var errNoDescription = errors.New("no description was generated")
+
+// describeImage runs the simple recipe's describe type on an image and returns
+// the generated text description, suitable for use as a prompt. The description
+// is the prompt the task derived from the image, read back once it has finished.
+func describeImage(ctx context.Context, gaiaApi api.GaiaApi, imageUrl string) (string, error) {
+	res, err := gaiaApi.GenerateImages(ctx, api.GenerateImagesRequest{
+		RecipeId:   shared.RecipeIdImageGeneratorSimple,
+		RecipeType: shared.RecipeTypeDescribe,
+		Params: map[string]interface{}{
+			"image": imageUrl,
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if res.Error != nil {
+		return "", errors.New(*res.Error)
+	}
+
+	if !res.Success || res.TaskId == "" {
+		return "", errors.New("failed to describe the image")
+	}
+
+	task, err := gaiaApi.WaitForTask(ctx, res.TaskId, 0)
+	if err != nil {
+		return "", err
+	}
+
+	if task.Status != shared.RecipeTaskStatusCompleted {
+		if task.Error != nil && *task.Error != "" {
+			return "", errors.New(*task.Error)
+		}
+		return "", fmt.Errorf("the describe task finished with status %s", task.Status)
+	}
+
+	if description := strings.TrimSpace(task.Prompt); description != "" {
+		return description, nil
+	}
+
+	return "", errNoDescription
+}
@@ -0,0 +1,143 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"gaia-mcp-go/internal/api"
+	"gaia-mcp-go/internal/testutil"
+	"gaia-mcp-go/pkg/shared"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescribe(t *testing.T) {
+	const imageUrl = "https://cdn.protogaia.com/lighthouse.png"
+
+	t.Run("Returns the description", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		description := "A lighthouse on a rocky cliff at sunset, waves crashing below, warm golden light"
+		server.AddResponse("POST", createTaskPath, describedResponse(server, description))
+
+		tool := NewDescribeTool(newTestApi(server))
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"image_url": imageUrl,
+		}))
+
+		require.NoError(t, err)
+		assert.False(t, result.IsError, resultText(result))
+		require.Len(t, result.Content, 1)
+		assert.Equal(t, description, resultText(result))
+		assert.Equal(t, 0, countImages(result))
+
+		requests := server.Requests("POST", createTaskPath)
+		require.Len(t, requests, 1)
+
+		var task struct {
+			RecipeId   shared.RecipeId        `json:"recipeId"`
+			RecipeType shared.RecipeType      `json:"recipeType"`
+			Params     map[string]interface{} `json:"params"`
+		}
+		require.NoError(t, json.Unmarshal(requests[0].Body, &task))
+		assert.Equal(t, shared.RecipeIdImageGeneratorSimple, task.RecipeId)
+		assert.Equal(t, shared.RecipeTypeDescribe, task.RecipeType)
+		assert.Equal(t, imageUrl, task.Params["image"])
+	})
+
+	t.Run("No description", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		server.AddResponse("POST", createTaskPath, describedResponse(server, ""))
+
+		tool := NewDescribeTool(newTestApi(server))
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"image_url": imageUrl,
+		}))
+
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Equal(t, "Failed to describe image: no description was generated", resultText(result))
+	})
+
+	t.Run("Failed describe task", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		message := "Image could not be read"
+		server.AddResponse("POST", createTaskPath, testutil.MockResponse{
+			StatusCode: http.StatusOK,
+			Body:       api.ImageGeneratedResponse{Success: true, TaskId: "task-123"},
+		})
+		server.AddResponse("GET", "/api/recipe/agi-tasks/task-123", testutil.MockResponse{
+			StatusCode: http.StatusOK,
+			Body:       api.RecipeTask{Id: "task-123", Status: shared.RecipeTaskStatusFailed, Error: &message},
+		})
+
+		tool := NewDescribeTool(newTestApi(server))
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"image_url": imageUrl,
+		}))
+
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Equal(t, "Failed to describe image: Image could not be read", resultText(result))
+	})
+
+	t.Run("API error", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		server.AddResponse("POST", createTaskPath, testutil.MockResponse{
+			StatusCode: http.StatusOK,
+			Body: map[string]interface{}{
+				"success": false,
+				"error":   "Describe is unavailable",
+			},
+		})
+
+		tool := NewDescribeTool(newTestApi(server))
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"image_url": imageUrl,
+		}))
+
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Equal(t, "Failed to describe image: Describe is unavailable", resultText(result))
+	})
+
+	t.Run("Accepts a url from the configured CDN", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		server.AddResponse("POST", createTaskPath, describedResponse(server, "A lighthouse"))
+
+		tool := NewDescribeTool(newTestApi(server), ToolDefaults{CdnUrl: "https://cdn.example.com/"})
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"image_url": "https://cdn.example.com/lighthouse.png",
+		}))
+
+		require.NoError(t, err)
+		assert.False(t, result.IsError, resultText(result))
+		assert.Contains(t, tool.MCPTool().InputSchema.Properties["image_url"].(map[string]any)["description"], "https://cdn.example.com/")
+	})
+
+	t.Run("Rejects a non-GAIA url", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		tool := NewDescribeTool(newTestApi(server))
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"image_url": "https://example.com/lighthouse.png",
+		}))
+
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, resultText(result), "image_url must be GAIA's image url")
+		assert.Empty(t, server.Requests("POST", createTaskPath))
+	})
+}
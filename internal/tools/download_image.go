@@ -0,0 +1,152 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// DownloadImageTool saves a GAIA image to the local disk, inside the configured download directory
+type DownloadImageTool struct {
+	tool     mcp.Tool
+	defaults ToolDefaults
+}
+
+func NewDownloadImageTool(overrides ...ToolDefaults) *DownloadImageTool {
+	defaults := resolveToolDefaults(overrides...)
+
+	return &DownloadImageTool{
+		defaults: defaults,
+		tool: mcp.NewTool(
+			"download_image",
+			mcp.WithDescription(fmt.Sprintf("Save a GAIA image as a file on this computer, in %s. Returns the path of the saved file.", defaults.DownloadDir)),
+			mcp.WithString(
+				"image_url",
+				mcp.Required(),
+				mcp.Description(fmt.Sprintf("The image URL to save. It must be GAIA's image url: starts with `%s/`", defaults.CdnUrl)),
+			),
+			mcp.WithString(
+				"output_path",
+				mcp.Description("Optional file path, relative to the download directory, e.g. `foxes/red-fox.png`. Defaults to the image's file name. Paths outside the download directory are rejected."),
+			),
+		),
+	}
+}
+
+func (t *DownloadImageTool) ToolName() string {
+	return "download_image"
+}
+
+func (t *DownloadImageTool) MCPTool() mcp.Tool {
+	return t.tool
+}
+
+func (t *DownloadImageTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	imageUrl, err := cdnImageUrlArg(req.GetArguments(), "image_url", t.defaults.CdnUrl)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	outputPath := req.GetString("output_path", "")
+	if strings.TrimSpace(outputPath) == "" {
+		outputPath = imageFileName(imageUrl)
+	}
+
+	target, err := resolveDownloadPath(t.defaults.DownloadDir, outputPath)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	data, _, err := t.defaults.imageProcessor().DownloadImageBytes(ctx, imageUrl)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to download image: %v", err)), nil
+	}
+
+	if err := writeNewFile(t.defaults.DownloadDir, target, data); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to save image: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Image saved to %s (%d bytes)", target, len(data))), nil
+}
+
+// imageFileName returns the file name of the image url's path
+func imageFileName(imageUrl string) string {
+	parsed, err := url.Parse(imageUrl)
+	if err != nil {
+		return "image"
+	}
+	name := path.Base(parsed.Path)
+	if name == "." || name == "/" {
+		return "image"
+	}
+	return name
+}
+
+// resolveDownloadPath returns the absolute path of outputPath inside dir.
+// Relative paths are joined to dir; absolute ones must already be inside it.
+// It returns an error for any path that escapes dir once cleaned.
+func resolveDownloadPath(dir, outputPath string) (string, error) {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("invalid download directory: %w", err)
+	}
+
+	target := outputPath
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(root, target)
+	}
+	target = filepath.Clean(target)
+
+	rel, err := filepath.Rel(root, target)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("output_path must be a file inside the download directory %s", root)
+	}
+
+	return target, nil
+}
+
+// writeNewFile writes data to a new file at path inside dir, creating its
+// directories with private permissions. Once they exist, the real path of the
+// file's directory is checked again, so a symlink inside dir cannot redirect
+// the write outside of it. It refuses to overwrite an existing file.
+func writeNewFile(dir, path string, data []byte) error {
+	parent := filepath.Dir(path)
+	if err := os.MkdirAll(parent, 0o700); err != nil {
+		return err
+	}
+
+	realRoot, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return err
+	}
+	realParent, err := filepath.EvalSymlinks(parent)
+	if err != nil {
+		return err
+	}
+	if rel, err := filepath.Rel(realRoot, realParent); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("%s resolves to %s, outside the download directory %s", parent, realParent, realRoot)
+	}
+
+	// O_EXCL also refuses a symlink in place of the file itself
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("%s already exists, choose another output_path", path)
+		}
+		return err
+	}
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		os.Remove(path)
+		return err
+	}
+	return file.Close()
+}
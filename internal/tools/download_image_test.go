@@ -0,0 +1,164 @@
+package tools
+
+import (
+	"context"
+	"gaia-mcp-go/internal/testutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestDownloadImageTool creates a download_image tool saving into dir that
+// accepts the test server's urls as GAIA image urls
+func newTestDownloadImageTool(server *testutil.TestServer, dir string) *DownloadImageTool {
+	return NewDownloadImageTool(ToolDefaults{DownloadDir: dir, CdnUrl: server.URL})
+}
+
+func TestDownloadImage(t *testing.T) {
+	t.Run("Saves the image under its file name", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		dir := t.TempDir()
+
+		tool := newTestDownloadImageTool(server, dir)
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"image_url": addMockImage(server, "/images/red-fox.png"),
+		}))
+
+		require.NoError(t, err)
+		assert.False(t, result.IsError, resultText(result))
+
+		path := filepath.Join(dir, "red-fox.png")
+		assert.Contains(t, resultText(result), "Image saved to "+path)
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, testutil.CreateMockImage(), data)
+	})
+
+	t.Run("Saves the image at the output path", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		dir := t.TempDir()
+
+		tool := newTestDownloadImageTool(server, dir)
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"image_url":   addMockImage(server, "/images/red-fox.png"),
+			"output_path": "foxes/../animals/fox.png",
+		}))
+
+		require.NoError(t, err)
+		assert.False(t, result.IsError, resultText(result))
+
+		data, err := os.ReadFile(filepath.Join(dir, "animals", "fox.png"))
+		require.NoError(t, err)
+		assert.Equal(t, testutil.CreateMockImage(), data)
+	})
+
+	t.Run("Does not overwrite an existing file", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "fox.png"), []byte("keep me"), 0o644))
+
+		tool := newTestDownloadImageTool(server, dir)
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"image_url":   addMockImage(server, "/images/red-fox.png"),
+			"output_path": "fox.png",
+		}))
+
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, resultText(result), "already exists")
+
+		data, err := os.ReadFile(filepath.Join(dir, "fox.png"))
+		require.NoError(t, err)
+		assert.Equal(t, "keep me", string(data))
+	})
+
+	t.Run("Does not follow a symlink out of the download directory", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		dir := t.TempDir()
+		outside := t.TempDir()
+		require.NoError(t, os.Symlink(outside, filepath.Join(dir, "escape")))
+
+		tool := newTestDownloadImageTool(server, dir)
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"image_url":   addMockImage(server, "/images/red-fox.png"),
+			"output_path": "escape/fox.png",
+		}))
+
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, resultText(result), "outside the download directory")
+
+		entries, err := os.ReadDir(outside)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("Creates private directories", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		dir := filepath.Join(t.TempDir(), "downloads")
+
+		tool := newTestDownloadImageTool(server, dir)
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"image_url":   addMockImage(server, "/images/red-fox.png"),
+			"output_path": "foxes/fox.png",
+		}))
+
+		require.NoError(t, err)
+		assert.False(t, result.IsError, resultText(result))
+
+		for _, path := range []string{dir, filepath.Join(dir, "foxes")} {
+			info, err := os.Stat(path)
+			require.NoError(t, err)
+			assert.Equal(t, os.FileMode(0o700), info.Mode().Perm(), path)
+		}
+	})
+
+	t.Run("Invalid arguments", func(t *testing.T) {
+		dir := t.TempDir()
+
+		tests := []struct {
+			name     string
+			args     map[string]any
+			expected string
+		}{
+			{"Missing image_url", map[string]any{}, "image_url parameter is required"},
+			{"Not a GAIA url", map[string]any{"image_url": "https://example.com/fox.png"}, "image_url must be GAIA's image url"},
+			{"Parent directory", map[string]any{"image_url": "/fox.png", "output_path": "../fox.png"}, "output_path must be a file inside the download directory"},
+			{"Nested traversal", map[string]any{"image_url": "/fox.png", "output_path": "foxes/../../../etc/fox.png"}, "output_path must be a file inside the download directory"},
+			{"Absolute path outside", map[string]any{"image_url": "/fox.png", "output_path": filepath.Join(os.TempDir(), "elsewhere", "fox.png")}, "output_path must be a file inside the download directory"},
+			{"The directory itself", map[string]any{"image_url": "/fox.png", "output_path": "."}, "output_path must be a file inside the download directory"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				server := testutil.NewTestServer()
+				defer server.Close()
+
+				args := tt.args
+				if imageUrl, ok := args["image_url"].(string); ok && imageUrl[0] == '/' {
+					args["image_url"] = server.URL + imageUrl
+				}
+
+				tool := newTestDownloadImageTool(server, dir)
+				result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), args))
+
+				require.NoError(t, err)
+				assert.True(t, result.IsError)
+				assert.Contains(t, resultText(result), tt.expected)
+				assert.Empty(t, server.Requests("GET", "/fox.png"))
+			})
+		}
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+}
@@ -0,0 +1,67 @@
+package tools
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ErrNoImagesGenerated is matched (via errors.Is) by every NoImagesError
+var ErrNoImagesGenerated = errors.New("no images were generated")
+
+// NoImagesError is returned when a generation task succeeds without
+// producing any image. Retrying the same request usually works.
+type NoImagesError struct {
+	// TaskId is the ID of the empty task, when reported by the API
+	TaskId string
+}
+
+// Error implements the error interface for NoImagesError
+func (e *NoImagesError) Error() string {
+	if e.TaskId == "" {
+		return ErrNoImagesGenerated.Error()
+	}
+	return fmt.Sprintf("%s (task ID: %s)", ErrNoImagesGenerated, e.TaskId)
+}
+
+// Is reports whether the target is ErrNoImagesGenerated
+func (e *NoImagesError) Is(target error) bool {
+	return target == ErrNoImagesGenerated
+}
+
+// ErrorKind classifies a tool error result, so clients can react to it
+// without parsing the message
+type ErrorKind string
+
+const (
+	// ErrorKindRetryableEmptyResult marks a generation that returned no images
+	ErrorKindRetryableEmptyResult ErrorKind = "retryable_empty_result"
+)
+
+// Result _meta fields describing a tool error
+const (
+	errorKindMetaField = "errorKind"
+	retryableMetaField = "retryable"
+	taskIdMetaField    = "taskId"
+)
+
+// noImagesResult returns the tool error for a generation without images.
+// The result's _meta carries ErrorKindRetryableEmptyResult and the task ID,
+// when known, which is also included in the message so users can check the
+// task's status.
+func noImagesResult(taskId string) *mcp.CallToolResult {
+	message := "No images were generated. Please try again."
+	meta := map[string]any{
+		errorKindMetaField: ErrorKindRetryableEmptyResult,
+		retryableMetaField: true,
+	}
+	if taskId != "" {
+		message += fmt.Sprintf(" Task ID: %s", taskId)
+		meta[taskIdMetaField] = taskId
+	}
+
+	result := mcp.NewToolResultError(message)
+	result.Meta = meta
+	return result
+}
@@ -0,0 +1,93 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"gaia-mcp-go/internal/api"
+	"gaia-mcp-go/internal/interfaces"
+	"gaia-mcp-go/internal/testutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoImagesError(t *testing.T) {
+	err := error(&NoImagesError{TaskId: "task-123"})
+
+	assert.True(t, errors.Is(err, ErrNoImagesGenerated))
+	assert.Equal(t, "no images were generated (task ID: task-123)", err.Error())
+	assert.Equal(t, "no images were generated", (&NoImagesError{}).Error())
+}
+
+func TestGenerationTools_EmptyResult(t *testing.T) {
+	const imageUrl = "https://cdn.protogaia.com/input.png"
+
+	tests := []struct {
+		name    string
+		newTool func(api.GaiaApi) interfaces.GaiaTool
+		args    map[string]any
+	}{
+		{"generate_image", func(a api.GaiaApi) interfaces.GaiaTool { return NewGenerateImageTool(a) }, map[string]any{"prompt": "A red fox"}},
+		{"generate_image_turbo", func(a api.GaiaApi) interfaces.GaiaTool { return NewGenerateImageTurboTool(a) }, map[string]any{"prompt": "A red fox"}},
+		{"remix", func(a api.GaiaApi) interfaces.GaiaTool { return NewRemixTool(a) }, map[string]any{"inputImage": imageUrl}},
+		{"upscaler", func(a api.GaiaApi) interfaces.GaiaTool { return NewUpscalerTool(a) }, map[string]any{"image_url": imageUrl}},
+		{"face_enhancer", func(a api.GaiaApi) interfaces.GaiaTool { return NewFaceEnhancerTool(a) }, map[string]any{"image_url": imageUrl}},
+		{"inpaint", func(a api.GaiaApi) interfaces.GaiaTool { return NewInpaintTool(a) }, map[string]any{"image_url": imageUrl, "mask_url": imageUrl}},
+		{"pipeline", func(a api.GaiaApi) interfaces.GaiaTool { return NewPipelineTool(a) }, map[string]any{"steps": []any{map[string]any{"type": "generate_image", "params": map[string]any{"prompt": "A red fox"}}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := testutil.NewTestServer()
+			defer server.Close()
+
+			server.AddResponse("POST", createTaskPath, testutil.MockResponse{
+				StatusCode: http.StatusOK,
+				Body:       api.ImageGeneratedResponse{Success: true, TaskId: "task-123"},
+			})
+
+			tool := tt.newTool(newTestApi(server))
+			result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), tt.args))
+
+			require.NoError(t, err)
+			assert.True(t, result.IsError)
+			assert.Contains(t, resultText(result), "task-123")
+			assert.Equal(t, ErrorKindRetryableEmptyResult, result.Meta["errorKind"])
+			assert.Equal(t, true, result.Meta["retryable"])
+			assert.Equal(t, "task-123", result.Meta["taskId"])
+		})
+	}
+
+	t.Run("Without a task ID", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		server.AddResponse("POST", createTaskPath, generatedResponse())
+
+		tool := NewGenerateImageTool(newTestApi(server))
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{"prompt": "A red fox"}))
+
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Equal(t, "No images were generated. Please try again.", resultText(result))
+		assert.Equal(t, ErrorKindRetryableEmptyResult, result.Meta["errorKind"])
+		assert.NotContains(t, result.Meta, "taskId")
+	})
+
+	t.Run("Other errors are not tagged", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+		server.AddResponse("POST", createTaskPath, testutil.MockResponse{
+			StatusCode: http.StatusBadRequest,
+			Body:       map[string]string{"error": "Style not found"},
+		})
+
+		tool := NewGenerateImageTool(newTestApi(server))
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{"prompt": "A red fox"}))
+
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Nil(t, result.Meta)
+	})
+}
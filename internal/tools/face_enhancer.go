@@ -4,22 +4,24 @@ import (
 	"context"
 	"fmt"
 	"gaia-mcp-go/internal/api"
-	"gaia-mcp-go/pkg/imageutil"
 	"gaia-mcp-go/pkg/shared"
 
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
 type FaceEnhancerTool struct {
-	api  api.GaiaApi
-	tool mcp.Tool
+	api      api.GaiaApi
+	tool     mcp.Tool
+	defaults ToolDefaults
 }
 
 func NewFaceEnhancerTool(
 	api api.GaiaApi,
+	overrides ...ToolDefaults,
 ) *FaceEnhancerTool {
 	return &FaceEnhancerTool{
-		api: api,
+		api:      api,
+		defaults: resolveToolDefaults(overrides...),
 		tool: mcp.NewTool(
 			"face_enhancer",
 			mcp.WithDescription("Enhance face's details in an existing image"),
@@ -32,6 +34,9 @@ func NewFaceEnhancerTool(
 				"prompt",
 				mcp.Description("The prompt to tell AI what to enhance."),
 			),
+			withFolderIdParam(),
+			withReturnModeParam(),
+			withIncludeInputParam(),
 		),
 	}
 }
@@ -47,15 +52,25 @@ func (t *FaceEnhancerTool) MCPTool() mcp.Tool {
 func (t *FaceEnhancerTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := req.GetArguments()
 
+	returnMode, err := returnModeArg(req)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
 	imageUrl := args["image_url"]
 	prompt := args["prompt"]
 
+	params := map[string]interface{}{
+		"imageUrl": imageUrl,
+		"prompt":   prompt,
+	}
+	if err := applyFolderId(args, params); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
 	res, err := t.api.GenerateImages(ctx, api.GenerateImagesRequest{
 		RecipeId: shared.RecipeIdFaceEnhancer,
-		Params: map[string]interface{}{
-			"imageUrl": imageUrl,
-			"prompt":   prompt,
-		},
+		Params:   params,
 	})
 
 	if err != nil {
@@ -71,15 +86,16 @@ func (t *FaceEnhancerTool) Handler(ctx context.Context, req mcp.CallToolRequest)
 	}
 
 	if len(res.Images) == 0 {
-		return mcp.NewToolResultError("No images were generated. Please try again."), nil
+		return noImagesResult(res.TaskId), nil
 	}
 
-	base64Data, mimeType, err := imageutil.ProcessImageQuickForMCP(ctx, res.Images[0])
+	msg := fmt.Sprintf("Face enhanced successfully. Image url: %s", res.Images[0])
+
+	result, err := t.defaults.generatedImageResult(ctx, returnMode, msg, res.Images[0], res.Fallbacks(0)...)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to process image: %v", err)), nil
 	}
+	appendInputImage(ctx, t.defaults, req, result, req.GetString("image_url", ""))
 
-	msg := fmt.Sprintf("Face enhanced successfully. Image url: %s", res.Images[0])
-
-	return mcp.NewToolResultImage(msg, base64Data, mimeType), nil
+	return result, nil
 }
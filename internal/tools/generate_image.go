@@ -4,45 +4,100 @@ import (
 	"context"
 	"fmt"
 	"gaia-mcp-go/internal/api"
-	"gaia-mcp-go/pkg/imageutil"
 	"gaia-mcp-go/pkg/shared"
+	"maps"
+	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
 // GenerateImageTool implements the GaiaTool interface
 type GenerateImageTool struct {
-	api  api.GaiaApi
-	tool mcp.Tool
+	api      api.GaiaApi
+	tool     mcp.Tool
+	defaults ToolDefaults
 }
 
-func NewGenerateImageTool(api api.GaiaApi) *GenerateImageTool {
+// NewGenerateImageTool creates the generate_image tool.
+// Optional ToolDefaults override the default aspect ratio and prompt style.
+func NewGenerateImageTool(api api.GaiaApi, overrides ...ToolDefaults) *GenerateImageTool {
+	defaults := resolveToolDefaults(overrides...)
+
 	return &GenerateImageTool{
-		api: api,
+		api:      api,
+		defaults: defaults,
 		tool: mcp.NewTool(
 			"generate_image",
 			mcp.WithDescription("Generate images with Protogaia"),
 			mcp.WithString(
 				"prompt",
-				mcp.Required(),
-				mcp.Description("The prompt to generate an image with"),
+				mcp.Description("The prompt to generate an image with. Required unless prompts is given"),
+			),
+			mcp.WithArray(
+				"prompts",
+				mcp.Description(fmt.Sprintf("Several prompts (up to %d) to generate images for in one call, instead of prompt. Every prompt uses the same settings; a failed prompt is reported without stopping the others.", maxBatchPrompts)),
+				mcp.Items(map[string]any{"type": "string"}),
 			),
 			mcp.WithString(
 				"aspectRatio",
 				mcp.Description("Aspect ratio of the image. One of the following: '1:1', '3:2', '2:3', '16:9', '9:16'"),
-				mcp.DefaultString(string(shared.AspectRatio1_1)),
+				mcp.DefaultString(string(defaults.AspectRatio)),
 				mcp.Enum(shared.GetAspectRatioMap().ToStrings()...),
 			),
+			mcp.WithNumber(
+				"width",
+				mcp.Description(fmt.Sprintf("Exact image width in pixels (%d-%d). Must be given together with height and overrides aspectRatio. Odd values are rounded down to an even number.", minImageDimension, maxImageDimension)),
+				mcp.Min(minImageDimension),
+				mcp.Max(maxImageDimension),
+			),
+			mcp.WithNumber(
+				"height",
+				mcp.Description(fmt.Sprintf("Exact image height in pixels (%d-%d). Must be given together with width and overrides aspectRatio. Odd values are rounded down to an even number.", minImageDimension, maxImageDimension)),
+				mcp.Min(minImageDimension),
+				mcp.Max(maxImageDimension),
+			),
 			mcp.WithString(
 				"promptStyle",
 				mcp.Description("Style to apply to the generated image. Choose from predefined styles. It's not style id and style name."),
-				mcp.DefaultString(string(shared.PromptStyleBase)),
+				mcp.DefaultString(string(defaults.PromptStyle)),
 				mcp.Enum(shared.GetPromptStyleMap().ToStrings()...),
 			),
+			mcp.WithNumber(
+				"numberOfImages",
+				mcp.Description(fmt.Sprintf("Number of images to generate (1-%d). Every image is returned.", maxNumberOfImages)),
+				mcp.DefaultNumber(1),
+				mcp.Min(1),
+				mcp.Max(maxNumberOfImages),
+			),
+			mcp.WithNumber(
+				"seed",
+				mcp.Description("Optional random seed. Generating again with the same seed and settings reproduces the image. Omit it for a random result."),
+				mcp.Min(0),
+			),
 			mcp.WithString(
 				"styleId",
-				mcp.Description("The style ID to use. It must be styleId created by create_style_tool from Gaia"),
+				mcp.Description("The style ID to use. It must be a styleId created by create_style or upload_and_create_style from Gaia"),
 			),
+			withFolderIdParam(),
+			withReturnModeParam(),
+			mcp.WithBoolean(
+				"persist",
+				mcp.DefaultBool(false),
+				mcp.Description("When true, also save the generated images into the user's GAIA library and return the persisted file urls"),
+			),
+			mcp.WithString(
+				"persistResource",
+				mcp.Description("The library resource to save the image under when persist is true"),
+				mcp.DefaultString(string(shared.FileAssociatedResourceWorkspace)),
+				mcp.Enum(shared.GetFileAssociatedResourceMap().ToStrings()...),
+			),
+			withZipParam(),
+			mcp.WithBoolean(
+				"export_recipe",
+				mcp.DefaultBool(false),
+				mcp.Description("When true, also return the resolved generation parameters as a JSON recipe that can be replayed with replay_recipe"),
+			),
+			withExtraParamsParam(),
 		),
 	}
 }
@@ -60,19 +115,262 @@ func (t *GenerateImageTool) Handler(ctx context.Context, req mcp.CallToolRequest
 
 	// Get the arguments from tool call request
 	prompt := args["prompt"]
-	aspectRatio := args["aspectRatio"]
-	promptStyle := args["promptStyle"]
+	prompts, err := batchPromptsArg(args)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	aspectRatio, err := shared.ParseAspectRatio(req.GetString("aspectRatio", string(t.defaults.AspectRatio)))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	promptStyle, err := shared.ParsePromptStyle(req.GetString("promptStyle", string(t.defaults.PromptStyle)))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 	styleId := args["styleId"]
+	numberOfImages, err := numberOfImagesArg(args)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	params := map[string]interface{}{
+		"prompt":         prompt,
+		"aspectRatio":    string(aspectRatio),
+		"promptStyle":    string(promptStyle),
+		"styleId":        styleId,
+		"numberOfImages": numberOfImages,
+	}
+	// Merge the extras first so the known params below override them
+	if err := applyExtraParams(args, params); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := applySeed(args, params); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := applyFolderId(args, params); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := applyDimensions(args, params); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	returnMode, err := returnModeArg(req)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	opts := generateOptions{
+		exportRecipe:    req.GetBool("export_recipe", false),
+		zip:             req.GetBool("zip", false),
+		persist:         req.GetBool("persist", false),
+		persistResource: shared.FileAssociatedResource(req.GetString("persistResource", string(shared.FileAssociatedResourceWorkspace))),
+		progressToken:   progressToken(req),
+		returnMode:      returnMode,
+	}
+	if prompts != nil {
+		return t.generateBatch(ctx, prompts, params, opts)
+	}
+	return t.generate(ctx, params, opts)
+}
+
+// maxBatchPrompts is the largest number of prompts generate_image accepts in one call
+const maxBatchPrompts = 10
+
+// batchPromptsArg reads the optional prompts argument of a batch generation.
+// It returns nil when the call has a single prompt instead, and an error when
+// both or neither of prompt and prompts are given, or the prompts are invalid.
+func batchPromptsArg(args map[string]interface{}) ([]string, error) {
+	hasPrompt := args["prompt"] != nil
+	if args["prompts"] == nil {
+		if !hasPrompt {
+			return nil, fmt.Errorf("prompt parameter is required")
+		}
+		return nil, nil
+	}
+	if hasPrompt {
+		return nil, fmt.Errorf("prompt and prompts cannot be used together")
+	}
+
+	prompts, err := stringArrayArg(args, "prompts")
+	if err != nil {
+		return nil, err
+	}
+	if len(prompts) == 0 {
+		return nil, fmt.Errorf("prompts must contain at least one prompt")
+	}
+	if len(prompts) > maxBatchPrompts {
+		return nil, fmt.Errorf("prompts must contain at most %d prompts, got %d", maxBatchPrompts, len(prompts))
+	}
+	for i, prompt := range prompts {
+		if strings.TrimSpace(prompt) == "" {
+			return nil, fmt.Errorf("prompts[%d] must not be empty", i)
+		}
+	}
+
+	return prompts, nil
+}
+
+// generateBatch generates images for each prompt in turn, with the same params
+// and options. A failed prompt does not stop the batch: its error is reported
+// in the summary and the remaining prompts are still generated.
+// The result of each prompt is also streamed to the client as soon as it is
+// ready, if the transport supports it.
+func (t *GenerateImageTool) generateBatch(ctx context.Context, prompts []string, params map[string]interface{}, opts generateOptions) (*mcp.CallToolResult, error) {
+	// Progress is reported per prompt, so the images of each prompt are not streamed on their own
+	promptOpts := opts
+	promptOpts.batched = true
+
+	notifier := t.defaults.partialResultNotifier()
+	var batch BatchGenerateImageResult
+	for i, prompt := range prompts {
+		promptParams := maps.Clone(params)
+		promptParams["prompt"] = prompt
+
+		result, err := t.generate(ctx, promptParams, promptOpts)
+		if err != nil {
+			return nil, err
+		}
+		batch.Prompts = append(batch.Prompts, BatchPromptResult{Prompt: prompt, Result: result})
+
+		if notifier != nil {
+			outcome := "succeeded"
+			if result.IsError {
+				outcome = "failed"
+			}
+			// Streaming is best effort: the final result carries every prompt anyway
+			_ = notifier.NotifyPartialResult(ctx, PartialResult{
+				ProgressToken: opts.progressToken,
+				Progress:      i + 1,
+				Total:         len(prompts),
+				Message:       fmt.Sprintf("Prompt %d of %d %s: %q. %s", i+1, len(prompts), outcome, prompt, contentText(result.Content)),
+				Content:       result.Content,
+			})
+		}
+	}
+
+	return batch.Render(), nil
+}
+
+// maxNumberOfImages is the largest number of images generate_image requests at once
+const maxNumberOfImages = 4
+
+// numberOfImagesArg reads the optional numberOfImages argument, defaulting to 1.
+// It returns an error when the value is not an integer between 1 and maxNumberOfImages.
+func numberOfImagesArg(args map[string]interface{}) (int, error) {
+	rawValue, exists := args["numberOfImages"]
+	if !exists || rawValue == nil {
+		return 1, nil
+	}
+
+	number, ok := toFloat(rawValue)
+	if !ok || number != float64(int64(number)) {
+		return 0, fmt.Errorf("numberOfImages must be an integer")
+	}
+	if number < 1 || number > maxNumberOfImages {
+		return 0, fmt.Errorf("numberOfImages must be between 1 and %d", maxNumberOfImages)
+	}
+	return int(number), nil
+}
+
+// applySeed forwards the optional seed argument into the recipe params. Without
+// a seed, the backend picks a random one.
+// It returns an error when seed is provided but is not a non-negative integer.
+func applySeed(args map[string]interface{}, params map[string]interface{}) error {
+	rawSeed, exists := args["seed"]
+	if !exists || rawSeed == nil {
+		return nil
+	}
+
+	seed, ok := toFloat(rawSeed)
+	if !ok || seed != float64(int64(seed)) {
+		return fmt.Errorf("seed must be an integer")
+	}
+	if seed < 0 {
+		return fmt.Errorf("seed must not be negative")
+	}
+
+	params["seed"] = int64(seed)
+	return nil
+}
 
+const (
+	// minImageDimension is the smallest width or height the model generates reliably
+	minImageDimension = 256
+	// maxImageDimension is the largest width or height the model accepts
+	maxImageDimension = 2048
+)
+
+// applyDimensions replaces the aspect ratio with the exact width and height
+// arguments when both are provided. Odd values are rounded down to even ones,
+// which the model requires.
+// It returns an error when only one is provided or a value is out of bounds.
+func applyDimensions(args map[string]interface{}, params map[string]interface{}) error {
+	rawWidth, hasWidth := args["width"]
+	rawHeight, hasHeight := args["height"]
+	hasWidth = hasWidth && rawWidth != nil
+	hasHeight = hasHeight && rawHeight != nil
+	if !hasWidth && !hasHeight {
+		return nil
+	}
+	if hasWidth != hasHeight {
+		return fmt.Errorf("width and height must be provided together")
+	}
+
+	width, err := dimensionArg("width", rawWidth)
+	if err != nil {
+		return err
+	}
+	height, err := dimensionArg("height", rawHeight)
+	if err != nil {
+		return err
+	}
+
+	delete(params, "aspectRatio")
+	params["width"] = width
+	params["height"] = height
+	return nil
+}
+
+// dimensionArg converts a width or height argument to an even, bounded pixel count
+func dimensionArg(name string, value interface{}) (int, error) {
+	number, ok := toFloat(value)
+	if !ok || number != float64(int64(number)) {
+		return 0, fmt.Errorf("%s must be an integer", name)
+	}
+	if number < minImageDimension || number > maxImageDimension {
+		return 0, fmt.Errorf("%s must be between %d and %d", name, minImageDimension, maxImageDimension)
+	}
+
+	// Round odd values down to the nearest even number
+	return int(number) &^ 1, nil
+}
+
+// generateOptions controls the optional extras appended to a generate_image result
+type generateOptions struct {
+	// exportRecipe appends the params as a JSON recipe
+	exportRecipe bool
+	// persist uploads the generated image into the user's library
+	persist bool
+	// persistResource is the library resource the image is uploaded under
+	persistResource shared.FileAssociatedResource
+	// zip appends every generated image as a single zip archive
+	zip bool
+	// progressToken is the client's progress token, passed along with streamed images
+	progressToken mcp.ProgressToken
+	// batched marks the generation of one prompt of a batch, whose progress is
+	// streamed per prompt rather than per image
+	batched bool
+	// returnMode selects whether the images are included or only their urls
+	returnMode ReturnMode
+}
+
+// generate runs the recipe with the resolved params and builds the tool result.
+// When opts.exportRecipe is true, the params are appended to the result as a JSON recipe.
+// When several images are generated, each one is also streamed to the client as
+// soon as it is processed, if the transport supports it.
+func (t *GenerateImageTool) generate(ctx context.Context, params map[string]interface{}, opts generateOptions) (*mcp.CallToolResult, error) {
 	res, err := t.api.GenerateImages(ctx, api.GenerateImagesRequest{
 		RecipeId: shared.RecipeIdImageGeneratorSimple,
-		Params: map[string]interface{}{
-			"prompt":         prompt,
-			"aspectRatio":    aspectRatio,
-			"promptStyle":    promptStyle,
-			"styleId":        styleId,
-			"numberOfImages": 1, // Always generate 1 image
-		},
+		Params:   params,
 	})
 
 	if err != nil {
@@ -89,16 +387,53 @@ func (t *GenerateImageTool) Handler(ctx context.Context, req mcp.CallToolRequest
 
 	// Check if we actually received any images
 	if len(res.Images) == 0 {
-		return mcp.NewToolResultError("No images were generated. Please try again."), nil
+		return noImagesResult(res.TaskId), nil
 	}
 
-	// Process the image using the imageutil package for MCP
-	base64Data, mimeType, err := imageutil.ProcessImageQuickForMCP(ctx, res.Images[0])
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to process image: %v", err)), nil
+	// Process every image using the imageutil package for MCP
+	result := GenerateImageResult{
+		ImageUrls: res.Images,
+		QueueType: res.QueueType,
+		Priority:  res.Priority,
 	}
+	notifier := t.defaults.partialResultNotifier()
+	// With url_only the urls in the message are the whole result, so nothing is downloaded
+	if opts.returnMode != ReturnModeUrlOnly {
+		for i, imageUrl := range res.Images {
+			base64Data, mimeType, err := t.defaults.processImage(ctx, imageUrl, res.Fallbacks(i)...)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Failed to process image: %v", err)), nil
+			}
+			image := t.defaults.imageContent(imageUrl, base64Data, mimeType)
+			result.Images = append(result.Images, image)
 
-	msg := fmt.Sprintf("Image generated successfully. Image url: %s", res.Images[0])
+			if notifier != nil && len(res.Images) > 1 && !opts.batched {
+				// Streaming is best effort: the final result carries every image anyway
+				_ = notifier.NotifyPartialResult(ctx, PartialResult{
+					ProgressToken: opts.progressToken,
+					Progress:      i + 1,
+					Total:         len(res.Images),
+					Message:       fmt.Sprintf("Image %d of %d ready. Image url: %s", i+1, len(res.Images), imageUrl),
+					Content:       []mcp.Content{image},
+				})
+			}
+		}
+	}
+	if opts.persist {
+		for _, imageUrl := range res.Images {
+			result.Persisted = append(result.Persisted, persistImage(ctx, t.api, imageUrl, opts.persistResource))
+		}
+	}
+	if opts.zip {
+		result.Zip = t.defaults.zipContents(ctx, t.api, res.Images)
+	}
+	if opts.exportRecipe {
+		recipe, err := ExportRecipe(t.ToolName(), shared.RecipeIdImageGeneratorSimple, params)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		result.Recipe = recipe
+	}
 
-	return mcp.NewToolResultImage(msg, base64Data, mimeType), nil
+	return result.Render(), nil
 }
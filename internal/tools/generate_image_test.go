@@ -0,0 +1,627 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"gaia-mcp-go/internal/api"
+	"gaia-mcp-go/internal/testutil"
+	"gaia-mcp-go/pkg/shared"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateImage_SafeMode(t *testing.T) {
+	server := testutil.NewTestServer()
+	defer server.Close()
+
+	imageUrl := addMockImage(server, "/result.png")
+	server.AddResponse("POST", createTaskPath, testutil.MockResponse{
+		StatusCode: 200,
+		Body: api.ImageGeneratedResponse{
+			Success:           true,
+			Images:            []string{imageUrl},
+			ModerationRatings: []api.ThumbnailModerationRating{api.ThumbnailModerationUnsafe},
+		},
+	})
+
+	tool := NewGenerateImageTool(api.NewGaiaApi(api.GaiaApiConfig{
+		BaseUrl:  server.URL,
+		ApiKey:   "test-key",
+		SafeMode: true,
+	}))
+	result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+		"prompt": "Something unsafe",
+	}))
+
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Equal(t, 0, countImages(result))
+	assert.Contains(t, resultText(result), "safe mode")
+	assert.Empty(t, server.Requests("GET", "/result.png"))
+}
+
+func TestGenerateImage_EmptyResponse(t *testing.T) {
+	server := testutil.NewTestServer()
+	defer server.Close()
+
+	server.AddResponse("POST", createTaskPath, testutil.MockResponse{StatusCode: http.StatusOK, Body: ""})
+
+	tool := NewGenerateImageTool(newTestApi(server))
+	result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+		"prompt": "A cat",
+	}))
+
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, resultText(result), "failed to unmarshal response")
+}
+
+func TestGenerateImage_Persist(t *testing.T) {
+	t.Run("Uploads the result into the library", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		persistedUrl := "https://cdn.protogaia.com/library/result.png"
+		server.AddResponse("POST", createTaskPath, generatedResponse(addMockImage(server, "/result.png")))
+		addMockUpload(server, persistedUrl)
+
+		tool := NewGenerateImageTool(newTestApi(server))
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"prompt":          "A red fox",
+			"persist":         true,
+			"persistResource": "STYLE",
+		}))
+
+		require.NoError(t, err)
+		assert.False(t, result.IsError, resultText(result))
+		assert.Contains(t, resultText(result), "Persisted file url: "+persistedUrl)
+
+		initRequests := server.Requests("POST", uploadInitPath)
+		require.Len(t, initRequests, 1)
+		assert.Contains(t, string(initRequests[0].Body), `"associatedResource":"STYLE"`)
+		assert.Len(t, server.Requests("PUT", "/chunks/1"), 1)
+		assert.Len(t, server.Requests("POST", uploadCompletePath), 1)
+	})
+
+	t.Run("Uploads generated images served from the CDN", func(t *testing.T) {
+		generatedUrl := "https://cdn.protogaia.com/recipe-tasks/6f1c2b7e/0.png"
+		libraryUrl := "https://cdn.protogaia.com/files/6f1c2b7e.png"
+		uploader := &uploadRecorder{fileUrl: libraryUrl}
+
+		// CDN urls cannot be fetched in tests, so check persistImage directly
+		message := persistImage(context.Background(), uploader, generatedUrl, "WORKSPACE")
+
+		assert.Equal(t, "Persisted file url: "+libraryUrl, message)
+		assert.Equal(t, []string{generatedUrl}, uploader.uploaded)
+	})
+
+	t.Run("Reports an image the library already holds", func(t *testing.T) {
+		libraryUrl := "https://cdn.protogaia.com/files/6f1c2b7e.png"
+		uploader := &uploadRecorder{fileUrl: libraryUrl}
+
+		message := persistImage(context.Background(), uploader, libraryUrl, "WORKSPACE")
+
+		assert.Equal(t, "Image is already persisted. Persisted file url: "+libraryUrl, message)
+	})
+
+	t.Run("Does not upload by default", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		server.AddResponse("POST", createTaskPath, generatedResponse(addMockImage(server, "/result.png")))
+
+		tool := NewGenerateImageTool(newTestApi(server))
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"prompt": "A red fox",
+		}))
+
+		require.NoError(t, err)
+		assert.False(t, result.IsError, resultText(result))
+		assert.NotContains(t, resultText(result), "Persisted")
+		assert.Empty(t, server.Requests("POST", uploadInitPath))
+	})
+}
+
+func TestGenerateImage_Dimensions(t *testing.T) {
+	tests := []struct {
+		name              string
+		args              map[string]any
+		expectedWidth     any
+		expectedHeight    any
+		expectAspectRatio bool
+		expectedError     string
+	}{
+		{
+			name:           "Valid dimensions",
+			args:           map[string]any{"width": 1024.0, "height": 768.0},
+			expectedWidth:  1024.0,
+			expectedHeight: 768.0,
+		},
+		{
+			name:           "Odd dimensions are rounded down to even",
+			args:           map[string]any{"width": 1023.0, "height": 257.0},
+			expectedWidth:  1022.0,
+			expectedHeight: 256.0,
+		},
+		{
+			name:           "Dimensions override aspectRatio",
+			args:           map[string]any{"width": 512.0, "height": 512.0, "aspectRatio": "16:9"},
+			expectedWidth:  512.0,
+			expectedHeight: 512.0,
+		},
+		{
+			name:              "Without dimensions aspectRatio is used",
+			args:              map[string]any{"aspectRatio": "16:9"},
+			expectAspectRatio: true,
+		},
+		{
+			name:          "Width without height",
+			args:          map[string]any{"width": 1024.0},
+			expectedError: "width and height must be provided together",
+		},
+		{
+			name:          "Out of bounds",
+			args:          map[string]any{"width": 4096.0, "height": 1024.0},
+			expectedError: "width must be between 256 and 2048",
+		},
+		{
+			name:          "Fractional value",
+			args:          map[string]any{"width": 1024.0, "height": 700.5},
+			expectedError: "height must be an integer",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := testutil.NewTestServer()
+			defer server.Close()
+
+			server.AddResponse("POST", createTaskPath, generatedResponse(addMockImage(server, "/result.png")))
+
+			args := map[string]any{"prompt": "A red fox"}
+			for key, value := range tt.args {
+				args[key] = value
+			}
+
+			tool := NewGenerateImageTool(newTestApi(server))
+			result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), args))
+			require.NoError(t, err)
+
+			requests := server.Requests("POST", createTaskPath)
+			if tt.expectedError != "" {
+				assert.True(t, result.IsError)
+				assert.Equal(t, tt.expectedError, resultText(result))
+				assert.Empty(t, requests)
+				return
+			}
+
+			assert.False(t, result.IsError, resultText(result))
+			require.Len(t, requests, 1)
+			params := decodeTaskParams(t, requests[0])
+
+			if tt.expectAspectRatio {
+				assert.Equal(t, "16:9", params["aspectRatio"])
+				assert.NotContains(t, params, "width")
+				assert.NotContains(t, params, "height")
+				return
+			}
+			assert.Equal(t, tt.expectedWidth, params["width"])
+			assert.Equal(t, tt.expectedHeight, params["height"])
+			assert.NotContains(t, params, "aspectRatio")
+		})
+	}
+}
+
+func TestGenerateImage_ExtraParams(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          map[string]any
+		expected      map[string]any
+		absent        []string
+		expectedError string
+	}{
+		{
+			name: "Extras are merged into the params",
+			args: map[string]any{"extraParams": map[string]any{"guidanceScale": 7.5, "sampler": "euler"}},
+			expected: map[string]any{
+				"prompt":        "A red fox",
+				"guidanceScale": 7.5,
+				"sampler":       "euler",
+			},
+		},
+		{
+			name: "Known params take precedence",
+			args: map[string]any{
+				"aspectRatio": "16:9",
+				"folderId":    "folder-123",
+				"extraParams": map[string]any{
+					"prompt":         "Something else",
+					"aspectRatio":    "1:1",
+					"numberOfImages": 4.0,
+					"folderId":       "folder-456",
+				},
+			},
+			expected: map[string]any{
+				"prompt":         "A red fox",
+				"aspectRatio":    "16:9",
+				"numberOfImages": 1.0,
+				"folderId":       "folder-123",
+			},
+		},
+		{
+			name: "Dimensions remove an extra aspectRatio",
+			args: map[string]any{
+				"width":       512.0,
+				"height":      512.0,
+				"extraParams": map[string]any{"aspectRatio": "1:1", "width": 2048.0},
+			},
+			expected: map[string]any{"width": 512.0, "height": 512.0},
+			absent:   []string{"aspectRatio"},
+		},
+		{
+			name:          "Not an object",
+			args:          map[string]any{"extraParams": "guidanceScale=7.5"},
+			expectedError: "extraParams must be a JSON object",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := testutil.NewTestServer()
+			defer server.Close()
+
+			server.AddResponse("POST", createTaskPath, generatedResponse(addMockImage(server, "/result.png")))
+
+			args := map[string]any{"prompt": "A red fox"}
+			for key, value := range tt.args {
+				args[key] = value
+			}
+
+			tool := NewGenerateImageTool(newTestApi(server))
+			result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), args))
+			require.NoError(t, err)
+
+			requests := server.Requests("POST", createTaskPath)
+			if tt.expectedError != "" {
+				assert.True(t, result.IsError)
+				assert.Equal(t, tt.expectedError, resultText(result))
+				assert.Empty(t, requests)
+				return
+			}
+
+			assert.False(t, result.IsError, resultText(result))
+			require.Len(t, requests, 1)
+			params := decodeTaskParams(t, requests[0])
+			for key, value := range tt.expected {
+				assert.Equal(t, value, params[key], key)
+			}
+			for _, key := range tt.absent {
+				assert.NotContains(t, params, key)
+			}
+		})
+	}
+}
+
+func TestGenerateImage_QueueInfo(t *testing.T) {
+	server := testutil.NewTestServer()
+	defer server.Close()
+
+	server.AddResponse("POST", createTaskPath, testutil.MockResponse{
+		StatusCode: http.StatusOK,
+		Body: map[string]any{
+			"success":   true,
+			"images":    []string{addMockImage(server, "/result.png")},
+			"queueType": "fast",
+			"priority":  5,
+		},
+	})
+
+	tool := NewGenerateImageTool(newTestApi(server))
+	result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{"prompt": "A red fox"}))
+
+	require.NoError(t, err)
+	assert.False(t, result.IsError, resultText(result))
+	assert.Contains(t, resultText(result), "Queued on the fast queue (priority 5)")
+}
+
+func TestGenerateImage_NumberOfImages(t *testing.T) {
+	t.Run("Returns every generated image", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		images := []string{
+			addMockImage(server, "/result-1.png"),
+			addMockImage(server, "/result-2.png"),
+			addMockImage(server, "/result-3.png"),
+		}
+		server.AddResponse("POST", createTaskPath, generatedResponse(images...))
+
+		tool := NewGenerateImageTool(newTestApi(server))
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"prompt":         "A red fox",
+			"numberOfImages": 3.0,
+		}))
+
+		require.NoError(t, err)
+		assert.False(t, result.IsError, resultText(result))
+		assert.Equal(t, 3, countImages(result))
+
+		text := resultText(result)
+		assert.Contains(t, text, "Generated 3 images successfully")
+		for _, image := range images {
+			assert.Contains(t, text, "- "+image)
+		}
+
+		requests := server.Requests("POST", createTaskPath)
+		require.Len(t, requests, 1)
+		assert.Equal(t, 3.0, decodeTaskParams(t, requests[0])["numberOfImages"])
+	})
+
+	t.Run("Defaults to one image", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		server.AddResponse("POST", createTaskPath, generatedResponse(addMockImage(server, "/result.png")))
+
+		tool := NewGenerateImageTool(newTestApi(server))
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{"prompt": "A red fox"}))
+
+		require.NoError(t, err)
+		assert.False(t, result.IsError, resultText(result))
+		assert.Equal(t, 1, countImages(result))
+
+		requests := server.Requests("POST", createTaskPath)
+		require.Len(t, requests, 1)
+		assert.Equal(t, 1.0, decodeTaskParams(t, requests[0])["numberOfImages"])
+	})
+
+	for _, tt := range []struct {
+		value    any
+		expected string
+	}{
+		{5.0, "numberOfImages must be between 1 and 4"},
+		{0.0, "numberOfImages must be between 1 and 4"},
+		{2.5, "numberOfImages must be an integer"},
+	} {
+		t.Run(fmt.Sprintf("Rejects %v", tt.value), func(t *testing.T) {
+			server := testutil.NewTestServer()
+			defer server.Close()
+
+			tool := NewGenerateImageTool(newTestApi(server))
+			result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+				"prompt":         "A red fox",
+				"numberOfImages": tt.value,
+			}))
+
+			require.NoError(t, err)
+			assert.True(t, result.IsError)
+			assert.Equal(t, tt.expected, resultText(result))
+			assert.Empty(t, server.Requests("POST", createTaskPath))
+		})
+	}
+}
+
+func TestGenerateImage_FallbackImageUrl(t *testing.T) {
+	server := testutil.NewTestServer()
+	defer server.Close()
+
+	// The CDN url 404s until it propagates, the origin already serves the image
+	cdnUrl := server.URL + "/cdn/result.png"
+	server.AddResponse("POST", createTaskPath, testutil.MockResponse{
+		StatusCode: http.StatusOK,
+		Body: api.ImageGeneratedResponse{
+			Success:      true,
+			Images:       []string{cdnUrl},
+			OriginImages: []string{addMockImage(server, "/origin/result.png")},
+		},
+	})
+
+	tool := NewGenerateImageTool(newTestApi(server))
+	result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{"prompt": "A red fox"}))
+
+	require.NoError(t, err)
+	assert.False(t, result.IsError, resultText(result))
+	assert.Equal(t, 1, countImages(result))
+	assert.Contains(t, resultText(result), "Image url: "+cdnUrl)
+	assert.Len(t, server.Requests("GET", "/cdn/result.png"), 1)
+	assert.Len(t, server.Requests("GET", "/origin/result.png"), 1)
+}
+
+func TestGenerateImage_Seed(t *testing.T) {
+	tests := []struct {
+		name          string
+		seed          any
+		expectedSeed  any
+		expectedError string
+	}{
+		{name: "Seed is forwarded", seed: 42.0, expectedSeed: 42.0},
+		{name: "Zero is a valid seed", seed: 0.0, expectedSeed: 0.0},
+		{name: "Without a seed the backend randomizes"},
+		{name: "Negative seed", seed: -1.0, expectedError: "seed must not be negative"},
+		{name: "Fractional seed", seed: 4.2, expectedError: "seed must be an integer"},
+		{name: "Non-numeric seed", seed: "lucky", expectedError: "seed must be an integer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := testutil.NewTestServer()
+			defer server.Close()
+
+			server.AddResponse("POST", createTaskPath, generatedResponse(addMockImage(server, "/result.png")))
+
+			args := map[string]any{"prompt": "A red fox"}
+			if tt.seed != nil {
+				args["seed"] = tt.seed
+			}
+
+			tool := NewGenerateImageTool(newTestApi(server))
+			result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), args))
+			require.NoError(t, err)
+
+			requests := server.Requests("POST", createTaskPath)
+			if tt.expectedError != "" {
+				assert.True(t, result.IsError)
+				assert.Equal(t, tt.expectedError, resultText(result))
+				assert.Empty(t, requests)
+				return
+			}
+
+			assert.False(t, result.IsError, resultText(result))
+			require.Len(t, requests, 1)
+			params := decodeTaskParams(t, requests[0])
+			if tt.expectedSeed == nil {
+				assert.NotContains(t, params, "seed")
+				return
+			}
+			assert.Equal(t, tt.expectedSeed, params["seed"])
+		})
+	}
+}
+
+func TestGenerateImage_RejectsInvalidEnums(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     map[string]any
+		expected string
+	}{
+		{"Aspect ratio", map[string]any{"aspectRatio": "4:3"}, `invalid aspect ratio "4:3": must be one of 16:9, 1:1, 2:3, 3:2, 9:16`},
+		{"Prompt style", map[string]any{"promptStyle": "vaporwave"}, `invalid prompt style "vaporwave": must be one of 3d-model, analog film`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := testutil.NewTestServer()
+			defer server.Close()
+
+			args := map[string]any{"prompt": "A red fox"}
+			for key, value := range tt.args {
+				args[key] = value
+			}
+
+			tool := NewGenerateImageTool(newTestApi(server))
+			result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), args))
+
+			require.NoError(t, err)
+			assert.True(t, result.IsError)
+			assert.Contains(t, resultText(result), tt.expected)
+			assert.Empty(t, server.Requests("POST", createTaskPath))
+		})
+	}
+}
+
+func TestGenerateImage_Batch(t *testing.T) {
+	t.Run("Continues after a failed prompt", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		foxUrl := addMockImage(server, "/fox.png")
+		owlUrl := addMockImage(server, "/owl.png")
+		server.AddResponseSequence("POST", createTaskPath,
+			generatedResponse(foxUrl),
+			testutil.MockResponse{
+				StatusCode: http.StatusOK,
+				Body: map[string]interface{}{
+					"success": false,
+					"images":  []string{},
+					"error":   "The prompt could not be generated",
+				},
+			},
+			generatedResponse(owlUrl),
+		)
+
+		tool := NewGenerateImageTool(newTestApi(server))
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"prompts": []interface{}{"A red fox", "A grey wolf", "A snowy owl"},
+		}))
+
+		require.NoError(t, err)
+		assert.False(t, result.IsError, resultText(result))
+		assert.Equal(t, 2, countImages(result))
+
+		text := resultText(result)
+		assert.Contains(t, text, "Generated images for 2 of 3 prompts:")
+		assert.Contains(t, text, `1. "A red fox" succeeded`)
+		assert.Contains(t, text, `2. "A grey wolf" failed: The prompt could not be generated`)
+		assert.Contains(t, text, `3. "A snowy owl" succeeded`)
+		assert.Contains(t, text, foxUrl)
+		assert.Contains(t, text, owlUrl)
+
+		requests := server.Requests("POST", createTaskPath)
+		require.Len(t, requests, 3)
+		for i, prompt := range []string{"A red fox", "A grey wolf", "A snowy owl"} {
+			assert.Equal(t, prompt, decodeTaskParams(t, requests[i])["prompt"])
+		}
+	})
+
+	t.Run("Is an error when every prompt fails", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		server.AddResponse("POST", createTaskPath, testutil.MockResponse{
+			StatusCode: http.StatusOK,
+			Body: map[string]interface{}{
+				"success": false,
+				"images":  []string{},
+				"error":   "Service unavailable",
+			},
+		})
+
+		tool := NewGenerateImageTool(newTestApi(server))
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"prompts": []interface{}{"A red fox", "A grey wolf"},
+		}))
+
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, resultText(result), "Generated images for 0 of 2 prompts:")
+		assert.Len(t, server.Requests("POST", createTaskPath), 2)
+	})
+
+	tests := []struct {
+		name     string
+		args     map[string]any
+		expected string
+	}{
+		{"Neither prompt nor prompts", map[string]any{}, "prompt parameter is required"},
+		{"Both prompt and prompts", map[string]any{"prompt": "A red fox", "prompts": []interface{}{"A grey wolf"}}, "prompt and prompts cannot be used together"},
+		{"Empty prompts", map[string]any{"prompts": []interface{}{}}, "prompts must contain at least one prompt"},
+		{"Blank prompt", map[string]any{"prompts": []interface{}{"A red fox", " "}}, "prompts[1] must not be empty"},
+		{"Too many prompts", map[string]any{"prompts": []interface{}{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11"}}, "prompts must contain at most 10 prompts, got 11"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("Rejects: %s", tt.name), func(t *testing.T) {
+			server := testutil.NewTestServer()
+			defer server.Close()
+
+			tool := NewGenerateImageTool(newTestApi(server))
+			result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), tt.args))
+
+			require.NoError(t, err)
+			assert.True(t, result.IsError)
+			assert.Equal(t, tt.expected, resultText(result))
+			assert.Empty(t, server.Requests("POST", createTaskPath))
+		})
+	}
+}
+
+// uploadRecorder is a GaiaApi whose UploadImages records the uploaded urls and
+// returns a library file at fileUrl for each
+type uploadRecorder struct {
+	api.GaiaApi
+	fileUrl  string
+	uploaded []string
+}
+
+func (u *uploadRecorder) UploadImages(ctx context.Context, imageUrls []string, resource shared.FileAssociatedResource, opts ...api.UploadOptions) ([]api.UploadFile, error) {
+	u.uploaded = append(u.uploaded, imageUrls...)
+
+	files := make([]api.UploadFile, len(imageUrls))
+	for i := range imageUrls {
+		files[i] = api.UploadFile{Id: "file-id", Url: &u.fileUrl}
+	}
+	return files, nil
+}
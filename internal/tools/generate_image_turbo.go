@@ -0,0 +1,115 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"gaia-mcp-go/internal/api"
+	"gaia-mcp-go/pkg/shared"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// GenerateImageTurboTool generates images with the low-latency turbo type of the
+// simple recipe.
+//
+// Turbo tasks run on the fast queue and use fewer sampling steps, so
+// results come back in a few seconds at the cost of less detail and weaker
+// prompt adherence than generate_image. It only accepts a reduced parameter
+// set: no prompt styles or custom styles.
+type GenerateImageTurboTool struct {
+	api      api.GaiaApi
+	tool     mcp.Tool
+	defaults ToolDefaults
+}
+
+// NewGenerateImageTurboTool creates the generate_image_turbo tool.
+// Optional ToolDefaults override the default aspect ratio.
+func NewGenerateImageTurboTool(api api.GaiaApi, overrides ...ToolDefaults) *GenerateImageTurboTool {
+	defaults := resolveToolDefaults(overrides...)
+
+	return &GenerateImageTurboTool{
+		api:      api,
+		defaults: defaults,
+		tool: mcp.NewTool(
+			"generate_image_turbo",
+			mcp.WithDescription("Generate images quickly with Protogaia's turbo model. Much faster than generate_image but with less detail; use it for drafts and quick iterations, and generate_image for final results."),
+			mcp.WithString(
+				"prompt",
+				mcp.Required(),
+				mcp.Description("The prompt to generate an image with"),
+			),
+			mcp.WithString(
+				"aspectRatio",
+				mcp.Description("Aspect ratio of the image. One of the following: '1:1', '3:2', '2:3', '16:9', '9:16'"),
+				mcp.DefaultString(string(defaults.AspectRatio)),
+				mcp.Enum(shared.GetAspectRatioMap().ToStrings()...),
+			),
+			withFolderIdParam(),
+			withReturnModeParam(),
+		),
+	}
+}
+
+func (t *GenerateImageTurboTool) ToolName() string {
+	return "generate_image_turbo"
+}
+
+func (t *GenerateImageTurboTool) MCPTool() mcp.Tool {
+	return t.tool
+}
+
+func (t *GenerateImageTurboTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := req.GetArguments()
+
+	returnMode, err := returnModeArg(req)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	aspectRatio, err := shared.ParseAspectRatio(req.GetString("aspectRatio", string(t.defaults.AspectRatio)))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	params := map[string]interface{}{
+		"prompt":         args["prompt"],
+		"aspectRatio":    string(aspectRatio),
+		"numberOfImages": 1, // Always generate 1 image
+	}
+	if err := applyFolderId(args, params); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// Turbo is a variant of the simple generator, run on the fast queue
+	res, err := t.api.GenerateImages(ctx, api.GenerateImagesRequest{
+		RecipeId:   shared.RecipeIdImageGeneratorSimple,
+		RecipeType: shared.RecipeTypeTurbo,
+		QueueType:  shared.QueueTypeFast,
+		Params:     params,
+	})
+
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	if res.Error != nil {
+		return mcp.NewToolResultError(*res.Error), nil
+	}
+
+	if !res.Success {
+		return mcp.NewToolResultError("Turbo generation was not successful"), nil
+	}
+
+	if len(res.Images) == 0 {
+		return noImagesResult(res.TaskId), nil
+	}
+
+	msg := fmt.Sprintf("Image generated successfully with turbo. Image url: %s", res.Images[0])
+
+	result, err := t.defaults.generatedImageResult(ctx, returnMode, msg, res.Images[0], res.Fallbacks(0)...)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to process image: %v", err)), nil
+	}
+
+	return result, nil
+}
@@ -0,0 +1,103 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"gaia-mcp-go/internal/api"
+	"gaia-mcp-go/internal/testutil"
+	"gaia-mcp-go/pkg/shared"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateImageTurbo(t *testing.T) {
+	server := testutil.NewTestServer()
+	defer server.Close()
+
+	server.AddResponse("POST", createTaskPath, generatedResponse(addMockImage(server, "/result.png")))
+
+	tool := NewGenerateImageTurboTool(newTestApi(server))
+	result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+		"prompt":      "A quick sketch of a cat",
+		"aspectRatio": "16:9",
+	}))
+
+	require.NoError(t, err)
+	assert.False(t, result.IsError, resultText(result))
+	assert.Equal(t, 1, countImages(result))
+
+	requests := server.Requests("POST", createTaskPath)
+	require.Len(t, requests, 1)
+
+	var payload struct {
+		RecipeId   shared.RecipeId        `json:"recipeId"`
+		RecipeType shared.RecipeType      `json:"recipeType"`
+		QueueType  shared.QueueType       `json:"queueType"`
+		Params     map[string]interface{} `json:"params"`
+	}
+	require.NoError(t, json.Unmarshal(requests[0].Body, &payload))
+	assert.Equal(t, shared.RecipeIdImageGeneratorSimple, payload.RecipeId)
+	assert.Equal(t, shared.RecipeTypeTurbo, payload.RecipeType)
+	assert.Equal(t, shared.QueueTypeFast, payload.QueueType)
+	assert.Equal(t, "A quick sketch of a cat", payload.Params["prompt"])
+	assert.Equal(t, "16:9", payload.Params["aspectRatio"])
+	assert.NotContains(t, payload.Params, "promptStyle")
+}
+
+func TestGenerateImageTurbo_Unsuccessful(t *testing.T) {
+	message := "Queue is full"
+	tests := []struct {
+		name     string
+		response api.ImageGeneratedResponse
+		expected string
+	}{
+		{"Reports the server's error", api.ImageGeneratedResponse{Success: false, Error: &message}, "Queue is full"},
+		{"Falls back without an error", api.ImageGeneratedResponse{Success: false}, "Turbo generation was not successful"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := testutil.NewTestServer()
+			defer server.Close()
+
+			server.AddResponse("POST", createTaskPath, testutil.MockResponse{StatusCode: http.StatusOK, Body: tt.response})
+
+			tool := NewGenerateImageTurboTool(newTestApi(server))
+			result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+				"prompt": "A quick sketch of a cat",
+			}))
+
+			require.NoError(t, err)
+			assert.True(t, result.IsError)
+			assert.Equal(t, tt.expected, resultText(result))
+		})
+	}
+}
+
+func TestGenerateImageTurbo_ReducedParameters(t *testing.T) {
+	properties := NewGenerateImageTurboTool(nil).MCPTool().InputSchema.Properties
+
+	assert.Contains(t, properties, "prompt")
+	assert.Contains(t, properties, "aspectRatio")
+	assert.NotContains(t, properties, "promptStyle")
+	assert.NotContains(t, properties, "styleId")
+}
+
+func TestGenerateImageTurbo_RejectsInvalidAspectRatio(t *testing.T) {
+	server := testutil.NewTestServer()
+	defer server.Close()
+
+	tool := NewGenerateImageTurboTool(newTestApi(server))
+	result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+		"prompt":      "A quick sketch of a cat",
+		"aspectRatio": "4:3",
+	}))
+
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, resultText(result), `invalid aspect ratio "4:3"`)
+	assert.Empty(t, server.Requests("POST", createTaskPath))
+}
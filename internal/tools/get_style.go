@@ -0,0 +1,72 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"gaia-mcp-go/internal/api"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// GetStyleTool shows the details of a style and the reference images it was built from
+type GetStyleTool struct {
+	api  api.GaiaApi
+	tool mcp.Tool
+}
+
+func NewGetStyleTool(api api.GaiaApi) *GetStyleTool {
+	return &GetStyleTool{
+		api: api,
+		tool: mcp.NewTool(
+			"get_style",
+			mcp.WithDescription("Get a GAIA style by its ID: its name, description, sharing mode and the urls of the reference images it was created from."),
+			mcp.WithString(
+				"style_id",
+				mcp.Required(),
+				mcp.Description("The ID of the style, as returned when the style was created"),
+			),
+		),
+	}
+}
+
+func (t *GetStyleTool) ToolName() string {
+	return "get_style"
+}
+
+func (t *GetStyleTool) MCPTool() mcp.Tool {
+	return t.tool
+}
+
+func (t *GetStyleTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	styleId, err := req.RequireString("style_id")
+	if err != nil || strings.TrimSpace(styleId) == "" {
+		return mcp.NewToolResultError("style_id parameter is required"), nil
+	}
+
+	style, err := t.api.GetStyle(ctx, strings.TrimSpace(styleId))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get style: %v", err)), nil
+	}
+
+	details := fmt.Sprintf("Style: %s\nStyle ID: %s\nSharing mode: %s", style.Name, style.Id, style.SharingMode)
+	if style.Description != "" {
+		details += fmt.Sprintf("\nDescription: %s", style.Description)
+	}
+	if style.ThumbnailUrl != "" {
+		details += fmt.Sprintf("\nThumbnail: %s", style.ThumbnailUrl)
+	}
+
+	// One content block per reference image so clients can show them separately
+	content := []mcp.Content{mcp.NewTextContent(details)}
+	if len(style.Images) == 0 {
+		content = append(content, mcp.NewTextContent("The style has no reference images."))
+	}
+	for i, image := range style.Images {
+		content = append(content, mcp.NewTextContent(
+			fmt.Sprintf("Reference image %d (weight %g): %s", i+1, image.Weight, image.Url),
+		))
+	}
+
+	return &mcp.CallToolResult{Content: content}, nil
+}
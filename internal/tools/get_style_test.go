@@ -0,0 +1,105 @@
+package tools
+
+import (
+	"context"
+	"gaia-mcp-go/internal/api"
+	"gaia-mcp-go/internal/testutil"
+	"net/http"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetStyle(t *testing.T) {
+	t.Run("Lists the style details and reference images", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		server.AddResponse("GET", "/api/sd-styles/style-123", testutil.MockResponse{
+			StatusCode: http.StatusOK,
+			Body: api.SdStyle{
+				Id:          "style-123",
+				Name:        "Watercolor",
+				Description: "Soft washes of color",
+				SharingMode: api.SharingModePrivate,
+				Images: []api.SdStyleImage{
+					{Url: "https://cdn.protogaia.com/styles/first.png", Weight: 0.5},
+					{Url: "https://cdn.protogaia.com/styles/second.png", Weight: 0.8},
+					{Url: "https://cdn.protogaia.com/styles/third.png", Weight: 1},
+				},
+			},
+		})
+
+		tool := NewGetStyleTool(newTestApi(server))
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"style_id": "style-123",
+		}))
+
+		require.NoError(t, err)
+		assert.False(t, result.IsError, resultText(result))
+		require.Len(t, result.Content, 4)
+
+		details, ok := mcp.AsTextContent(result.Content[0])
+		require.True(t, ok)
+		assert.Contains(t, details.Text, "Style: Watercolor")
+		assert.Contains(t, details.Text, "Style ID: style-123")
+		assert.Contains(t, details.Text, "Sharing mode: private")
+		assert.Contains(t, details.Text, "Description: Soft washes of color")
+
+		text := resultText(result)
+		assert.Contains(t, text, "Reference image 1 (weight 0.5): https://cdn.protogaia.com/styles/first.png")
+		assert.Contains(t, text, "Reference image 2 (weight 0.8): https://cdn.protogaia.com/styles/second.png")
+		assert.Contains(t, text, "Reference image 3 (weight 1): https://cdn.protogaia.com/styles/third.png")
+	})
+
+	t.Run("Style without reference images", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		server.AddResponse("GET", "/api/sd-styles/style-456", testutil.MockResponse{
+			StatusCode: http.StatusOK,
+			Body:       api.SdStyle{Id: "style-456", Name: "Empty", SharingMode: api.SharingModePublic},
+		})
+
+		tool := NewGetStyleTool(newTestApi(server))
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"style_id": "style-456",
+		}))
+
+		require.NoError(t, err)
+		assert.False(t, result.IsError)
+		assert.Contains(t, resultText(result), "The style has no reference images.")
+		assert.NotContains(t, resultText(result), "Description:")
+	})
+
+	t.Run("API errors are reported", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		server.AddResponse("GET", "/api/sd-styles/missing", testutil.MockResponse{
+			StatusCode: http.StatusNotFound,
+			Body:       map[string]interface{}{"message": "Style not found"},
+		})
+
+		tool := NewGetStyleTool(newTestApi(server))
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"style_id": "missing",
+		}))
+
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, resultText(result), "Failed to get style")
+		assert.Contains(t, resultText(result), "The style ID is invalid or no longer exists")
+	})
+
+	t.Run("Missing style_id", func(t *testing.T) {
+		tool := NewGetStyleTool(nil)
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{}))
+
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, resultText(result), "style_id parameter is required")
+	})
+}
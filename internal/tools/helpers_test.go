@@ -0,0 +1,116 @@
+package tools
+
+import (
+	"encoding/json"
+	"gaia-mcp-go/internal/api"
+	"gaia-mcp-go/internal/testutil"
+	"net/http"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/require"
+)
+
+// createTaskPath is the Gaia endpoint used by all generation tools
+const createTaskPath = "/api/recipe/agi-tasks/create-task"
+
+// newTestApi creates a Gaia API client pointed at the test server
+func newTestApi(server *testutil.TestServer) api.GaiaApi {
+	return api.NewGaiaApi(api.GaiaApiConfig{
+		BaseUrl: server.URL,
+		ApiKey:  "test-key",
+	})
+}
+
+// newCallToolRequest builds an MCP tool call request with the given arguments
+func newCallToolRequest(name string, args map[string]any) mcp.CallToolRequest {
+	req := mcp.CallToolRequest{}
+	req.Params.Name = name
+	req.Params.Arguments = args
+	return req
+}
+
+// addMockImage serves the mock PNG image at the given path and returns its URL
+func addMockImage(server *testutil.TestServer, path string) string {
+	server.AddResponse("GET", path, testutil.MockResponse{
+		StatusCode: http.StatusOK,
+		Body:       testutil.CreateMockImage(),
+		Headers:    map[string]string{"Content-Type": "image/png"},
+	})
+	return server.URL + path
+}
+
+// generatedResponse returns a successful create-task mock response with the given images
+func generatedResponse(images ...string) testutil.MockResponse {
+	return testutil.MockResponse{
+		StatusCode: http.StatusOK,
+		Body: api.ImageGeneratedResponse{
+			Success: true,
+			Images:  images,
+		},
+	}
+}
+
+// decodeTaskParams decodes the recipe params from a recorded create-task request
+func decodeTaskParams(t *testing.T, req testutil.RecordedRequest) map[string]interface{} {
+	t.Helper()
+
+	var payload struct {
+		RecipeId string                 `json:"recipeId"`
+		Params   map[string]interface{} `json:"params"`
+	}
+	require.NoError(t, json.Unmarshal(req.Body, &payload))
+	return payload.Params
+}
+
+// resultText returns the concatenated text content of a tool result
+func resultText(result *mcp.CallToolResult) string {
+	var text string
+	for _, content := range result.Content {
+		if textContent, ok := mcp.AsTextContent(content); ok {
+			text += textContent.Text
+		}
+	}
+	return text
+}
+
+// countImages returns the number of image content blocks in a tool result
+func countImages(result *mcp.CallToolResult) int {
+	count := 0
+	for _, content := range result.Content {
+		if _, ok := mcp.AsImageContent(content); ok {
+			count++
+		}
+	}
+	return count
+}
+
+// uploadInitPath and uploadCompletePath are the Gaia endpoints of the multipart upload flow
+const (
+	uploadInitPath     = "/api/upload/initialize"
+	uploadCompletePath = "/api/upload/complete"
+)
+
+// addMockUpload serves the multipart upload flow. Consecutive uploads return
+// the given file URLs in order, each uploaded as a single chunk.
+func addMockUpload(server *testutil.TestServer, fileUrls ...string) {
+	responses := make([]testutil.MockResponse, len(fileUrls))
+	for i, fileUrl := range fileUrls {
+		responses[i] = testutil.MockResponse{
+			StatusCode: http.StatusOK,
+			Body: []api.InitUploadResponse{{
+				Key:        "upload-key",
+				UploadId:   "upload-id",
+				UploadUrls: []string{server.URL + "/chunks/1"},
+				File:       api.UploadFile{Id: "file-id", Url: &fileUrl},
+			}},
+		}
+	}
+
+	server.AddResponseSequence("POST", uploadInitPath, responses...)
+	server.AddResponse("PUT", "/chunks/1", testutil.MockResponse{
+		StatusCode: http.StatusOK,
+		Headers:    map[string]string{"ETag": "etag-1"},
+	})
+	server.AddResponse("POST", uploadCompletePath, testutil.MockResponse{StatusCode: http.StatusOK})
+}
@@ -0,0 +1,119 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"gaia-mcp-go/internal/api"
+	"gaia-mcp-go/pkg/shared"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// InpaintTool repaints the masked area of an existing image
+type InpaintTool struct {
+	api      api.GaiaApi
+	tool     mcp.Tool
+	defaults ToolDefaults
+}
+
+func NewInpaintTool(
+	api api.GaiaApi,
+	overrides ...ToolDefaults,
+) *InpaintTool {
+	defaults := resolveToolDefaults(overrides...)
+
+	return &InpaintTool{
+		api:      api,
+		defaults: defaults,
+		tool: mcp.NewTool(
+			"inpaint",
+			mcp.WithDescription("Repaint part of an existing image. The white area of the mask is regenerated from the prompt, the rest of the image is kept"),
+			mcp.WithString(
+				"image_url",
+				mcp.Required(),
+				mcp.Description(fmt.Sprintf("The image URL to inpaint. It must be GAIA's image url: starts with `%s/`", defaults.CdnUrl)),
+			),
+			mcp.WithString(
+				"mask_url",
+				mcp.Required(),
+				mcp.Description(fmt.Sprintf("The mask image URL, white where the image should be repainted. It must be GAIA's image url: starts with `%s/`", defaults.CdnUrl)),
+			),
+			mcp.WithString(
+				"prompt",
+				mcp.Description("The prompt describing what to paint in the masked area."),
+			),
+			withFolderIdParam(),
+			withReturnModeParam(),
+			withIncludeInputParam(),
+		),
+	}
+}
+
+func (t *InpaintTool) ToolName() string {
+	return "inpaint"
+}
+
+func (t *InpaintTool) MCPTool() mcp.Tool {
+	return t.tool
+}
+
+func (t *InpaintTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := req.GetArguments()
+
+	returnMode, err := returnModeArg(req)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	imageUrl, err := cdnImageUrlArg(args, "image_url", t.defaults.CdnUrl)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	maskUrl, err := cdnImageUrlArg(args, "mask_url", t.defaults.CdnUrl)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	params := map[string]interface{}{
+		"image":  imageUrl,
+		"mask":   maskUrl,
+		"prompt": req.GetString("prompt", ""),
+	}
+	if err := applyFolderId(args, params); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// Inpainting is a variant of the simple generator
+	res, err := t.api.GenerateImages(ctx, api.GenerateImagesRequest{
+		RecipeId:   shared.RecipeIdImageGeneratorSimple,
+		RecipeType: shared.RecipeTypeInpaint,
+		Params:     params,
+	})
+
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	if res.Error != nil {
+		return mcp.NewToolResultError(*res.Error), nil
+	}
+
+	if !res.Success {
+		return mcp.NewToolResultError("Failed to inpaint the image"), nil
+	}
+
+	if len(res.Images) == 0 {
+		return noImagesResult(res.TaskId), nil
+	}
+
+	msg := fmt.Sprintf("Inpainted successfully. Image url: %s", res.Images[0])
+
+	result, err := t.defaults.generatedImageResult(ctx, returnMode, msg, res.Images[0], res.Fallbacks(0)...)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to process image: %v", err)), nil
+	}
+	appendInputImage(ctx, t.defaults, req, result, imageUrl)
+
+	return result, nil
+}
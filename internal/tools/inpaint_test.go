@@ -0,0 +1,105 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"gaia-mcp-go/internal/testutil"
+	"gaia-mcp-go/pkg/shared"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInpaint(t *testing.T) {
+	const (
+		imageUrl = "https://cdn.protogaia.com/input.png"
+		maskUrl  = "https://cdn.protogaia.com/mask.png"
+	)
+
+	t.Run("Inpaints the masked area", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		resultUrl := addMockImage(server, "/result.png")
+		server.AddResponse("POST", createTaskPath, generatedResponse(resultUrl))
+
+		tool := NewInpaintTool(newTestApi(server))
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"image_url": imageUrl,
+			"mask_url":  maskUrl,
+			"prompt":    "A red hat",
+		}))
+
+		require.NoError(t, err)
+		assert.False(t, result.IsError, resultText(result))
+		assert.Equal(t, 1, countImages(result))
+		assert.Contains(t, resultText(result), "Inpainted successfully. Image url: "+resultUrl)
+
+		requests := server.Requests("POST", createTaskPath)
+		require.Len(t, requests, 1)
+
+		var payload struct {
+			RecipeId   shared.RecipeId        `json:"recipeId"`
+			RecipeType shared.RecipeType      `json:"recipeType"`
+			Params     map[string]interface{} `json:"params"`
+		}
+		require.NoError(t, json.Unmarshal(requests[0].Body, &payload))
+		assert.Equal(t, shared.RecipeIdImageGeneratorSimple, payload.RecipeId)
+		assert.Equal(t, shared.RecipeTypeInpaint, payload.RecipeType)
+		assert.Equal(t, imageUrl, payload.Params["image"])
+		assert.Equal(t, maskUrl, payload.Params["mask"])
+		assert.Equal(t, "A red hat", payload.Params["prompt"])
+	})
+
+	t.Run("Invalid arguments", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			args     map[string]any
+			expected string
+		}{
+			{"Missing mask", map[string]any{"image_url": imageUrl}, "mask_url parameter is required"},
+			{"Missing image", map[string]any{"mask_url": maskUrl}, "image_url parameter is required"},
+			{"Mask outside the CDN", map[string]any{"image_url": imageUrl, "mask_url": "https://example.com/mask.png"}, "mask_url must be GAIA's image url: starts with `https://cdn.protogaia.com/`"},
+			{"Image outside the CDN", map[string]any{"image_url": "https://example.com/input.png", "mask_url": maskUrl}, "image_url must be GAIA's image url: starts with `https://cdn.protogaia.com/`"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				server := testutil.NewTestServer()
+				defer server.Close()
+
+				tool := NewInpaintTool(newTestApi(server))
+				result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), tt.args))
+
+				require.NoError(t, err)
+				assert.True(t, result.IsError)
+				assert.Equal(t, tt.expected, resultText(result))
+				assert.Empty(t, server.Requests("POST", createTaskPath))
+			})
+		}
+	})
+
+	t.Run("Validates against the configured CDN", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		server.AddResponse("POST", createTaskPath, generatedResponse(addMockImage(server, "/result.png")))
+
+		tool := NewInpaintTool(newTestApi(server), ToolDefaults{CdnUrl: "https://cdn.example.com"})
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"image_url": "https://cdn.example.com/input.png",
+			"mask_url":  "https://cdn.example.com/mask.png",
+		}))
+		require.NoError(t, err)
+		assert.False(t, result.IsError, resultText(result))
+
+		result, err = tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"image_url": imageUrl,
+			"mask_url":  maskUrl,
+		}))
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Equal(t, "image_url must be GAIA's image url: starts with `https://cdn.example.com/`", resultText(result))
+	})
+}
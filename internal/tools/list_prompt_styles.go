@@ -0,0 +1,60 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"gaia-mcp-go/pkg/shared"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ListPromptStylesTool lists the prompt styles accepted by generate_image with
+// a short description of each, so a style can be chosen before generating.
+type ListPromptStylesTool struct {
+	tool     mcp.Tool
+	defaults ToolDefaults
+	previews map[shared.PromptStyle]string
+}
+
+// NewListPromptStylesTool creates the list_prompt_styles tool.
+// previews optionally maps prompt styles to example thumbnail URLs; styles
+// without a preview are listed with their description only.
+// Optional ToolDefaults override which prompt style is reported as the default.
+func NewListPromptStylesTool(previews map[shared.PromptStyle]string, overrides ...ToolDefaults) *ListPromptStylesTool {
+	return &ListPromptStylesTool{
+		defaults: resolveToolDefaults(overrides...),
+		previews: previews,
+		tool: mcp.NewTool(
+			"list_prompt_styles",
+			mcp.WithDescription("List the prompt styles available for generate_image with a description of each and, when available, an example image url. Use it to pick a promptStyle before calling generate_image."),
+		),
+	}
+}
+
+func (t *ListPromptStylesTool) ToolName() string {
+	return "list_prompt_styles"
+}
+
+func (t *ListPromptStylesTool) MCPTool() mcp.Tool {
+	return t.tool
+}
+
+func (t *ListPromptStylesTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	styleMap := shared.GetPromptStyleMap()
+
+	var sb strings.Builder
+	sb.WriteString("Available prompt styles. Pass the name as promptStyle to generate_image:\n")
+	for _, style := range styleMap.Styles() {
+		sb.WriteString(fmt.Sprintf("- %s: %s", style, styleMap.Description(style)))
+		if style == t.defaults.PromptStyle {
+			sb.WriteString(" (default)")
+		}
+		if preview := t.previews[style]; preview != "" {
+			sb.WriteString(fmt.Sprintf(". Example: %s", preview))
+		}
+		sb.WriteString("\n")
+	}
+
+	return mcp.NewToolResultText(strings.TrimRight(sb.String(), "\n")), nil
+}
@@ -0,0 +1,45 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"gaia-mcp-go/pkg/shared"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListPromptStyles(t *testing.T) {
+	t.Run("Every style is listed with a description", func(t *testing.T) {
+		tool := NewListPromptStylesTool(nil)
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), nil))
+
+		require.NoError(t, err)
+		assert.False(t, result.IsError)
+
+		text := resultText(result)
+		styleMap := shared.GetPromptStyleMap()
+		for _, style := range styleMap.Styles() {
+			assert.Contains(t, text, fmt.Sprintf("- %s: %s", style, styleMap.Description(style)))
+		}
+		assert.Len(t, strings.Split(text, "\n"), len(styleMap.Styles())+1)
+		assert.Contains(t, text, "- base: No added styling; the prompt is used as written (default)")
+		assert.NotContains(t, text, "Example:")
+	})
+
+	t.Run("Previews and the configured default are included", func(t *testing.T) {
+		tool := NewListPromptStylesTool(
+			map[shared.PromptStyle]string{shared.PromptStyleAnime: "https://cdn.protogaia.com/styles/anime.png"},
+			ToolDefaults{PromptStyle: shared.PromptStyleAnime},
+		)
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), nil))
+
+		require.NoError(t, err)
+		text := resultText(result)
+		assert.Contains(t, text, "(default). Example: https://cdn.protogaia.com/styles/anime.png")
+		assert.NotContains(t, text, "as written (default)")
+		assert.Equal(t, 1, strings.Count(text, "Example:"))
+	})
+}
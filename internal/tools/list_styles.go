@@ -0,0 +1,90 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"gaia-mcp-go/internal/api"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// maxStylesPerPage is the largest page list_styles requests
+const maxStylesPerPage = 100
+
+// ListStylesTool lists the user's GAIA styles, so an existing style can be
+// picked by its ID
+type ListStylesTool struct {
+	api  api.GaiaApi
+	tool mcp.Tool
+}
+
+func NewListStylesTool(gaiaApi api.GaiaApi) *ListStylesTool {
+	return &ListStylesTool{
+		api: gaiaApi,
+		tool: mcp.NewTool(
+			"list_styles",
+			mcp.WithDescription("List the user's GAIA styles with their IDs, names and thumbnails. Use it to find the styleId of an existing style instead of guessing it."),
+			mcp.WithNumber(
+				"page",
+				mcp.Description("The page to list, starting at 1"),
+				mcp.DefaultNumber(1),
+				mcp.Min(1),
+			),
+			mcp.WithNumber(
+				"perPage",
+				mcp.Description(fmt.Sprintf("The number of styles per page (1-%d)", maxStylesPerPage)),
+				mcp.DefaultNumber(api.DefaultStylesPerPage),
+				mcp.Min(1),
+				mcp.Max(maxStylesPerPage),
+			),
+		),
+	}
+}
+
+func (t *ListStylesTool) ToolName() string {
+	return "list_styles"
+}
+
+func (t *ListStylesTool) MCPTool() mcp.Tool {
+	return t.tool
+}
+
+func (t *ListStylesTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := req.GetArguments()
+
+	page, err := intArg(args, "page", 1, 1, 0)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	perPage, err := intArg(args, "perPage", api.DefaultStylesPerPage, 1, maxStylesPerPage)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	styles, err := t.api.ListStyles(ctx, page, perPage)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to list styles: %v", err)), nil
+	}
+
+	if len(styles.Data) == 0 {
+		if styles.Total > 0 {
+			return mcp.NewToolResultText(fmt.Sprintf("Page %d has no styles: there are %d styles on %d pages.", page, styles.Total, styles.TotalPages)), nil
+		}
+		return mcp.NewToolResultText("No styles found. Create one with create_style or upload_and_create_style."), nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Styles (page %d of %d, %d in total):", page, max(styles.TotalPages, 1), styles.Total))
+	for _, style := range styles.Data {
+		sb.WriteString(fmt.Sprintf("\n- %s (Style ID: %s)", style.Name, style.Id))
+		if style.ThumbnailUrl != "" {
+			sb.WriteString(fmt.Sprintf("\n  Thumbnail: %s", style.ThumbnailUrl))
+		}
+	}
+	if page < styles.TotalPages {
+		sb.WriteString(fmt.Sprintf("\nMore styles are on page %d.", page+1))
+	}
+
+	return mcp.NewToolResultText(sb.String()), nil
+}
@@ -0,0 +1,155 @@
+package tools
+
+import (
+	"context"
+	"gaia-mcp-go/internal/api"
+	"gaia-mcp-go/internal/testutil"
+	"gaia-mcp-go/pkg/httpclient"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const listStylesPath = "/api/sd-styles"
+
+func TestListStyles(t *testing.T) {
+	t.Run("Pages through the styles", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		server.AddResponseSequence("GET", listStylesPath,
+			testutil.MockResponse{
+				StatusCode: http.StatusOK,
+				Body: httpclient.PaginatedResponse[api.SdStyle]{
+					Data: []api.SdStyle{
+						{Id: "style-1", Name: "Watercolor", ThumbnailUrl: "https://cdn.protogaia.com/styles/watercolor.png"},
+						{Id: "style-2", Name: "Ink"},
+					},
+					Page: 1, PerPage: 2, Total: 3, TotalPages: 2,
+				},
+			},
+			testutil.MockResponse{
+				StatusCode: http.StatusOK,
+				Body: httpclient.PaginatedResponse[api.SdStyle]{
+					Data: []api.SdStyle{
+						{Id: "style-3", Name: "Pastel", ThumbnailUrl: "https://cdn.protogaia.com/styles/pastel.png"},
+					},
+					Page: 2, PerPage: 2, Total: 3, TotalPages: 2,
+				},
+			},
+		)
+
+		tool := NewListStylesTool(newTestApi(server))
+
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"perPage": 2.0,
+		}))
+		require.NoError(t, err)
+		assert.False(t, result.IsError, resultText(result))
+
+		text := resultText(result)
+		assert.Contains(t, text, "Styles (page 1 of 2, 3 in total):")
+		assert.Contains(t, text, "- Watercolor (Style ID: style-1)\n  Thumbnail: https://cdn.protogaia.com/styles/watercolor.png")
+		assert.Contains(t, text, "- Ink (Style ID: style-2)")
+		assert.Contains(t, text, "More styles are on page 2.")
+		assert.NotContains(t, text, "style-3")
+
+		result, err = tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"page":    2.0,
+			"perPage": 2.0,
+		}))
+		require.NoError(t, err)
+		assert.False(t, result.IsError, resultText(result))
+
+		text = resultText(result)
+		assert.Contains(t, text, "Styles (page 2 of 2, 3 in total):")
+		assert.Contains(t, text, "- Pastel (Style ID: style-3)\n  Thumbnail: https://cdn.protogaia.com/styles/pastel.png")
+		assert.NotContains(t, text, "More styles")
+
+		requests := server.Requests("GET", listStylesPath)
+		require.Len(t, requests, 2)
+		assert.Equal(t, "1", requests[0].Query.Get("page"))
+		assert.Equal(t, "2", requests[1].Query.Get("page"))
+		assert.Equal(t, "2", requests[1].Query.Get("per_page"))
+	})
+
+	t.Run("No styles", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		server.AddResponse("GET", listStylesPath, testutil.MockResponse{
+			StatusCode: http.StatusOK,
+			Body:       httpclient.PaginatedResponse[api.SdStyle]{Page: 1},
+		})
+
+		tool := NewListStylesTool(newTestApi(server))
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{}))
+
+		require.NoError(t, err)
+		assert.False(t, result.IsError)
+		assert.Equal(t, "No styles found. Create one with create_style or upload_and_create_style.", resultText(result))
+	})
+
+	t.Run("Page past the last page", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		server.AddResponse("GET", listStylesPath, testutil.MockResponse{
+			StatusCode: http.StatusOK,
+			Body:       httpclient.PaginatedResponse[api.SdStyle]{Page: 5, Total: 3, TotalPages: 2},
+		})
+
+		tool := NewListStylesTool(newTestApi(server))
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{"page": 5.0}))
+
+		require.NoError(t, err)
+		assert.False(t, result.IsError)
+		assert.Equal(t, "Page 5 has no styles: there are 3 styles on 2 pages.", resultText(result))
+	})
+
+	t.Run("API error", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		server.AddResponse("GET", listStylesPath, testutil.MockResponse{
+			StatusCode: http.StatusBadRequest,
+			Body:       map[string]string{"message": "bad request"},
+		})
+
+		tool := NewListStylesTool(newTestApi(server))
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{}))
+
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, resultText(result), "Failed to list styles")
+	})
+
+	t.Run("Invalid arguments", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			args     map[string]any
+			expected string
+		}{
+			{"Page not an integer", map[string]any{"page": 1.5}, "page must be an integer"},
+			{"Page below 1", map[string]any{"page": 0.0}, "page must be at least 1"},
+			{"perPage above max", map[string]any{"perPage": 500.0}, "perPage must be between 1 and 100"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				server := testutil.NewTestServer()
+				defer server.Close()
+
+				tool := NewListStylesTool(newTestApi(server))
+				result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), tt.args))
+
+				require.NoError(t, err)
+				assert.True(t, result.IsError)
+				assert.Equal(t, tt.expected, resultText(result))
+				assert.Empty(t, server.Requests("GET", listStylesPath))
+			})
+		}
+	})
+}
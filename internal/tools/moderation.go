@@ -0,0 +1,135 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"gaia-mcp-go/internal/api"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// PromptFilter blocks prompts containing banned terms before they are
+// submitted, so prompts the API would reject do not cost credits.
+// A nil *PromptFilter allows every prompt.
+type PromptFilter struct {
+	terms    []string
+	patterns []*regexp.Regexp
+}
+
+// NewPromptFilter creates a filter for the given terms. Terms match whole
+// words, ignoring case; blank terms are skipped.
+func NewPromptFilter(terms []string) *PromptFilter {
+	filter := &PromptFilter{}
+	for _, term := range terms {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+
+		// Match on word boundaries so "ass" does not block "class"
+		filter.terms = append(filter.terms, term)
+		filter.patterns = append(filter.patterns, regexp.MustCompile(`(?i)\b`+regexp.QuoteMeta(term)+`\b`))
+	}
+	return filter
+}
+
+// LoadPromptFilter reads banned terms from a file with one term per line.
+// Blank lines and lines starting with # are ignored.
+func LoadPromptFilter(path string) (*PromptFilter, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open banned terms file: %w", err)
+	}
+	defer file.Close()
+
+	var terms []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		terms = append(terms, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read banned terms file: %w", err)
+	}
+
+	return NewPromptFilter(terms), nil
+}
+
+// Len returns the number of banned terms
+func (f *PromptFilter) Len() int {
+	if f == nil {
+		return 0
+	}
+	return len(f.terms)
+}
+
+// Check returns the first banned term found in the prompt, and whether one was found
+func (f *PromptFilter) Check(prompt string) (string, bool) {
+	if f == nil {
+		return "", false
+	}
+
+	for i, pattern := range f.patterns {
+		if pattern.MatchString(prompt) {
+			return f.terms[i], true
+		}
+	}
+	return "", false
+}
+
+// ErrBannedPrompt is matched (via errors.Is) by every BannedPromptError
+var ErrBannedPrompt = errors.New("prompt contains a banned term")
+
+// BannedPromptError is returned when a prompt contains a banned term, before
+// the task is submitted
+type BannedPromptError struct {
+	// Term is the banned term found in the prompt
+	Term string
+}
+
+// Error implements the error interface for BannedPromptError
+func (e *BannedPromptError) Error() string {
+	return fmt.Sprintf(
+		"The prompt was blocked by the content policy because it contains the banned term %q. It was not submitted and no credits were used. Please rephrase the prompt.",
+		e.Term,
+	)
+}
+
+// Is reports whether the target is ErrBannedPrompt
+func (e *BannedPromptError) Is(target error) bool {
+	return target == ErrBannedPrompt
+}
+
+// moderatedApi wraps a GaiaApi and checks the prompt of every generation
+// against the filter before it is submitted, so every tool that sends a prompt
+// is covered, whether the prompt comes from its arguments, a recipe or a pipeline.
+type moderatedApi struct {
+	api.GaiaApi
+	filter *PromptFilter
+}
+
+// withPromptFilter wraps the API client so generations with a banned prompt
+// are rejected. A nil or empty filter returns the client unchanged.
+func withPromptFilter(apiClient api.GaiaApi, filter *PromptFilter) api.GaiaApi {
+	if filter.Len() == 0 {
+		return apiClient
+	}
+	return &moderatedApi{GaiaApi: apiClient, filter: filter}
+}
+
+// GenerateImages submits the task only when its prompt contains no banned term.
+// It returns a *BannedPromptError otherwise.
+func (m *moderatedApi) GenerateImages(ctx context.Context, req api.GenerateImagesRequest) (api.ImageGeneratedResponse, error) {
+	if prompt, ok := req.Params["prompt"].(string); ok {
+		if term, banned := m.filter.Check(prompt); banned {
+			return api.ImageGeneratedResponse{}, &BannedPromptError{Term: term}
+		}
+	}
+	return m.GaiaApi.GenerateImages(ctx, req)
+}
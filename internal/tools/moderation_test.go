@@ -0,0 +1,151 @@
+package tools
+
+import (
+	"context"
+	"gaia-mcp-go/internal/interfaces"
+	"gaia-mcp-go/internal/testutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromptFilter_Check(t *testing.T) {
+	filter := NewPromptFilter([]string{"gore", "  ", "Blood Bath"})
+	assert.Equal(t, 2, filter.Len())
+
+	tests := []struct {
+		prompt   string
+		expected string
+		banned   bool
+	}{
+		{prompt: "A castle covered in GORE", expected: "gore", banned: true},
+		{prompt: "a blood bath at dawn", expected: "Blood Bath", banned: true},
+		{prompt: "A gorgeous sunset", banned: false},
+		{prompt: "A bath full of bubbles", banned: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.prompt, func(t *testing.T) {
+			term, banned := filter.Check(tt.prompt)
+			assert.Equal(t, tt.banned, banned)
+			assert.Equal(t, tt.expected, term)
+		})
+	}
+
+	t.Run("Nil filter allows everything", func(t *testing.T) {
+		var filter *PromptFilter
+		_, banned := filter.Check("gore")
+		assert.False(t, banned)
+		assert.Zero(t, filter.Len())
+	})
+}
+
+func TestLoadPromptFilter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "banned.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# Banned terms\ngore\n\n  weapon  \n"), 0o644))
+
+	filter, err := LoadPromptFilter(path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, filter.Len())
+
+	term, banned := filter.Check("A knight with a weapon")
+	assert.True(t, banned)
+	assert.Equal(t, "weapon", term)
+
+	_, banned = filter.Check("# Banned terms")
+	assert.False(t, banned)
+
+	t.Run("Missing file", func(t *testing.T) {
+		_, err := LoadPromptFilter(filepath.Join(t.TempDir(), "missing.txt"))
+		assert.ErrorContains(t, err, "failed to open banned terms file")
+	})
+}
+
+// newModeratedTool returns the named tool built by NewGaiaTools with a filter banning "gore"
+func newModeratedTool(t *testing.T, server *testutil.TestServer, name string) interfaces.GaiaTool {
+	t.Helper()
+
+	deps := ToolDeps{PromptFilter: NewPromptFilter([]string{"gore"})}
+	for _, tool := range NewGaiaTools(newTestApi(server), ToolDefaults{}, deps) {
+		if tool.ToolName() == name {
+			return tool
+		}
+	}
+	t.Fatalf("tool %s not found", name)
+	return nil
+}
+
+func TestGaiaTools_PromptFilter(t *testing.T) {
+	blocked := []struct {
+		name string
+		tool string
+		args map[string]any
+	}{
+		{
+			name: "Generate image",
+			tool: "generate_image",
+			args: map[string]any{"prompt": "A battlefield full of gore"},
+		},
+		{
+			name: "Turbo",
+			tool: "generate_image_turbo",
+			args: map[string]any{"prompt": "A battlefield full of GORE"},
+		},
+		{
+			name: "Inpaint",
+			tool: "inpaint",
+			args: map[string]any{
+				"image_url": "https://cdn.protogaia.com/input.png",
+				"mask_url":  "https://cdn.protogaia.com/mask.png",
+				"prompt":    "gore on the walls",
+			},
+		},
+	}
+
+	for _, tt := range blocked {
+		t.Run(tt.name+" blocks a banned prompt before submitting", func(t *testing.T) {
+			server := testutil.NewTestServer()
+			defer server.Close()
+
+			tool := newModeratedTool(t, server, tt.tool)
+			result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), tt.args))
+
+			require.NoError(t, err)
+			assert.True(t, result.IsError)
+			assert.Contains(t, resultText(result), `contains the banned term "gore"`)
+			assert.Contains(t, resultText(result), "no credits were used")
+			assert.Empty(t, server.Requests("POST", createTaskPath))
+		})
+	}
+
+	t.Run("Clean prompt proceeds", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		server.AddResponse("POST", createTaskPath, generatedResponse(addMockImage(server, "/result.png")))
+
+		tool := newModeratedTool(t, server, "generate_image_turbo")
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"prompt": "A gorgeous mountain lake",
+		}))
+
+		require.NoError(t, err)
+		assert.False(t, result.IsError, resultText(result))
+		assert.Len(t, server.Requests("POST", createTaskPath), 1)
+	})
+
+	t.Run("Replayed recipes are checked too", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		tool := newModeratedTool(t, server, "replay_recipe").(*ReplayRecipeTool)
+		result, err := tool.generator.generate(context.Background(), map[string]interface{}{"prompt": "gore"}, generateOptions{})
+
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Empty(t, server.Requests("POST", createTaskPath))
+	})
+}
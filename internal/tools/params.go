@@ -0,0 +1,243 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"gaia-mcp-go/internal/api"
+	"gaia-mcp-go/pkg/shared"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// withFolderIdParam declares the optional folderId argument shared by generation tools
+func withFolderIdParam() mcp.ToolOption {
+	return mcp.WithString(
+		"folderId",
+		mcp.Description("Optional GAIA folder ID to store the generated images in. Defaults to the user's default folder."),
+	)
+}
+
+// applyFolderId forwards the optional folderId argument into the recipe params.
+// It returns an error when folderId is provided but is not a non-empty string.
+func applyFolderId(args map[string]interface{}, params map[string]interface{}) error {
+	folderId, err := folderIdArg(args)
+	if err != nil || folderId == nil {
+		return err
+	}
+
+	params["folderId"] = *folderId
+	return nil
+}
+
+// folderIdArg reads the optional folderId argument, returning nil when it is absent.
+// It returns an error when folderId is provided but is not a non-empty string.
+func folderIdArg(args map[string]interface{}) (*string, error) {
+	rawFolderId, exists := args["folderId"]
+	if !exists || rawFolderId == nil {
+		return nil, nil
+	}
+
+	folderId, ok := rawFolderId.(string)
+	if !ok || strings.TrimSpace(folderId) == "" {
+		return nil, fmt.Errorf("folderId must be a non-empty string")
+	}
+
+	return &folderId, nil
+}
+
+// withExtraParamsParam declares the optional extraParams argument that passes
+// additional recipe params through to the API
+func withExtraParamsParam() mcp.ToolOption {
+	return mcp.WithObject(
+		"extraParams",
+		mcp.Description("Optional advanced recipe params merged into the request, for backend features not exposed by this tool yet. Params set by the tool's own arguments take precedence."),
+	)
+}
+
+// applyExtraParams merges the optional extraParams object into the recipe params.
+// Keys already present in params are kept, so the tool's own arguments always
+// take precedence. Call it before any step that may remove a known param.
+// It returns an error when extraParams is provided but is not a JSON object.
+func applyExtraParams(args map[string]interface{}, params map[string]interface{}) error {
+	rawExtras, exists := args["extraParams"]
+	if !exists || rawExtras == nil {
+		return nil
+	}
+
+	extras, ok := rawExtras.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("extraParams must be a JSON object")
+	}
+
+	for key, value := range extras {
+		if _, known := params[key]; !known {
+			params[key] = value
+		}
+	}
+	return nil
+}
+
+// paramsFromStruct converts a typed recipe params struct into the map sent to the API.
+//
+// Keys come from the fields' json tags, so optional fields should be pointers
+// tagged omitempty to be left out when unset. Values are round-tripped through
+// JSON, so numbers come back as float64 exactly as the API will receive them.
+// It returns nil if v does not encode to a JSON object.
+func paramsFromStruct(v any) map[string]interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+
+	var params map[string]interface{}
+	if err := json.Unmarshal(data, &params); err != nil {
+		return nil
+	}
+	return params
+}
+
+// withIncludeInputParam declares the optional include_input argument shared by image editing tools
+func withIncludeInputParam() mcp.ToolOption {
+	return mcp.WithBoolean(
+		"include_input",
+		mcp.DefaultBool(false),
+		mcp.Description("When true, the original input image is also returned alongside the result for before/after comparison."),
+	)
+}
+
+// appendInputImage adds the original input image to the result when include_input is true.
+// With the url_only return mode only its url is added.
+// A failure to fetch the input image is reported as text so the generated result is not lost.
+func appendInputImage(ctx context.Context, defaults ToolDefaults, req mcp.CallToolRequest, result *mcp.CallToolResult, inputUrl string) {
+	if !req.GetBool("include_input", false) || inputUrl == "" {
+		return
+	}
+
+	if mode, _ := returnModeArg(req); mode == ReturnModeUrlOnly {
+		result.Content = append(result.Content, mcp.NewTextContent(fmt.Sprintf("Input image url: %s", inputUrl)))
+		return
+	}
+
+	base64Data, mimeType, err := defaults.processImage(ctx, inputUrl)
+	if err != nil {
+		result.Content = append(result.Content, mcp.NewTextContent(fmt.Sprintf("Failed to include input image: %v", err)))
+		return
+	}
+
+	result.Content = append(result.Content,
+		mcp.NewTextContent(fmt.Sprintf("Input image url: %s", inputUrl)),
+		defaults.imageContent(inputUrl, base64Data, mimeType),
+	)
+}
+
+// persistImage uploads a generated image into the user's library under resource
+// and returns a message with the persisted file url. Generated images are served
+// from the GAIA CDN like library files, but only as task outputs that may expire,
+// so the url alone cannot tell whether an image is in the library and the upload
+// decides: when the library keeps the image at the url it already had, it is
+// reported as already persisted.
+// Failures are reported in the message so the generated result is not lost.
+func persistImage(ctx context.Context, gaiaApi api.GaiaApi, imageUrl string, resource shared.FileAssociatedResource) string {
+	files, err := gaiaApi.UploadImages(ctx, []string{imageUrl}, resource)
+	if err != nil {
+		return fmt.Sprintf("Failed to persist image: %v", err)
+	}
+	if len(files) == 0 || files[0].Url == nil {
+		return "Failed to persist image: upload returned no file url"
+	}
+
+	if *files[0].Url == imageUrl {
+		return fmt.Sprintf("Image is already persisted. Persisted file url: %s", imageUrl)
+	}
+	return fmt.Sprintf("Persisted file url: %s", *files[0].Url)
+}
+
+// stringArrayArg reads a required array-of-strings argument from the tool call
+func stringArrayArg(args map[string]interface{}, name string) ([]string, error) {
+	// First, get the raw value and check if it exists
+	rawValues, exists := args[name]
+	if !exists {
+		return nil, fmt.Errorf("%s parameter is required", name)
+	}
+
+	// Convert from []interface{} to []string safely
+	valuesInterface, ok := rawValues.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s must be an array", name)
+	}
+
+	values := make([]string, len(valuesInterface))
+	for i, value := range valuesInterface {
+		str, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s[%d] must be a string", name, i)
+		}
+		values[i] = str
+	}
+
+	return values, nil
+}
+
+// cdnImageUrlArg reads a required image url argument from the tool call.
+// It returns an error when the url is missing or not hosted under cdnUrl, the GAIA CDN.
+func cdnImageUrlArg(args map[string]interface{}, name string, cdnUrl string) (string, error) {
+	imageUrl, ok := args[name].(string)
+	if !ok || strings.TrimSpace(imageUrl) == "" {
+		return "", fmt.Errorf("%s parameter is required", name)
+	}
+
+	if !strings.HasPrefix(imageUrl, cdnUrl+"/") {
+		return "", fmt.Errorf("%s must be GAIA's image url: starts with `%s/`", name, cdnUrl)
+	}
+
+	return imageUrl, nil
+}
+
+// floatArrayArg reads an optional array-of-numbers argument from the tool call.
+// It returns nil when the argument is not set.
+func floatArrayArg(args map[string]interface{}, name string) ([]float64, error) {
+	rawValues, exists := args[name]
+	if !exists || rawValues == nil {
+		return nil, nil
+	}
+
+	valuesInterface, ok := rawValues.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s must be an array", name)
+	}
+
+	values := make([]float64, len(valuesInterface))
+	for i, value := range valuesInterface {
+		number, ok := value.(float64)
+		if !ok {
+			return nil, fmt.Errorf("%s[%d] must be a number", name, i)
+		}
+		values[i] = number
+	}
+
+	return values, nil
+}
+
+// intArg reads an optional integer argument from the tool call, returning
+// defaultValue when it is not set. A max of zero or less means no maximum.
+// It returns an error when the value is not an integer within the bounds.
+func intArg(args map[string]interface{}, name string, defaultValue, min, max int) (int, error) {
+	rawValue, exists := args[name]
+	if !exists || rawValue == nil {
+		return defaultValue, nil
+	}
+
+	number, ok := toFloat(rawValue)
+	if !ok || number != float64(int64(number)) {
+		return 0, fmt.Errorf("%s must be an integer", name)
+	}
+	if number < float64(min) {
+		return 0, fmt.Errorf("%s must be at least %d", name, min)
+	}
+	if max > 0 && number > float64(max) {
+		return 0, fmt.Errorf("%s must be between %d and %d", name, min, max)
+	}
+	return int(number), nil
+}
@@ -0,0 +1,192 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"gaia-mcp-go/internal/api"
+	"gaia-mcp-go/internal/interfaces"
+	"gaia-mcp-go/internal/testutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerationTools_FolderId(t *testing.T) {
+	tests := []struct {
+		name    string
+		newTool func(api.GaiaApi) interfaces.GaiaTool
+		args    map[string]any
+	}{
+		{
+			name:    "generate_image",
+			newTool: func(a api.GaiaApi) interfaces.GaiaTool { return NewGenerateImageTool(a) },
+			args:    map[string]any{"prompt": "A red fox"},
+		},
+		{
+			name:    "remix",
+			newTool: func(a api.GaiaApi) interfaces.GaiaTool { return NewRemixTool(a) },
+			args:    map[string]any{"inputImage": "https://cdn.protogaia.com/input.png"},
+		},
+		{
+			name:    "upscaler",
+			newTool: func(a api.GaiaApi) interfaces.GaiaTool { return NewUpscalerTool(a) },
+			args:    map[string]any{"image_url": "https://cdn.protogaia.com/input.png"},
+		},
+		{
+			name:    "face_enhancer",
+			newTool: func(a api.GaiaApi) interfaces.GaiaTool { return NewFaceEnhancerTool(a) },
+			args:    map[string]any{"image_url": "https://cdn.protogaia.com/input.png"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" forwards folderId", func(t *testing.T) {
+			server := testutil.NewTestServer()
+			defer server.Close()
+
+			server.AddResponse("POST", createTaskPath, generatedResponse(addMockImage(server, "/result.png")))
+
+			args := map[string]any{"folderId": "folder-123"}
+			for key, value := range tt.args {
+				args[key] = value
+			}
+
+			tool := tt.newTool(newTestApi(server))
+			result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), args))
+
+			require.NoError(t, err)
+			assert.False(t, result.IsError, resultText(result))
+
+			requests := server.Requests("POST", createTaskPath)
+			require.Len(t, requests, 1)
+			assert.Equal(t, "folder-123", decodeTaskParams(t, requests[0])["folderId"])
+		})
+
+		t.Run(tt.name+" rejects empty folderId", func(t *testing.T) {
+			server := testutil.NewTestServer()
+			defer server.Close()
+
+			args := map[string]any{"folderId": "  "}
+			for key, value := range tt.args {
+				args[key] = value
+			}
+
+			tool := tt.newTool(newTestApi(server))
+			result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), args))
+
+			require.NoError(t, err)
+			assert.True(t, result.IsError)
+			assert.Contains(t, resultText(result), "folderId")
+			assert.Empty(t, server.Requests("POST", createTaskPath))
+		})
+	}
+}
+
+func TestApplyFolderId(t *testing.T) {
+	t.Run("Missing folderId leaves params untouched", func(t *testing.T) {
+		params := map[string]interface{}{}
+		assert.NoError(t, applyFolderId(map[string]interface{}{}, params))
+		assert.NotContains(t, params, "folderId")
+	})
+
+	t.Run("Non-string folderId is rejected", func(t *testing.T) {
+		params := map[string]interface{}{}
+		assert.Error(t, applyFolderId(map[string]interface{}{"folderId": 42}, params))
+	})
+}
+
+func TestParamsFromStruct(t *testing.T) {
+	folderId := "folder-123"
+
+	tests := []struct {
+		name     string
+		params   upscalerParams
+		expected map[string]interface{}
+	}{
+		{
+			name:   "Empty optional fields are omitted",
+			params: upscalerParams{Image: "https://cdn.protogaia.com/input.png", UpscaleMode: "4x-Ultrasharp.pt", UpscaleRatio: 2},
+			expected: map[string]interface{}{
+				"image":         "https://cdn.protogaia.com/input.png",
+				"upscale_mode":  "4x-Ultrasharp.pt",
+				"upscale_ratio": 2.0,
+			},
+		},
+		{
+			name:   "Set optional fields are included",
+			params: upscalerParams{Image: "https://cdn.protogaia.com/input.png", UpscaleMode: "4x-Ultrasharp.pt", UpscaleRatio: 4, FolderId: &folderId},
+			expected: map[string]interface{}{
+				"image":         "https://cdn.protogaia.com/input.png",
+				"upscale_mode":  "4x-Ultrasharp.pt",
+				"upscale_ratio": 4.0,
+				"folderId":      "folder-123",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, paramsFromStruct(tt.params))
+		})
+	}
+
+	t.Run("Non-object values return nil", func(t *testing.T) {
+		assert.Nil(t, paramsFromStruct("not an object"))
+		assert.Nil(t, paramsFromStruct(make(chan int)))
+	})
+}
+
+func TestEditingTools_IncludeInput(t *testing.T) {
+	tests := []struct {
+		name     string
+		newTool  func(api.GaiaApi) interfaces.GaiaTool
+		inputArg string
+	}{
+		{
+			name:     "remix",
+			newTool:  func(a api.GaiaApi) interfaces.GaiaTool { return NewRemixTool(a) },
+			inputArg: "inputImage",
+		},
+		{
+			name:     "upscaler",
+			newTool:  func(a api.GaiaApi) interfaces.GaiaTool { return NewUpscalerTool(a) },
+			inputArg: "image_url",
+		},
+		{
+			name:     "face_enhancer",
+			newTool:  func(a api.GaiaApi) interfaces.GaiaTool { return NewFaceEnhancerTool(a) },
+			inputArg: "image_url",
+		},
+	}
+
+	for _, tt := range tests {
+		for _, includeInput := range []bool{true, false} {
+			t.Run(fmt.Sprintf("%s include_input=%t", tt.name, includeInput), func(t *testing.T) {
+				server := testutil.NewTestServer()
+				defer server.Close()
+
+				inputUrl := addMockImage(server, "/input.png")
+				server.AddResponse("POST", createTaskPath, generatedResponse(addMockImage(server, "/result.png")))
+
+				tool := tt.newTool(newTestApi(server))
+				result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+					tt.inputArg:     inputUrl,
+					"include_input": includeInput,
+				}))
+
+				require.NoError(t, err)
+				assert.False(t, result.IsError, resultText(result))
+
+				if includeInput {
+					assert.Equal(t, 2, countImages(result))
+					assert.Contains(t, resultText(result), inputUrl)
+					assert.Len(t, server.Requests("GET", "/input.png"), 1)
+				} else {
+					assert.Equal(t, 1, countImages(result))
+					assert.Empty(t, server.Requests("GET", "/input.png"))
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gaia-mcp-go/internal/api"
+	"gaia-mcp-go/pkg/shared"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// pipelineStep describes how a pipeline step maps onto a Gaia recipe
+type pipelineStep struct {
+	// recipeId is the recipe executed for this step
+	recipeId shared.RecipeId
+	// inputParam is the recipe param that receives the previous step's image.
+	// Empty for steps that don't take an input image.
+	inputParam string
+	// defaults are recipe params applied before the user-provided params
+	defaults map[string]interface{}
+}
+
+// pipelineSteps lists the supported step types keyed by their tool name
+var pipelineSteps = map[string]pipelineStep{
+	"generate_image": {
+		recipeId: shared.RecipeIdImageGeneratorSimple,
+		defaults: map[string]interface{}{
+			"aspectRatio":    string(shared.AspectRatio1_1),
+			"promptStyle":    string(shared.PromptStyleBase),
+			"numberOfImages": 1,
+		},
+	},
+	"remix": {
+		recipeId:   shared.RecipeIdRemix,
+		inputParam: "inputImage",
+		defaults: map[string]interface{}{
+			"variationControl": "subtle",
+			"numberOfImages":   1,
+		},
+	},
+	"upscaler": {
+		recipeId:   shared.RecipeIdUpscaler,
+		inputParam: "image",
+		defaults: map[string]interface{}{
+			"upscale_mode":  "4x-Ultrasharp.pt",
+			"upscale_ratio": 2,
+		},
+	},
+	"face_enhancer": {
+		recipeId:   shared.RecipeIdFaceEnhancer,
+		inputParam: "imageUrl",
+	},
+}
+
+// PipelineTool runs several image recipes in sequence, feeding each step's
+// output image into the next step (e.g. generate → upscale → face-enhance)
+type PipelineTool struct {
+	api      api.GaiaApi
+	tool     mcp.Tool
+	defaults ToolDefaults
+}
+
+// NewPipelineTool creates the pipeline tool.
+// Optional ToolDefaults select the server mode used to process the final image.
+func NewPipelineTool(api api.GaiaApi, overrides ...ToolDefaults) *PipelineTool {
+	return &PipelineTool{
+		api:      api,
+		defaults: resolveToolDefaults(overrides...),
+		tool: mcp.NewTool(
+			"pipeline",
+			mcp.WithDescription("Run multiple image steps in order (e.g. generate_image → upscaler → face_enhancer). Each step receives the image produced by the previous step."),
+			mcp.WithArray(
+				"steps",
+				mcp.Required(),
+				mcp.Description("Ordered list of steps. Each step has a `type` (one of 'generate_image', 'remix', 'upscaler', 'face_enhancer') and optional recipe `params`. The input image of every step after the first is filled from the previous step's result."),
+				mcp.Items(map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"type": map[string]any{
+							"type": "string",
+							"enum": []string{"generate_image", "remix", "upscaler", "face_enhancer"},
+						},
+						"params": map[string]any{
+							"type": "object",
+						},
+					},
+					"required": []string{"type"},
+				}),
+			),
+			withReturnModeParam(),
+		),
+	}
+}
+
+func (t *PipelineTool) ToolName() string {
+	return "pipeline"
+}
+
+func (t *PipelineTool) MCPTool() mcp.Tool {
+	return t.tool
+}
+
+func (t *PipelineTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := req.GetArguments()
+
+	rawSteps, ok := args["steps"].([]interface{})
+	if !ok || len(rawSteps) == 0 {
+		return mcp.NewToolResultError("steps must be a non-empty array"), nil
+	}
+
+	returnMode, err := returnModeArg(req)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	var imageUrl string
+	for i, rawStep := range rawSteps {
+		stepArgs, ok := rawStep.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("steps[%d] must be an object", i)), nil
+		}
+
+		stepType, _ := stepArgs["type"].(string)
+		step, ok := pipelineSteps[stepType]
+		if !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("steps[%d] has unsupported type %q", i, stepType)), nil
+		}
+
+		// Build the recipe params: defaults -> user params -> previous output
+		params := make(map[string]interface{}, len(step.defaults))
+		for key, value := range step.defaults {
+			params[key] = value
+		}
+		if userParams, ok := stepArgs["params"].(map[string]interface{}); ok {
+			for key, value := range userParams {
+				params[key] = value
+			}
+		}
+		if step.inputParam != "" && imageUrl != "" {
+			params[step.inputParam] = imageUrl
+		}
+
+		url, err := t.runStep(ctx, step.recipeId, params)
+		if err != nil {
+			result := mcp.NewToolResultError(fmt.Sprintf("Pipeline failed at step %d (%s): %v", i+1, stepType, err))
+			var noImages *NoImagesError
+			if errors.As(err, &noImages) {
+				result.Meta = noImagesResult(noImages.TaskId).Meta
+			}
+			return result, nil
+		}
+		imageUrl = url
+	}
+
+	msg := fmt.Sprintf("Pipeline completed %d steps successfully. Image url: %s", len(rawSteps), imageUrl)
+
+	result, err := t.defaults.generatedImageResult(ctx, returnMode, msg, imageUrl)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to process image: %v", err)), nil
+	}
+
+	return result, nil
+}
+
+// runStep executes a single recipe and returns the first generated image URL
+func (t *PipelineTool) runStep(ctx context.Context, recipeId shared.RecipeId, params map[string]interface{}) (string, error) {
+	res, err := t.api.GenerateImages(ctx, api.GenerateImagesRequest{
+		RecipeId: recipeId,
+		Params:   params,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if res.Error != nil {
+		return "", fmt.Errorf("%s", *res.Error)
+	}
+
+	if !res.Success {
+		return "", fmt.Errorf("generation was not successful")
+	}
+
+	if len(res.Images) == 0 {
+		return "", &NoImagesError{TaskId: res.TaskId}
+	}
+
+	return res.Images[0], nil
+}
@@ -0,0 +1,92 @@
+package tools
+
+import (
+	"context"
+	"gaia-mcp-go/internal/testutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPipelineTool_Handler(t *testing.T) {
+	steps := []interface{}{
+		map[string]interface{}{
+			"type":   "generate_image",
+			"params": map[string]interface{}{"prompt": "A lighthouse at dusk"},
+		},
+		map[string]interface{}{
+			"type":   "upscaler",
+			"params": map[string]interface{}{"upscale_ratio": 4},
+		},
+	}
+
+	t.Run("Two-step pipeline succeeds", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		generatedUrl := addMockImage(server, "/generated.png")
+		upscaledUrl := addMockImage(server, "/upscaled.png")
+		server.AddResponseSequence("POST", createTaskPath,
+			generatedResponse(generatedUrl),
+			generatedResponse(upscaledUrl),
+		)
+
+		tool := NewPipelineTool(newTestApi(server))
+		result, err := tool.Handler(context.Background(), newCallToolRequest("pipeline", map[string]any{"steps": steps}))
+
+		require.NoError(t, err)
+		assert.False(t, result.IsError)
+		assert.Equal(t, 1, countImages(result))
+		assert.Contains(t, resultText(result), upscaledUrl)
+
+		// The second step must receive the first step's output image
+		requests := server.Requests("POST", createTaskPath)
+		require.Len(t, requests, 2)
+		params := decodeTaskParams(t, requests[1])
+		assert.Equal(t, generatedUrl, params["image"])
+		assert.Equal(t, float64(4), params["upscale_ratio"])
+	})
+
+	t.Run("Failure in the middle of the pipeline", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		generatedUrl := addMockImage(server, "/generated.png")
+		upscaleError := "Upscaler is unavailable"
+		server.AddResponseSequence("POST", createTaskPath,
+			generatedResponse(generatedUrl),
+			testutil.MockResponse{
+				StatusCode: http.StatusOK,
+				Body: map[string]interface{}{
+					"success": false,
+					"images":  []string{},
+					"error":   upscaleError,
+				},
+			},
+		)
+
+		tool := NewPipelineTool(newTestApi(server))
+		result, err := tool.Handler(context.Background(), newCallToolRequest("pipeline", map[string]any{"steps": steps}))
+
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, resultText(result), "step 2 (upscaler)")
+		assert.Contains(t, resultText(result), upscaleError)
+	})
+
+	t.Run("Unsupported step type", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		tool := NewPipelineTool(newTestApi(server))
+		result, err := tool.Handler(context.Background(), newCallToolRequest("pipeline", map[string]any{
+			"steps": []interface{}{map[string]interface{}{"type": "teleport"}},
+		}))
+
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Empty(t, server.Requests("POST", createTaskPath))
+	})
+}
@@ -0,0 +1,58 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"gaia-mcp-go/pkg/shared"
+)
+
+// RecipeFormatVersion is the version written into exported recipes
+const RecipeFormatVersion = 1
+
+// Recipe is a portable snapshot of the resolved parameters of a generation,
+// so the exact same settings can be saved and replayed later
+type Recipe struct {
+	// Version is the recipe format version
+	Version int `json:"version"`
+	// Tool is the name of the tool that produced the recipe
+	Tool string `json:"tool"`
+	// RecipeId is the Gaia recipe that was executed
+	RecipeId shared.RecipeId `json:"recipeId"`
+	// Params are the resolved recipe params sent to the API
+	Params map[string]interface{} `json:"params"`
+}
+
+// ExportRecipe serializes a tool's resolved params into a JSON recipe
+func ExportRecipe(tool string, recipeId shared.RecipeId, params map[string]interface{}) (string, error) {
+	data, err := json.MarshalIndent(Recipe{
+		Version:  RecipeFormatVersion,
+		Tool:     tool,
+		RecipeId: recipeId,
+		Params:   params,
+	}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to export recipe: %w", err)
+	}
+
+	return string(data), nil
+}
+
+// LoadRecipe parses and validates a JSON recipe produced by ExportRecipe
+func LoadRecipe(data string) (Recipe, error) {
+	var recipe Recipe
+	if err := json.Unmarshal([]byte(data), &recipe); err != nil {
+		return Recipe{}, fmt.Errorf("invalid recipe JSON: %w", err)
+	}
+
+	if recipe.Version != RecipeFormatVersion {
+		return Recipe{}, fmt.Errorf("unsupported recipe version %d", recipe.Version)
+	}
+	if recipe.RecipeId == "" {
+		return Recipe{}, fmt.Errorf("recipe is missing recipeId")
+	}
+	if len(recipe.Params) == 0 {
+		return Recipe{}, fmt.Errorf("recipe is missing params")
+	}
+
+	return recipe, nil
+}
@@ -0,0 +1,92 @@
+package tools
+
+import (
+	"context"
+	"gaia-mcp-go/internal/testutil"
+	"gaia-mcp-go/pkg/shared"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecipe_RoundTrip(t *testing.T) {
+	server := testutil.NewTestServer()
+	defer server.Close()
+
+	server.AddResponse("POST", createTaskPath, generatedResponse(addMockImage(server, "/result.png")))
+	testApi := newTestApi(server)
+
+	// Generate once and export the recipe
+	generate := NewGenerateImageTool(testApi)
+	result, err := generate.Handler(context.Background(), newCallToolRequest(generate.ToolName(), map[string]any{
+		"prompt":        "A misty forest",
+		"aspectRatio":   "16:9",
+		"promptStyle":   "cinematic",
+		"folderId":      "folder-123",
+		"export_recipe": true,
+	}))
+	require.NoError(t, err)
+	require.False(t, result.IsError, resultText(result))
+
+	text := resultText(result)
+	require.Contains(t, text, "Recipe:\n")
+	recipeJSON := text[strings.Index(text, "Recipe:\n")+len("Recipe:\n"):]
+
+	recipe, err := LoadRecipe(recipeJSON)
+	require.NoError(t, err)
+	assert.Equal(t, "generate_image", recipe.Tool)
+	assert.Equal(t, shared.RecipeIdImageGeneratorSimple, recipe.RecipeId)
+
+	// Replay the recipe
+	replay := NewReplayRecipeTool(testApi)
+	result, err = replay.Handler(context.Background(), newCallToolRequest(replay.ToolName(), map[string]any{
+		"recipe": recipeJSON,
+	}))
+	require.NoError(t, err)
+	require.False(t, result.IsError, resultText(result))
+	assert.Equal(t, 1, countImages(result))
+
+	requests := server.Requests("POST", createTaskPath)
+	require.Len(t, requests, 2)
+	assert.Equal(t, decodeTaskParams(t, requests[0]), decodeTaskParams(t, requests[1]))
+}
+
+func TestLoadRecipe(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		expectedErr string
+	}{
+		{name: "Invalid JSON", data: "{", expectedErr: "invalid recipe JSON"},
+		{name: "Unsupported version", data: `{"version": 99, "recipeId": "x", "params": {"a": 1}}`, expectedErr: "unsupported recipe version"},
+		{name: "Missing recipe ID", data: `{"version": 1, "params": {"a": 1}}`, expectedErr: "missing recipeId"},
+		{name: "Missing params", data: `{"version": 1, "recipeId": "x"}`, expectedErr: "missing params"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadRecipe(tt.data)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedErr)
+		})
+	}
+}
+
+func TestReplayRecipe_UnsupportedTool(t *testing.T) {
+	server := testutil.NewTestServer()
+	defer server.Close()
+
+	recipe, err := ExportRecipe("upscaler", shared.RecipeIdUpscaler, map[string]interface{}{"image": "x"})
+	require.NoError(t, err)
+
+	replay := NewReplayRecipeTool(newTestApi(server))
+	result, err := replay.Handler(context.Background(), newCallToolRequest(replay.ToolName(), map[string]any{
+		"recipe": recipe,
+	}))
+
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Empty(t, server.Requests("POST", createTaskPath))
+}
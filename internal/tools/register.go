@@ -0,0 +1,124 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"gaia-mcp-go/internal/api"
+	"gaia-mcp-go/internal/interfaces"
+	"gaia-mcp-go/pkg/imageutil"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// DefaultMaxConcurrentTools is the default number of tool calls that may run at
+// once. Zero leaves tool calls unlimited, as quick calls such as get_style would
+// otherwise queue behind long generations.
+const DefaultMaxConcurrentTools = 0
+
+// ToolDeps holds the runtime dependencies NewGaiaTools shares with every tool.
+// Nil fields fall back to their defaults.
+type ToolDeps struct {
+	// Processor downloads and encodes the images the tools return. Nil creates
+	// one from ToolDefaults.ImageProcessorConfig, so every tool shares its
+	// connections and cache.
+	Processor *imageutil.Processor
+	// Notifier streams each image of a multi-image generation to the client as
+	// soon as it is ready. Nil uses MCP progress notifications in HTTP mode and
+	// buffers the whole result in stdio mode.
+	Notifier PartialResultNotifier
+	// PromptFilter blocks prompts containing banned terms before they are
+	// submitted. Nil disables the check.
+	PromptFilter *PromptFilter
+}
+
+// merge returns the dependencies with the non-nil fields of override applied
+func (d ToolDeps) merge(override ToolDeps) ToolDeps {
+	if override.Processor != nil {
+		d.Processor = override.Processor
+	}
+	if override.Notifier != nil {
+		d.Notifier = override.Notifier
+	}
+	if override.PromptFilter != nil {
+		d.PromptFilter = override.PromptFilter
+	}
+	return d
+}
+
+// NewGaiaTools creates every tool the MCP server exposes. All transports build
+// their tools here, so they serve the same set. Every generation they submit
+// is checked against deps.PromptFilter first, and they share one image
+// Processor.
+func NewGaiaTools(apiClient api.GaiaApi, defaults ToolDefaults, deps ToolDeps) []interfaces.GaiaTool {
+	apiClient = withPromptFilter(apiClient, deps.PromptFilter)
+	if deps.Processor == nil {
+		deps.Processor = imageutil.NewProcessor(defaults.ImageProcessorConfig())
+	}
+	defaults.deps = deps
+
+	return []interfaces.GaiaTool{
+		NewGenerateImageTool(apiClient, defaults),
+		NewGenerateImageTurboTool(apiClient, defaults),
+		NewFaceEnhancerTool(apiClient, defaults),
+		NewRemixTool(apiClient, defaults),
+		NewUpscalerTool(apiClient, defaults),
+		NewInpaintTool(apiClient, defaults),
+		NewUploadImageTool(apiClient),
+		NewDownloadImageTool(defaults),
+		NewCreateStyleTool(apiClient),
+		NewUploadAndCreateStyleTool(apiClient),
+		NewGetStyleTool(apiClient),
+		NewListStylesTool(apiClient),
+		NewPipelineTool(apiClient, defaults),
+		NewReplayRecipeTool(apiClient, defaults),
+		NewListPromptStylesTool(nil, defaults),
+		NewReimagineTool(apiClient, defaults),
+		NewDescribeTool(apiClient, defaults),
+		NewCancelTaskTool(apiClient),
+	}
+}
+
+// RegisterTools adds the tools to the MCP server. Each call is validated
+// against the tool schema and its API calls are tagged with trace IDs.
+//
+// At most maxConcurrent tool calls run at once across all the tools, so an
+// aggressive client cannot overwhelm memory or the Gaia API with generations.
+// Further calls wait for a running call to finish rather than being rejected.
+// Zero or less means no limit.
+func RegisterTools(s *server.MCPServer, gaiaTools []interfaces.GaiaTool, maxConcurrent int) {
+	limit := WithConcurrencyLimit(maxConcurrent)
+	for _, tool := range gaiaTools {
+		s.AddTool(tool.MCPTool(), limit(WithRequestTrace(WithValidation(tool))))
+	}
+}
+
+// WithConcurrencyLimit returns a middleware that lets at most maxConcurrent of
+// the handlers it wraps run at once; the slots are shared by every handler.
+// Calls over the limit wait for a free slot until their context is done, in
+// which case they return an error result without running.
+// Zero or less returns a middleware that leaves handlers unchanged.
+func WithConcurrencyLimit(maxConcurrent int) func(server.ToolHandlerFunc) server.ToolHandlerFunc {
+	if maxConcurrent <= 0 {
+		return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+			return next
+		}
+	}
+
+	slots := make(chan struct{}, maxConcurrent)
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return mcp.NewToolResultError(fmt.Sprintf(
+					"The tool call was cancelled while waiting for one of the %d running tool calls to finish: %v",
+					maxConcurrent, ctx.Err(),
+				)), nil
+			}
+			defer func() { <-slots }()
+
+			return next(ctx, req)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"gaia-mcp-go/internal/interfaces"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slowTool is a tool whose calls take a while and record how many ran at once
+type slowTool struct {
+	name     string
+	delay    time.Duration
+	inFlight *atomic.Int32
+	peak     *atomic.Int32
+}
+
+func (t *slowTool) ToolName() string {
+	return t.name
+}
+
+func (t *slowTool) MCPTool() mcp.Tool {
+	return mcp.NewTool(t.name)
+}
+
+func (t *slowTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	current := t.inFlight.Add(1)
+	defer t.inFlight.Add(-1)
+	for {
+		peak := t.peak.Load()
+		if current <= peak || t.peak.CompareAndSwap(peak, current) {
+			break
+		}
+	}
+
+	time.Sleep(t.delay)
+	return mcp.NewToolResultText("done"), nil
+}
+
+func TestRegisterTools_ConcurrencyLimit(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxConcurrent int
+		expectedPeak  int32
+	}{
+		{name: "Limit is shared across tools", maxConcurrent: 2, expectedPeak: 2},
+		{name: "Calls run one at a time", maxConcurrent: 1, expectedPeak: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var inFlight, peak atomic.Int32
+			gaiaTools := []interfaces.GaiaTool{
+				&slowTool{name: "slow_a", delay: 50 * time.Millisecond, inFlight: &inFlight, peak: &peak},
+				&slowTool{name: "slow_b", delay: 50 * time.Millisecond, inFlight: &inFlight, peak: &peak},
+			}
+
+			s := server.NewMCPServer("test", "1.0.0", server.WithToolCapabilities(false))
+			RegisterTools(s, gaiaTools, tt.maxConcurrent)
+
+			// Call both tools simultaneously, 8 calls in total
+			var wg sync.WaitGroup
+			for i := 0; i < 8; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					message := fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"tools/call","params":{"name":"%s"}}`, i, gaiaTools[i%2].ToolName())
+					response := s.HandleMessage(context.Background(), json.RawMessage(message))
+
+					result, ok := response.(mcp.JSONRPCResponse)
+					require.True(t, ok, "unexpected response: %#v", response)
+					callResult, ok := result.Result.(mcp.CallToolResult)
+					require.True(t, ok)
+					assert.False(t, callResult.IsError, resultText(&callResult))
+				}(i)
+			}
+			wg.Wait()
+
+			assert.Equal(t, tt.expectedPeak, peak.Load())
+		})
+	}
+
+	t.Run("No limit", func(t *testing.T) {
+		var inFlight, peak atomic.Int32
+		tool := &slowTool{name: "slow", delay: 100 * time.Millisecond, inFlight: &inFlight, peak: &peak}
+
+		s := server.NewMCPServer("test", "1.0.0", server.WithToolCapabilities(false))
+		RegisterTools(s, []interfaces.GaiaTool{tool}, 0)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				s.HandleMessage(context.Background(), json.RawMessage(fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"tools/call","params":{"name":"slow"}}`, i)))
+			}(i)
+		}
+		wg.Wait()
+
+		assert.Greater(t, peak.Load(), int32(2))
+	})
+}
+
+func TestWithConcurrencyLimit_ContextCancelledWhileQueued(t *testing.T) {
+	limit := WithConcurrencyLimit(1)
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	blocking := limit(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		close(started)
+		<-release
+		return mcp.NewToolResultText("done"), nil
+	})
+
+	var ran atomic.Bool
+	queued := limit(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ran.Store(true)
+		return mcp.NewToolResultText("done"), nil
+	})
+
+	go blocking(context.Background(), mcp.CallToolRequest{})
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	result, err := queued(ctx, mcp.CallToolRequest{})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, resultText(result), "cancelled while waiting")
+	assert.False(t, ran.Load())
+
+	// Once the slot is free, queued calls run again
+	close(release)
+	result, err = queued(context.Background(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.True(t, ran.Load())
+}
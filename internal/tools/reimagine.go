@@ -0,0 +1,98 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"gaia-mcp-go/internal/api"
+	"gaia-mcp-go/pkg/shared"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ReimagineTool describes an existing image and generates a new image from
+// that description, returning both the derived prompt and the new image
+type ReimagineTool struct {
+	api       api.GaiaApi
+	generator *GenerateImageTool
+	tool      mcp.Tool
+}
+
+// NewReimagineTool creates the reimagine tool.
+// Optional ToolDefaults override the default aspect ratio and prompt style.
+func NewReimagineTool(api api.GaiaApi, overrides ...ToolDefaults) *ReimagineTool {
+	generator := NewGenerateImageTool(api, overrides...)
+
+	return &ReimagineTool{
+		api:       api,
+		generator: generator,
+		tool: mcp.NewTool(
+			"reimagine",
+			mcp.WithDescription("Describe an existing image with Protogaia and generate a new image from that description. Returns the derived prompt and the new image."),
+			mcp.WithString(
+				"image_url",
+				mcp.Required(),
+				mcp.Description("The URL of the image to reimagine"),
+			),
+			mcp.WithString(
+				"aspectRatio",
+				mcp.Description("Aspect ratio of the new image. One of the following: '1:1', '3:2', '2:3', '16:9', '9:16'"),
+				mcp.DefaultString(string(generator.defaults.AspectRatio)),
+				mcp.Enum(shared.GetAspectRatioMap().ToStrings()...),
+			),
+			mcp.WithString(
+				"promptStyle",
+				mcp.Description("Style to apply to the new image. Choose from predefined styles. It's not style id and style name."),
+				mcp.DefaultString(string(generator.defaults.PromptStyle)),
+				mcp.Enum(shared.GetPromptStyleMap().ToStrings()...),
+			),
+			withFolderIdParam(),
+			withReturnModeParam(),
+		),
+	}
+}
+
+func (t *ReimagineTool) ToolName() string {
+	return "reimagine"
+}
+
+func (t *ReimagineTool) MCPTool() mcp.Tool {
+	return t.tool
+}
+
+func (t *ReimagineTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := req.GetArguments()
+
+	imageUrl, err := req.RequireString("image_url")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	returnMode, err := returnModeArg(req)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	prompt, err := describeImage(ctx, t.api, imageUrl)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to describe image: %v", err)), nil
+	}
+
+	params := map[string]interface{}{
+		"prompt":         prompt,
+		"aspectRatio":    req.GetString("aspectRatio", string(t.generator.defaults.AspectRatio)),
+		"promptStyle":    req.GetString("promptStyle", string(t.generator.defaults.PromptStyle)),
+		"numberOfImages": 1, // Always generate 1 image
+	}
+	if err := applyFolderId(args, params); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	result, err := t.generator.generate(ctx, params, generateOptions{returnMode: returnMode})
+	if err != nil || result.IsError {
+		return result, err
+	}
+
+	// Lead with the derived prompt so it can be tweaked and reused
+	result.Content = append([]mcp.Content{mcp.NewTextContent("Derived prompt: " + prompt)}, result.Content...)
+	return result, nil
+}
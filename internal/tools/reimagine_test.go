@@ -0,0 +1,142 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"gaia-mcp-go/internal/api"
+	"gaia-mcp-go/internal/testutil"
+	"gaia-mcp-go/pkg/shared"
+	"net/http"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// describeTaskId is the ID of the describe task in describedResponse
+const describeTaskId = "describe-task"
+
+// describedResponse returns a successful describe submission and serves the
+// finished describe task, whose prompt is the description
+func describedResponse(server *testutil.TestServer, description string) testutil.MockResponse {
+	server.AddResponse("GET", "/api/recipe/agi-tasks/"+describeTaskId, testutil.MockResponse{
+		StatusCode: http.StatusOK,
+		Body: api.RecipeTask{
+			Id:     describeTaskId,
+			Status: shared.RecipeTaskStatusCompleted,
+			Prompt: description,
+		},
+	})
+
+	return testutil.MockResponse{
+		StatusCode: http.StatusOK,
+		Body:       api.ImageGeneratedResponse{Success: true, TaskId: describeTaskId},
+	}
+}
+
+func TestReimagine(t *testing.T) {
+	const inputUrl = "https://cdn.protogaia.com/input.png"
+
+	t.Run("Describes the image then generates from the description", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		server.AddResponseSequence("POST", createTaskPath,
+			describedResponse(server, "  A lighthouse on a rocky cliff at sunset  "),
+			generatedResponse(addMockImage(server, "/result.png")),
+		)
+
+		tool := NewReimagineTool(newTestApi(server))
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"image_url":   inputUrl,
+			"aspectRatio": "16:9",
+			"folderId":    "folder-123",
+		}))
+
+		require.NoError(t, err)
+		assert.False(t, result.IsError, resultText(result))
+		assert.Equal(t, 1, countImages(result))
+
+		// The derived prompt is the first block
+		first, ok := mcp.AsTextContent(result.Content[0])
+		require.True(t, ok)
+		assert.Equal(t, "Derived prompt: A lighthouse on a rocky cliff at sunset", first.Text)
+		assert.Contains(t, resultText(result), "Image generated successfully")
+
+		requests := server.Requests("POST", createTaskPath)
+		require.Len(t, requests, 2)
+
+		var describe struct {
+			RecipeId   shared.RecipeId        `json:"recipeId"`
+			RecipeType shared.RecipeType      `json:"recipeType"`
+			Params     map[string]interface{} `json:"params"`
+		}
+		require.NoError(t, json.Unmarshal(requests[0].Body, &describe))
+		assert.Equal(t, shared.RecipeIdImageGeneratorSimple, describe.RecipeId)
+		assert.Equal(t, shared.RecipeTypeDescribe, describe.RecipeType)
+		assert.Equal(t, inputUrl, describe.Params["image"])
+
+		params := decodeTaskParams(t, requests[1])
+		assert.Equal(t, "A lighthouse on a rocky cliff at sunset", params["prompt"])
+		assert.Equal(t, "16:9", params["aspectRatio"])
+		assert.Equal(t, string(shared.PromptStyleBase), params["promptStyle"])
+		assert.Equal(t, "folder-123", params["folderId"])
+	})
+
+	t.Run("Empty description stops before generating", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		server.AddResponse("POST", createTaskPath, describedResponse(server, " "))
+
+		tool := NewReimagineTool(newTestApi(server))
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"image_url": inputUrl,
+		}))
+
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Equal(t, "Failed to describe image: no description was generated", resultText(result))
+		assert.Len(t, server.Requests("POST", createTaskPath), 1)
+	})
+
+	t.Run("Describe errors are reported", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		message := "Image could not be read"
+		server.AddResponse("POST", createTaskPath, testutil.MockResponse{
+			StatusCode: http.StatusOK,
+			Body:       api.ImageGeneratedResponse{Success: false, Error: &message},
+		})
+
+		tool := NewReimagineTool(newTestApi(server))
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"image_url": inputUrl,
+		}))
+
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Equal(t, "Failed to describe image: Image could not be read", resultText(result))
+	})
+
+	t.Run("Generation errors are returned as is", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		server.AddResponseSequence("POST", createTaskPath,
+			describedResponse(server, "A red fox"),
+			generatedResponse(),
+		)
+
+		tool := NewReimagineTool(newTestApi(server))
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"image_url": inputUrl,
+		}))
+
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Equal(t, "No images were generated. Please try again.", resultText(result))
+	})
+}
@@ -4,22 +4,28 @@ import (
 	"context"
 	"fmt"
 	"gaia-mcp-go/internal/api"
-	"gaia-mcp-go/pkg/imageutil"
 	"gaia-mcp-go/pkg/shared"
 
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
 type RemixTool struct {
-	api  api.GaiaApi
-	tool mcp.Tool
+	api      api.GaiaApi
+	tool     mcp.Tool
+	defaults ToolDefaults
 }
 
+// NewRemixTool creates the remix tool.
+// Optional ToolDefaults override the default variation control.
 func NewRemixTool(
 	api api.GaiaApi,
+	overrides ...ToolDefaults,
 ) *RemixTool {
+	defaults := resolveToolDefaults(overrides...)
+
 	return &RemixTool{
-		api: api,
+		api:      api,
+		defaults: defaults,
 		tool: mcp.NewTool(
 			"remix",
 			mcp.WithDescription("Create new variations of an existing image"),
@@ -31,9 +37,12 @@ func NewRemixTool(
 			mcp.WithString(
 				"variationControl",
 				mcp.Description("The variation control of the remix. One of the following: 'subtle', 'medium', 'strong'"),
-				mcp.DefaultString("subtle"),
+				mcp.DefaultString(defaults.VariationControl),
 				mcp.Enum("subtle", "medium", "strong"),
 			),
+			withFolderIdParam(),
+			withReturnModeParam(),
+			withIncludeInputParam(),
 		),
 	}
 }
@@ -49,16 +58,26 @@ func (t *RemixTool) MCPTool() mcp.Tool {
 func (t *RemixTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := req.GetArguments()
 
+	returnMode, err := returnModeArg(req)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
 	inputImage := args["inputImage"]
-	variationControl := args["variationControl"]
+	variationControl := req.GetString("variationControl", t.defaults.VariationControl)
+
+	params := map[string]interface{}{
+		"inputImage":       inputImage,
+		"variationControl": variationControl,
+		"numberOfImages":   1, // Always generate 1 image
+	}
+	if err := applyFolderId(args, params); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 
 	res, err := t.api.GenerateImages(ctx, api.GenerateImagesRequest{
 		RecipeId: shared.RecipeIdRemix,
-		Params: map[string]interface{}{
-			"inputImage":       inputImage,
-			"variationControl": variationControl,
-			"numberOfImages":   1, // Always generate 1 image
-		},
+		Params:   params,
 	})
 
 	if err != nil {
@@ -74,15 +93,16 @@ func (t *RemixTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.
 	}
 
 	if len(res.Images) == 0 {
-		return mcp.NewToolResultError("No images were generated. Please try again."), nil
+		return noImagesResult(res.TaskId), nil
 	}
 
-	base64Data, mimeType, err := imageutil.ProcessImageQuickForMCP(ctx, res.Images[0])
+	msg := fmt.Sprintf("Remix generated successfully. Image url: %s", res.Images[0])
+
+	result, err := t.defaults.generatedImageResult(ctx, returnMode, msg, res.Images[0], res.Fallbacks(0)...)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to process image: %v", err)), nil
 	}
+	appendInputImage(ctx, t.defaults, req, result, req.GetString("inputImage", ""))
 
-	msg := fmt.Sprintf("Remix generated successfully. Image url: %s", res.Images[0])
-
-	return mcp.NewToolResultImage(msg, base64Data, mimeType), nil
+	return result, nil
 }
@@ -0,0 +1,63 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"gaia-mcp-go/internal/api"
+	"gaia-mcp-go/pkg/shared"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ReplayRecipeTool re-runs generate_image with the exact params of an exported recipe
+type ReplayRecipeTool struct {
+	generator *GenerateImageTool
+	tool      mcp.Tool
+}
+
+func NewReplayRecipeTool(api api.GaiaApi, overrides ...ToolDefaults) *ReplayRecipeTool {
+	return &ReplayRecipeTool{
+		generator: NewGenerateImageTool(api, overrides...),
+		tool: mcp.NewTool(
+			"replay_recipe",
+			mcp.WithDescription("Generate an image again using a JSON recipe exported by generate_image with export_recipe=true"),
+			mcp.WithString(
+				"recipe",
+				mcp.Required(),
+				mcp.Description("The JSON recipe exported by generate_image"),
+			),
+			withReturnModeParam(),
+		),
+	}
+}
+
+func (t *ReplayRecipeTool) ToolName() string {
+	return "replay_recipe"
+}
+
+func (t *ReplayRecipeTool) MCPTool() mcp.Tool {
+	return t.tool
+}
+
+func (t *ReplayRecipeTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	data, err := req.RequireString("recipe")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	recipe, err := LoadRecipe(data)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	returnMode, err := returnModeArg(req)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	if recipe.Tool != t.generator.ToolName() || recipe.RecipeId != shared.RecipeIdImageGeneratorSimple {
+		return mcp.NewToolResultError(fmt.Sprintf("recipe for %q cannot be replayed, only %s recipes are supported", recipe.Tool, t.generator.ToolName())), nil
+	}
+
+	return t.generator.generate(ctx, recipe.Params, generateOptions{returnMode: returnMode})
+}
@@ -0,0 +1,152 @@
+package tools
+
+import (
+	"fmt"
+	"gaia-mcp-go/internal/api"
+	"gaia-mcp-go/pkg/shared"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ToolResult is the outcome of a successful tool call. Keeping the outcome
+// separate from its presentation lets the messages be tested without the API.
+type ToolResult interface {
+	// Render builds the MCP result returned to the client
+	Render() *mcp.CallToolResult
+}
+
+// UploadImageResult is the outcome of an upload_image call
+type UploadImageResult struct {
+	// Files are the uploaded files, in the order of the requested urls
+	Files []api.UploadFile
+}
+
+// Render lists the urls of the uploaded files
+func (r UploadImageResult) Render() *mcp.CallToolResult {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Uploaded %d images successfully", len(r.Files)))
+	sb.WriteString("\nFile urls:\n")
+	for _, file := range r.Files {
+		if file.Url != nil {
+			sb.WriteString(fmt.Sprintf("- %s\n", *file.Url))
+		}
+	}
+
+	return mcp.NewToolResultText(sb.String())
+}
+
+// GenerateImageResult is the outcome of a generate_image call
+type GenerateImageResult struct {
+	// ImageUrls are the urls of the generated images
+	ImageUrls []string
+	// Images holds the content of each generated image, in the order of
+	// ImageUrls, or a warning with its url when it is too large to return inline
+	Images []mcp.Content
+	// Persisted reports the outcome of saving each image to the library, if requested
+	Persisted []string
+	// Zip holds the zip archive content of every generated image, if requested
+	Zip []mcp.Content
+	// Recipe is the exported JSON recipe, if requested
+	Recipe string
+	// QueueType is the processing queue the task ran on, when reported by the API
+	QueueType shared.QueueType
+	// Priority is the execution priority of the task, when reported by the API
+	Priority int
+}
+
+// Render returns the image urls and images, followed by the requested extras
+func (r GenerateImageResult) Render() *mcp.CallToolResult {
+	var message string
+	if len(r.ImageUrls) == 1 {
+		message = fmt.Sprintf("Image generated successfully. Image url: %s", r.ImageUrls[0])
+	} else {
+		message = fmt.Sprintf("Generated %d images successfully. Image urls:", len(r.ImageUrls))
+		for _, imageUrl := range r.ImageUrls {
+			message += fmt.Sprintf("\n- %s", imageUrl)
+		}
+	}
+	if queue := queueMessage(r.QueueType, r.Priority); queue != "" {
+		message += "\n" + queue
+	}
+
+	content := []mcp.Content{mcp.NewTextContent(message)}
+	content = append(content, r.Images...)
+	for _, persisted := range r.Persisted {
+		content = append(content, mcp.NewTextContent(persisted))
+	}
+	content = append(content, r.Zip...)
+	if r.Recipe != "" {
+		content = append(content, mcp.NewTextContent("Recipe:\n"+r.Recipe))
+	}
+
+	return &mcp.CallToolResult{Content: content}
+}
+
+// BatchPromptResult is the outcome of one prompt of a batch generate_image call
+type BatchPromptResult struct {
+	// Prompt is the prompt the images were generated from
+	Prompt string
+	// Result is the generate_image result of the prompt, which may be an error
+	Result *mcp.CallToolResult
+}
+
+// BatchGenerateImageResult is the outcome of a generate_image call with several prompts
+type BatchGenerateImageResult struct {
+	// Prompts holds the outcome of each prompt, in the order they were given
+	Prompts []BatchPromptResult
+}
+
+// Render returns a summary of every prompt, followed by the results of the
+// prompts that succeeded. It is an error only when every prompt failed.
+func (r BatchGenerateImageResult) Render() *mcp.CallToolResult {
+	var summary strings.Builder
+	succeeded := 0
+	for i, prompt := range r.Prompts {
+		if prompt.Result.IsError {
+			summary.WriteString(fmt.Sprintf("\n%d. %q failed: %s", i+1, prompt.Prompt, contentText(prompt.Result.Content)))
+			continue
+		}
+		succeeded++
+		summary.WriteString(fmt.Sprintf("\n%d. %q succeeded", i+1, prompt.Prompt))
+	}
+
+	message := fmt.Sprintf("Generated images for %d of %d prompts:", succeeded, len(r.Prompts)) + summary.String()
+	content := []mcp.Content{mcp.NewTextContent(message)}
+	for i, prompt := range r.Prompts {
+		if prompt.Result.IsError {
+			continue
+		}
+		content = append(content, mcp.NewTextContent(fmt.Sprintf("Prompt %d: %s", i+1, prompt.Prompt)))
+		content = append(content, prompt.Result.Content...)
+	}
+
+	return &mcp.CallToolResult{Content: content, IsError: succeeded == 0}
+}
+
+// contentText joins the text of the text content blocks
+func contentText(content []mcp.Content) string {
+	var texts []string
+	for _, c := range content {
+		if text, ok := mcp.AsTextContent(c); ok {
+			texts = append(texts, text.Text)
+		}
+	}
+	return strings.Join(texts, " ")
+}
+
+// queueMessage describes the queue a task was assigned to, such as
+// "Queued on the fast queue (priority 5)", or returns "" when neither the
+// queue nor the priority is known
+func queueMessage(queueType shared.QueueType, priority int) string {
+	switch {
+	case queueType != "" && priority != 0:
+		return fmt.Sprintf("Queued on the %s queue (priority %d)", queueType, priority)
+	case queueType != "":
+		return fmt.Sprintf("Queued on the %s queue", queueType)
+	case priority != 0:
+		return fmt.Sprintf("Queued with priority %d", priority)
+	default:
+		return ""
+	}
+}
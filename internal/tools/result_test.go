@@ -0,0 +1,144 @@
+package tools
+
+import (
+	"gaia-mcp-go/internal/api"
+	"gaia-mcp-go/pkg/shared"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// contentTexts returns the text of each content block, or "<image>" for images
+func contentTexts(t *testing.T, result *mcp.CallToolResult) []string {
+	t.Helper()
+
+	texts := make([]string, len(result.Content))
+	for i, content := range result.Content {
+		if text, ok := mcp.AsTextContent(content); ok {
+			texts[i] = text.Text
+			continue
+		}
+		_, ok := mcp.AsImageContent(content)
+		require.True(t, ok, "unexpected content %T", content)
+		texts[i] = "<image>"
+	}
+	return texts
+}
+
+func TestUploadImageResult_Render(t *testing.T) {
+	first := "https://cdn.protogaia.com/uploads/first.png"
+	second := "https://cdn.protogaia.com/uploads/second.png"
+
+	tests := []struct {
+		name     string
+		result   UploadImageResult
+		expected string
+	}{
+		{
+			name:     "Several files",
+			result:   UploadImageResult{Files: []api.UploadFile{{Id: "1", Url: &first}, {Id: "2", Url: &second}}},
+			expected: "Uploaded 2 images successfully\nFile urls:\n- " + first + "\n- " + second + "\n",
+		},
+		{
+			name:     "Files without url are counted but not listed",
+			result:   UploadImageResult{Files: []api.UploadFile{{Id: "1", Url: &first}, {Id: "2"}}},
+			expected: "Uploaded 2 images successfully\nFile urls:\n- " + first + "\n",
+		},
+		{
+			name:     "No files",
+			result:   UploadImageResult{},
+			expected: "Uploaded 0 images successfully\nFile urls:\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered := tt.result.Render()
+			assert.False(t, rendered.IsError)
+			assert.Equal(t, []string{tt.expected}, contentTexts(t, rendered))
+		})
+	}
+}
+
+func TestGenerateImageResult_Render(t *testing.T) {
+	const (
+		imageUrl       = "https://cdn.protogaia.com/generated/1.png"
+		secondImageUrl = "https://cdn.protogaia.com/generated/2.png"
+	)
+	image := mcp.NewImageContent("aGVsbG8=", "image/png")
+
+	tests := []struct {
+		name     string
+		result   GenerateImageResult
+		expected []string
+	}{
+		{
+			name:     "Image only",
+			result:   GenerateImageResult{ImageUrls: []string{imageUrl}, Images: []mcp.Content{image}},
+			expected: []string{"Image generated successfully. Image url: " + imageUrl, "<image>"},
+		},
+		{
+			name: "With every extra",
+			result: GenerateImageResult{
+				ImageUrls: []string{imageUrl},
+				Images:    []mcp.Content{image},
+				Persisted: []string{"Saved to your library: https://cdn.protogaia.com/library/1.png"},
+				Zip:       []mcp.Content{mcp.NewTextContent("Zip archive with 1 images attached")},
+				Recipe:    `{"tool":"generate_image"}`,
+			},
+			expected: []string{
+				"Image generated successfully. Image url: " + imageUrl,
+				"<image>",
+				"Saved to your library: https://cdn.protogaia.com/library/1.png",
+				"Zip archive with 1 images attached",
+				"Recipe:\n" + `{"tool":"generate_image"}`,
+			},
+		},
+		{
+			name: "Several images",
+			result: GenerateImageResult{
+				ImageUrls: []string{imageUrl, secondImageUrl},
+				Images:    []mcp.Content{image, image},
+				Persisted: []string{"Image is already persisted. Persisted file url: " + imageUrl, "Image is already persisted. Persisted file url: " + secondImageUrl},
+			},
+			expected: []string{
+				"Generated 2 images successfully. Image urls:\n- " + imageUrl + "\n- " + secondImageUrl,
+				"<image>",
+				"<image>",
+				"Image is already persisted. Persisted file url: " + imageUrl,
+				"Image is already persisted. Persisted file url: " + secondImageUrl,
+			},
+		},
+		{
+			name:     "Queue info",
+			result:   GenerateImageResult{ImageUrls: []string{imageUrl}, Images: []mcp.Content{image}, QueueType: shared.QueueTypeFast, Priority: 5},
+			expected: []string{"Image generated successfully. Image url: " + imageUrl + "\nQueued on the fast queue (priority 5)", "<image>"},
+		},
+		{
+			name:     "Queue without priority",
+			result:   GenerateImageResult{ImageUrls: []string{imageUrl}, Images: []mcp.Content{image}, QueueType: shared.QueueTypeDefault},
+			expected: []string{"Image generated successfully. Image url: " + imageUrl + "\nQueued on the default queue", "<image>"},
+		},
+		{
+			name: "Oversized image warning replaces the image",
+			result: GenerateImageResult{
+				ImageUrls: []string{imageUrl},
+				Images:    []mcp.Content{ToolDefaults{MaxImagePayloadBytes: 4}.imageContent(imageUrl, "aGVsbG8=", "image/png")},
+			},
+			expected: []string{
+				"Image generated successfully. Image url: " + imageUrl,
+				"Warning: the image is too large to return inline (8 bytes encoded, limit 4). Open it from its url instead: " + imageUrl,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered := tt.result.Render()
+			assert.False(t, rendered.IsError)
+			assert.Equal(t, tt.expected, contentTexts(t, rendered))
+		})
+	}
+}
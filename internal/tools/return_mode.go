@@ -0,0 +1,62 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ReturnMode selects how the tools return generated images
+type ReturnMode string
+
+const (
+	// ReturnModeImage downloads the image and returns it encoded for MCP along with its url
+	ReturnModeImage ReturnMode = "image"
+	// ReturnModeUrlOnly returns only the image url, for clients that fetch the image themselves
+	ReturnModeUrlOnly ReturnMode = "url_only"
+)
+
+// returnModes lists the valid return modes, the default first
+var returnModes = []string{string(ReturnModeImage), string(ReturnModeUrlOnly)}
+
+// withReturnModeParam declares the optional returnMode argument of tools that return images
+func withReturnModeParam() mcp.ToolOption {
+	return mcp.WithString(
+		"returnMode",
+		mcp.Enum(returnModes...),
+		mcp.DefaultString(string(ReturnModeImage)),
+		mcp.Description("How to return the image. 'image' (default) includes a downscaled copy of the image; 'url_only' returns only the image url, at full quality and without the download, for clients that can fetch it themselves."),
+	)
+}
+
+// returnModeArg reads the optional returnMode argument, defaulting to ReturnModeImage.
+// It returns an error when the value is not a known return mode.
+func returnModeArg(req mcp.CallToolRequest) (ReturnMode, error) {
+	mode := ReturnMode(strings.TrimSpace(req.GetString("returnMode", string(ReturnModeImage))))
+	switch mode {
+	case "":
+		return ReturnModeImage, nil
+	case ReturnModeImage, ReturnModeUrlOnly:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("returnMode must be one of: %s", strings.Join(returnModes, ", "))
+	}
+}
+
+// generatedImageResult builds a tool result with a message and the image at
+// imageUrl, trying the fallback urls when it cannot be downloaded. With
+// ReturnModeUrlOnly the image is not downloaded and the result holds only the
+// message, which is expected to carry the url.
+func (d ToolDefaults) generatedImageResult(ctx context.Context, mode ReturnMode, msg, imageUrl string, fallbacks ...string) (*mcp.CallToolResult, error) {
+	if mode == ReturnModeUrlOnly {
+		return mcp.NewToolResultText(msg), nil
+	}
+
+	base64Data, mimeType, err := d.processImage(ctx, imageUrl, fallbacks...)
+	if err != nil {
+		return nil, err
+	}
+	return d.imageResult(msg, imageUrl, base64Data, mimeType), nil
+}
@@ -0,0 +1,103 @@
+package tools
+
+import (
+	"context"
+	"gaia-mcp-go/internal/interfaces"
+	"gaia-mcp-go/internal/testutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReturnMode(t *testing.T) {
+	toolCases := []struct {
+		name    string
+		newTool func(server *testutil.TestServer) interfaces.GaiaTool
+		args    map[string]any
+	}{
+		{"generate_image", func(s *testutil.TestServer) interfaces.GaiaTool { return NewGenerateImageTool(newTestApi(s)) }, map[string]any{"prompt": "A red fox"}},
+		{"generate_image_turbo", func(s *testutil.TestServer) interfaces.GaiaTool { return NewGenerateImageTurboTool(newTestApi(s)) }, map[string]any{"prompt": "A red fox"}},
+		{"remix", func(s *testutil.TestServer) interfaces.GaiaTool { return NewRemixTool(newTestApi(s)) }, map[string]any{"inputImage": "https://cdn.protogaia.com/fox.png"}},
+		{"upscaler", func(s *testutil.TestServer) interfaces.GaiaTool { return NewUpscalerTool(newTestApi(s)) }, map[string]any{"image_url": "https://cdn.protogaia.com/fox.png"}},
+	}
+
+	modes := []struct {
+		mode           string
+		expectedImages int
+	}{
+		{"", 1},
+		{"image", 1},
+		{"url_only", 0},
+	}
+
+	for _, tc := range toolCases {
+		for _, m := range modes {
+			name := tc.name + "/" + m.mode
+			if m.mode == "" {
+				name = tc.name + "/default"
+			}
+			t.Run(name, func(t *testing.T) {
+				server := testutil.NewTestServer()
+				defer server.Close()
+
+				imageUrl := addMockImage(server, "/result.png")
+				server.AddResponse("POST", createTaskPath, generatedResponse(imageUrl))
+
+				args := map[string]any{}
+				for key, value := range tc.args {
+					args[key] = value
+				}
+				if m.mode != "" {
+					args["returnMode"] = m.mode
+				}
+
+				tool := tc.newTool(server)
+				result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), args))
+
+				require.NoError(t, err)
+				assert.False(t, result.IsError, resultText(result))
+				assert.Contains(t, resultText(result), "Image url: "+imageUrl)
+				assert.Equal(t, m.expectedImages, countImages(result))
+				assert.Len(t, server.Requests("GET", "/result.png"), m.expectedImages)
+			})
+		}
+	}
+
+	t.Run("url_only returns only the input url", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		server.AddResponse("POST", createTaskPath, generatedResponse(addMockImage(server, "/result.png")))
+		inputUrl := addMockImage(server, "/input.png")
+
+		tool := NewRemixTool(newTestApi(server))
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"inputImage":    inputUrl,
+			"include_input": true,
+			"returnMode":    "url_only",
+		}))
+
+		require.NoError(t, err)
+		assert.False(t, result.IsError, resultText(result))
+		assert.Contains(t, resultText(result), "Input image url: "+inputUrl)
+		assert.Equal(t, 0, countImages(result))
+		assert.Empty(t, server.Requests("GET", "/input.png"))
+	})
+
+	t.Run("Rejects an unknown mode", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		tool := NewGenerateImageTool(newTestApi(server))
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"prompt":     "A red fox",
+			"returnMode": "base64",
+		}))
+
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Equal(t, "returnMode must be one of: image, url_only", resultText(result))
+		assert.Empty(t, server.Requests("POST", createTaskPath))
+	})
+}
@@ -0,0 +1,69 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// PartialResult is a piece of a tool result that is sent before the tool call completes
+type PartialResult struct {
+	// ProgressToken is the token the client attached to the tool call, if any
+	ProgressToken mcp.ProgressToken
+	// Progress is the number of completed items, counting this one
+	Progress int
+	// Total is the number of items the tool call produces
+	Total int
+	// Message describes the completed item
+	Message string
+	// Content is the completed item, e.g. an image
+	Content []mcp.Content
+}
+
+// PartialResultNotifier delivers partial results to the client while a tool call is still running
+type PartialResultNotifier interface {
+	NotifyPartialResult(ctx context.Context, partial PartialResult) error
+}
+
+// progressNotifier sends partial results as MCP progress notifications.
+// Progress notifications only carry a message, so the content is left out and
+// the message names the image urls instead; the images themselves arrive with
+// the final result. Calls without a progress token are skipped, as the client
+// has no way to match them to the request.
+type progressNotifier struct{}
+
+func (progressNotifier) NotifyPartialResult(ctx context.Context, partial PartialResult) error {
+	srv := server.ServerFromContext(ctx)
+	if srv == nil || partial.ProgressToken == nil {
+		return nil
+	}
+
+	return srv.SendNotificationToClient(ctx, "notifications/progress", map[string]any{
+		"progressToken": partial.ProgressToken,
+		"progress":      partial.Progress,
+		"total":         partial.Total,
+		"message":       partial.Message,
+	})
+}
+
+// partialResultNotifier returns the notifier used to stream partial results,
+// or nil when results are buffered. Stdio clients get the whole result at
+// once, as they do not render progress notifications.
+func (d ToolDefaults) partialResultNotifier() PartialResultNotifier {
+	if d.deps.Notifier != nil {
+		return d.deps.Notifier
+	}
+	if d.ServerMode == ServerModeHTTP {
+		return progressNotifier{}
+	}
+	return nil
+}
+
+// progressToken returns the progress token the client attached to the tool call, if any
+func progressToken(req mcp.CallToolRequest) mcp.ProgressToken {
+	if req.Params.Meta == nil {
+		return nil
+	}
+	return req.Params.Meta.ProgressToken
+}
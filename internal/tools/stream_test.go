@@ -0,0 +1,166 @@
+package tools
+
+import (
+	"context"
+	"gaia-mcp-go/internal/testutil"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNotifier records the partial results it is asked to deliver
+type fakeNotifier struct {
+	mu       sync.Mutex
+	partials []PartialResult
+}
+
+func (n *fakeNotifier) NotifyPartialResult(ctx context.Context, partial PartialResult) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.partials = append(n.partials, partial)
+	return nil
+}
+
+func TestGenerateImage_StreamsPartialResults(t *testing.T) {
+	t.Run("Emits a partial result per completed image", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		images := []string{
+			addMockImage(server, "/result-1.png"),
+			addMockImage(server, "/result-2.png"),
+			addMockImage(server, "/result-3.png"),
+		}
+		server.AddResponse("POST", createTaskPath, generatedResponse(images...))
+
+		notifier := &fakeNotifier{}
+		tool := NewGenerateImageTool(newTestApi(server), ToolDefaults{deps: ToolDeps{Notifier: notifier}})
+
+		req := newCallToolRequest(tool.ToolName(), map[string]any{
+			"prompt":         "A red fox",
+			"numberOfImages": 3.0,
+		})
+		req.Params.Meta = &mcp.Meta{ProgressToken: "token-1"}
+
+		result, err := tool.Handler(context.Background(), req)
+
+		require.NoError(t, err)
+		assert.False(t, result.IsError, resultText(result))
+		assert.Equal(t, 3, countImages(result), "the final result still carries every image")
+
+		require.Len(t, notifier.partials, 3)
+		for i, partial := range notifier.partials {
+			assert.Equal(t, "token-1", partial.ProgressToken)
+			assert.Equal(t, i+1, partial.Progress)
+			assert.Equal(t, 3, partial.Total)
+			assert.Contains(t, partial.Message, images[i])
+			require.Len(t, partial.Content, 1)
+			_, isImage := mcp.AsImageContent(partial.Content[0])
+			assert.True(t, isImage)
+		}
+	})
+
+	t.Run("Batches emit a partial result per prompt", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		images := []string{
+			addMockImage(server, "/fox-1.png"),
+			addMockImage(server, "/fox-2.png"),
+			addMockImage(server, "/owl.png"),
+		}
+		server.AddResponseSequence("POST", createTaskPath,
+			generatedResponse(images[0], images[1]),
+			generatedResponse(images[2]),
+		)
+
+		notifier := &fakeNotifier{}
+		tool := NewGenerateImageTool(newTestApi(server), ToolDefaults{deps: ToolDeps{Notifier: notifier}})
+
+		req := newCallToolRequest(tool.ToolName(), map[string]any{
+			"prompts":        []any{"A red fox", "A snowy owl"},
+			"numberOfImages": 2.0,
+		})
+		req.Params.Meta = &mcp.Meta{ProgressToken: "token-1"}
+
+		result, err := tool.Handler(context.Background(), req)
+
+		require.NoError(t, err)
+		assert.False(t, result.IsError, resultText(result))
+
+		// Progress counts prompts, so the images of a prompt are not streamed on their own
+		require.Len(t, notifier.partials, 2)
+		assert.Equal(t, 1, notifier.partials[0].Progress)
+		assert.Equal(t, 2, notifier.partials[0].Total)
+		assert.Contains(t, notifier.partials[0].Message, `Prompt 1 of 2 succeeded: "A red fox"`)
+		assert.Contains(t, notifier.partials[0].Message, images[1])
+		assert.Equal(t, 2, countImages(&mcp.CallToolResult{Content: notifier.partials[0].Content}))
+		assert.Equal(t, 2, notifier.partials[1].Progress)
+		assert.Contains(t, notifier.partials[1].Message, `Prompt 2 of 2 succeeded: "A snowy owl"`)
+	})
+
+	t.Run("Single images are not streamed", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		server.AddResponse("POST", createTaskPath, generatedResponse(addMockImage(server, "/result.png")))
+
+		notifier := &fakeNotifier{}
+		tool := NewGenerateImageTool(newTestApi(server), ToolDefaults{deps: ToolDeps{Notifier: notifier}})
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{"prompt": "A red fox"}))
+
+		require.NoError(t, err)
+		assert.False(t, result.IsError, resultText(result))
+		assert.Empty(t, notifier.partials)
+	})
+}
+
+func TestToolDefaults_PartialResultNotifier(t *testing.T) {
+	t.Run("Stdio buffers the result", func(t *testing.T) {
+		assert.Nil(t, resolveToolDefaults(ToolDefaults{ServerMode: ServerModeStdio}).partialResultNotifier())
+	})
+
+	t.Run("HTTP streams progress notifications", func(t *testing.T) {
+		assert.Equal(t, progressNotifier{}, resolveToolDefaults(ToolDefaults{ServerMode: ServerModeHTTP}).partialResultNotifier())
+	})
+
+	t.Run("A configured notifier wins", func(t *testing.T) {
+		notifier := &fakeNotifier{}
+		assert.Same(t, notifier, resolveToolDefaults(ToolDefaults{deps: ToolDeps{Notifier: notifier}}).partialResultNotifier())
+	})
+
+	t.Run("Progress notifications carry only the spec fields", func(t *testing.T) {
+		s := server.NewMCPServer("test", "1.0.0")
+		s.AddTool(mcp.NewTool("stream"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			err := progressNotifier{}.NotifyPartialResult(ctx, PartialResult{
+				ProgressToken: "token-1",
+				Progress:      1,
+				Total:         2,
+				Message:       "Image 1 of 2 ready. Image url: https://cdn.protogaia.com/1.png",
+				Content:       []mcp.Content{mcp.NewImageContent("aW1hZ2U=", "image/png")},
+			})
+			return mcp.NewToolResultText("done"), err
+		})
+
+		session := fakeSession{id: "session-1", notifications: make(chan mcp.JSONRPCNotification, 1)}
+		s.HandleMessage(s.WithContext(context.Background(), session), []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"stream"}}`))
+
+		require.Len(t, session.notifications, 1)
+		notification := <-session.notifications
+		assert.Equal(t, "notifications/progress", notification.Method)
+		assert.Equal(t, map[string]any{
+			"progressToken": "token-1",
+			"progress":      1,
+			"total":         2,
+			"message":       "Image 1 of 2 ready. Image url: https://cdn.protogaia.com/1.png",
+		}, notification.Params.AdditionalFields)
+	})
+
+	t.Run("Progress notifier without a server is a no-op", func(t *testing.T) {
+		assert.NoError(t, progressNotifier{}.NotifyPartialResult(context.Background(), PartialResult{ProgressToken: "token-1"}))
+	})
+}
@@ -0,0 +1,44 @@
+package tools
+
+import (
+	"context"
+	"gaia-mcp-go/internal/api"
+
+	"github.com/google/uuid"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// requestIdMetaField is the _meta field a client can set to choose the request ID of a tool call
+const requestIdMetaField = "requestId"
+
+// WithRequestTrace wraps a tool handler so every Gaia API call it makes carries
+// the MCP session ID and a request ID identifying the tool call.
+//
+// The request ID is taken from the call's _meta.requestId when the client sets
+// it, otherwise a new UUID is generated for the call.
+func WithRequestTrace(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return next(api.WithRequestTrace(ctx, requestTrace(ctx, req)), req)
+	}
+}
+
+// requestTrace builds the trace of a tool call from its MCP session and metadata
+func requestTrace(ctx context.Context, req mcp.CallToolRequest) api.RequestTrace {
+	var trace api.RequestTrace
+
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		trace.SessionId = session.SessionID()
+	}
+
+	if req.Params.Meta != nil {
+		if requestId, ok := req.Params.Meta.AdditionalFields[requestIdMetaField].(string); ok && requestId != "" {
+			trace.RequestId = requestId
+		}
+	}
+	if trace.RequestId == "" {
+		trace.RequestId = uuid.NewString()
+	}
+
+	return trace
+}
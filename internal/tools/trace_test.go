@@ -0,0 +1,74 @@
+package tools
+
+import (
+	"context"
+	"gaia-mcp-go/internal/api"
+	"gaia-mcp-go/internal/testutil"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSession is a minimal MCP client session with a fixed ID. Notifications
+// sent to it are buffered in notifications, when set.
+type fakeSession struct {
+	id            string
+	notifications chan mcp.JSONRPCNotification
+}
+
+func (s fakeSession) Initialize()                                         {}
+func (s fakeSession) Initialized() bool                                   { return true }
+func (s fakeSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return s.notifications }
+func (s fakeSession) SessionID() string                                   { return s.id }
+
+func TestWithRequestTrace(t *testing.T) {
+	callGenerateImage := func(t *testing.T, ctx context.Context, meta *mcp.Meta) testutil.RecordedRequest {
+		t.Helper()
+
+		testServer := testutil.NewTestServer()
+		defer testServer.Close()
+		testServer.AddResponse("POST", createTaskPath, generatedResponse(addMockImage(testServer, "/result.png")))
+
+		tool := NewGenerateImageTool(newTestApi(testServer))
+		req := newCallToolRequest(tool.ToolName(), map[string]any{"prompt": "A red fox"})
+		req.Params.Meta = meta
+
+		result, err := WithRequestTrace(tool.Handler)(ctx, req)
+		require.NoError(t, err)
+		require.False(t, result.IsError, resultText(result))
+
+		requests := testServer.Requests("POST", createTaskPath)
+		require.Len(t, requests, 1)
+		return requests[0]
+	}
+
+	t.Run("Request ID from the call metadata", func(t *testing.T) {
+		request := callGenerateImage(t, context.Background(), &mcp.Meta{
+			AdditionalFields: map[string]any{"requestId": "request-123"},
+		})
+
+		assert.Equal(t, "request-123", request.Headers.Get(api.RequestIdHeader))
+		assert.Empty(t, request.Headers.Get(api.SessionIdHeader))
+	})
+
+	t.Run("Generated request ID", func(t *testing.T) {
+		first := callGenerateImage(t, context.Background(), nil)
+		second := callGenerateImage(t, context.Background(), nil)
+
+		assert.NotEmpty(t, first.Headers.Get(api.RequestIdHeader))
+		assert.NotEqual(t, first.Headers.Get(api.RequestIdHeader), second.Headers.Get(api.RequestIdHeader))
+	})
+
+	t.Run("Session ID from the MCP session", func(t *testing.T) {
+		mcpServer := server.NewMCPServer("test", "1.0.0")
+		ctx := mcpServer.WithContext(context.Background(), fakeSession{id: "session-42"})
+
+		request := callGenerateImage(t, ctx, nil)
+
+		assert.Equal(t, "session-42", request.Headers.Get(api.SessionIdHeader))
+		assert.NotEmpty(t, request.Headers.Get(api.RequestIdHeader))
+	})
+}
@@ -0,0 +1,108 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"gaia-mcp-go/internal/api"
+	"gaia-mcp-go/pkg/shared"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// UploadAndCreateStyleTool uploads reference images and creates a style from them in one call
+type UploadAndCreateStyleTool struct {
+	api  api.GaiaApi
+	tool mcp.Tool
+}
+
+func NewUploadAndCreateStyleTool(api api.GaiaApi) *UploadAndCreateStyleTool {
+	return &UploadAndCreateStyleTool{
+		api: api,
+		tool: mcp.NewTool(
+			"upload_and_create_style",
+			mcp.WithDescription("Upload reference images to GAIA and create a new style from them in one step. Returns the new style ID."),
+			mcp.WithArray(
+				"image_urls",
+				mcp.Items(map[string]any{"type": "string"}),
+				mcp.Required(),
+				mcp.Description("The URLs of the reference images to upload and build the style from"),
+			),
+			mcp.WithString(
+				"name",
+				mcp.Required(),
+				mcp.Description("The display name of the new style"),
+			),
+			mcp.WithString(
+				"description",
+				mcp.Description("Optional description of the new style"),
+			),
+		),
+	}
+}
+
+func (t *UploadAndCreateStyleTool) ToolName() string {
+	return "upload_and_create_style"
+}
+
+func (t *UploadAndCreateStyleTool) MCPTool() mcp.Tool {
+	return t.tool
+}
+
+func (t *UploadAndCreateStyleTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := req.GetArguments()
+
+	imageUrls, err := stringArrayArg(args, "image_urls")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if len(imageUrls) == 0 {
+		return mcp.NewToolResultError("image_urls must contain at least one URL"), nil
+	}
+
+	name, err := req.RequireString("name")
+	if err != nil || strings.TrimSpace(name) == "" {
+		return mcp.NewToolResultError("name parameter is required"), nil
+	}
+
+	var description *string
+	if value := req.GetString("description", ""); value != "" {
+		description = &value
+	}
+
+	// Upload one image at a time so a single bad URL does not discard the others
+	var uploadedUrls []string
+	var failures []string
+	for _, imageUrl := range imageUrls {
+		files, err := t.api.UploadImages(ctx, []string{imageUrl}, shared.FileAssociatedResourceStyle)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("- %s: %v", imageUrl, err))
+			continue
+		}
+		if len(files) == 0 || files[0].Url == nil {
+			failures = append(failures, fmt.Sprintf("- %s: upload returned no file url", imageUrl))
+			continue
+		}
+		uploadedUrls = append(uploadedUrls, *files[0].Url)
+	}
+
+	if len(uploadedUrls) == 0 {
+		return mcp.NewToolResultError("Failed to upload all images, style was not created:\n" + strings.Join(failures, "\n")), nil
+	}
+
+	style, err := t.api.CreateStyle(ctx, uploadedUrls, name, description)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	resultMsg := fmt.Sprintf("Style created successfully. Style ID: %s", style.Id)
+	resultMsg += fmt.Sprintf("\nUploaded %d of %d images:\n", len(uploadedUrls), len(imageUrls))
+	for _, url := range uploadedUrls {
+		resultMsg += fmt.Sprintf("- %s\n", url)
+	}
+	if len(failures) > 0 {
+		resultMsg += "Failed to upload:\n" + strings.Join(failures, "\n")
+	}
+
+	return mcp.NewToolResultText(resultMsg), nil
+}
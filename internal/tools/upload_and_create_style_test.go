@@ -0,0 +1,127 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"gaia-mcp-go/internal/api"
+	"gaia-mcp-go/internal/testutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const createStylePath = "/api/sd-styles"
+
+// decodeStyleImageUrls decodes the image URLs from a recorded create style request
+func decodeStyleImageUrls(t *testing.T, req testutil.RecordedRequest) []string {
+	t.Helper()
+
+	var payload struct {
+		Images []struct {
+			Url string `json:"url"`
+		} `json:"images"`
+	}
+	require.NoError(t, json.Unmarshal(req.Body, &payload))
+
+	urls := make([]string, len(payload.Images))
+	for i, image := range payload.Images {
+		urls[i] = image.Url
+	}
+	return urls
+}
+
+func TestUploadAndCreateStyle(t *testing.T) {
+	const (
+		firstFileUrl  = "https://cdn.protogaia.com/styles/first.png"
+		secondFileUrl = "https://cdn.protogaia.com/styles/second.png"
+	)
+
+	t.Run("Uploads all images and creates the style", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		addMockUpload(server, firstFileUrl, secondFileUrl)
+		server.AddResponse("POST", createStylePath, testutil.MockResponse{
+			StatusCode: http.StatusOK,
+			Body:       api.SdStyle{Id: "style-123", Name: "Watercolor"},
+		})
+
+		tool := NewUploadAndCreateStyleTool(newTestApi(server))
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"image_urls": []interface{}{addMockImage(server, "/first.png"), addMockImage(server, "/second.png")},
+			"name":       "Watercolor",
+		}))
+
+		require.NoError(t, err)
+		assert.False(t, result.IsError, resultText(result))
+		assert.Contains(t, resultText(result), "Style ID: style-123")
+		assert.Contains(t, resultText(result), "Uploaded 2 of 2 images")
+		assert.NotContains(t, resultText(result), "Failed to upload")
+
+		assert.Len(t, server.Requests("POST", uploadInitPath), 2)
+		requests := server.Requests("POST", createStylePath)
+		require.Len(t, requests, 1)
+		assert.Equal(t, []string{firstFileUrl, secondFileUrl}, decodeStyleImageUrls(t, requests[0]))
+	})
+
+	t.Run("Creates the style from the images that uploaded", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		addMockUpload(server, firstFileUrl)
+		server.AddResponse("POST", createStylePath, testutil.MockResponse{
+			StatusCode: http.StatusOK,
+			Body:       api.SdStyle{Id: "style-123", Name: "Watercolor"},
+		})
+		missingUrl := server.URL + "/missing.png"
+
+		tool := NewUploadAndCreateStyleTool(newTestApi(server))
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"image_urls": []interface{}{addMockImage(server, "/first.png"), missingUrl},
+			"name":       "Watercolor",
+		}))
+
+		require.NoError(t, err)
+		assert.False(t, result.IsError, resultText(result))
+		assert.Contains(t, resultText(result), "Style ID: style-123")
+		assert.Contains(t, resultText(result), "Uploaded 1 of 2 images")
+		assert.Contains(t, resultText(result), "Failed to upload:\n- "+missingUrl)
+
+		requests := server.Requests("POST", createStylePath)
+		require.Len(t, requests, 1)
+		assert.Equal(t, []string{firstFileUrl}, decodeStyleImageUrls(t, requests[0]))
+	})
+
+	t.Run("Does not create the style when every upload fails", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		tool := NewUploadAndCreateStyleTool(newTestApi(server))
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"image_urls": []interface{}{server.URL + "/missing.png", "not-a-url"},
+			"name":       "Watercolor",
+		}))
+
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, resultText(result), "style was not created")
+		assert.Empty(t, server.Requests("POST", createStylePath))
+	})
+
+	t.Run("Rejects missing name", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		tool := NewUploadAndCreateStyleTool(newTestApi(server))
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"image_urls": []interface{}{addMockImage(server, "/first.png")},
+		}))
+
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, resultText(result), "name")
+		assert.Empty(t, server.Requests("POST", uploadInitPath))
+	})
+}
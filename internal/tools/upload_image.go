@@ -2,7 +2,6 @@ package tools
 
 import (
 	"context"
-	"fmt"
 	"gaia-mcp-go/internal/api"
 	"gaia-mcp-go/pkg/shared"
 
@@ -41,26 +40,9 @@ func (t *UploadImageTool) MCPTool() mcp.Tool {
 func (t *UploadImageTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := req.GetArguments()
 
-	// First, get the raw value and check if it exists
-	rawImageUrls, exists := args["image_urls"]
-	if !exists {
-		return mcp.NewToolResultError("image_urls parameter is required"), nil
-	}
-
-	// Convert from []interface{} to []string safely
-	imageUrlsInterface, ok := rawImageUrls.([]interface{})
-	if !ok {
-		return mcp.NewToolResultError("image_urls must be an array"), nil
-	}
-
-	// Convert each interface{} element to string
-	imageUrls := make([]string, len(imageUrlsInterface))
-	for i, url := range imageUrlsInterface {
-		urlStr, ok := url.(string)
-		if !ok {
-			return mcp.NewToolResultError(fmt.Sprintf("image_urls[%d] must be a string", i)), nil
-		}
-		imageUrls[i] = urlStr
+	imageUrls, err := stringArrayArg(args, "image_urls")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
 	}
 
 	// Now we can safely use imageUrls as []string
@@ -70,13 +52,5 @@ func (t *UploadImageTool) Handler(ctx context.Context, req mcp.CallToolRequest)
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	resultMsg := fmt.Sprintf("Uploaded %d images successfully", len(uploadedFiles))
-	resultMsg += "\nFile urls:\n"
-	for _, file := range uploadedFiles {
-		if file.Url != nil {
-			resultMsg += fmt.Sprintf("- %s\n", *file.Url)
-		}
-	}
-
-	return mcp.NewToolResultText(resultMsg), nil
+	return UploadImageResult{Files: uploadedFiles}.Render(), nil
 }
@@ -4,20 +4,33 @@ import (
 	"context"
 	"fmt"
 	"gaia-mcp-go/internal/api"
-	"gaia-mcp-go/pkg/imageutil"
 	"gaia-mcp-go/pkg/shared"
 
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
 type UpscalerTool struct {
-	api  api.GaiaApi
-	tool mcp.Tool
+	api      api.GaiaApi
+	tool     mcp.Tool
+	defaults ToolDefaults
 }
 
-func NewUpscalerTool(api api.GaiaApi) *UpscalerTool {
+// upscalerParams are the recipe params of the upscaler recipe
+type upscalerParams struct {
+	Image        string  `json:"image"`
+	UpscaleMode  string  `json:"upscale_mode"`
+	UpscaleRatio float64 `json:"upscale_ratio"`
+	FolderId     *string `json:"folderId,omitempty"`
+}
+
+// NewUpscalerTool creates the upscaler tool.
+// Optional ToolDefaults override the default upscale ratio.
+func NewUpscalerTool(api api.GaiaApi, overrides ...ToolDefaults) *UpscalerTool {
+	defaults := resolveToolDefaults(overrides...)
+
 	return &UpscalerTool{
-		api: api,
+		api:      api,
+		defaults: defaults,
 		tool: mcp.NewTool(
 			"upscaler",
 			mcp.WithDescription("Enhance the resolution quality of images"),
@@ -28,11 +41,14 @@ func NewUpscalerTool(api api.GaiaApi) *UpscalerTool {
 			),
 			mcp.WithNumber(
 				"ratio",
-				mcp.DefaultNumber(2),
+				mcp.DefaultNumber(defaults.UpscaleRatio),
 				mcp.Min(1),
 				mcp.Max(4),
 				mcp.Description("The ratio to upscale the image. It must be a number between 1 and 4"),
 			),
+			withFolderIdParam(),
+			withReturnModeParam(),
+			withIncludeInputParam(),
 		),
 	}
 }
@@ -46,18 +62,26 @@ func (t *UpscalerTool) MCPTool() mcp.Tool {
 }
 
 func (t *UpscalerTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	args := req.GetArguments()
+	returnMode, err := returnModeArg(req)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 
-	imageUrl := args["image_url"]
-	ratio := args["ratio"]
+	folderId, err := folderIdArg(req.GetArguments())
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	params := upscalerParams{
+		Image:        req.GetString("image_url", ""),
+		UpscaleMode:  "4x-Ultrasharp.pt",
+		UpscaleRatio: req.GetFloat("ratio", t.defaults.UpscaleRatio),
+		FolderId:     folderId,
+	}
 
 	res, err := t.api.GenerateImages(ctx, api.GenerateImagesRequest{
 		RecipeId: shared.RecipeIdUpscaler,
-		Params: map[string]interface{}{
-			"image":         imageUrl,
-			"upscale_mode":  "4x-Ultrasharp.pt",
-			"upscale_ratio": ratio,
-		},
+		Params:   paramsFromStruct(params),
 	})
 
 	if err != nil {
@@ -73,15 +97,16 @@ func (t *UpscalerTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*m
 	}
 
 	if len(res.Images) == 0 {
-		return mcp.NewToolResultError("No images were generated. Please try again."), nil
+		return noImagesResult(res.TaskId), nil
 	}
 
-	base64Data, mimeType, err := imageutil.ProcessImageQuickForMCP(ctx, res.Images[0])
+	msg := fmt.Sprintf("Upscaled successfully. Image url: %s", res.Images[0])
+
+	result, err := t.defaults.generatedImageResult(ctx, returnMode, msg, res.Images[0], res.Fallbacks(0)...)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to process image: %v", err)), nil
 	}
+	appendInputImage(ctx, t.defaults, req, result, req.GetString("image_url", ""))
 
-	msg := fmt.Sprintf("Upscaled successfully. Image url: %s", res.Images[0])
-
-	return mcp.NewToolResultImage(msg, base64Data, mimeType), nil
+	return result, nil
 }
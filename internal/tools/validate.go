@@ -0,0 +1,218 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"gaia-mcp-go/internal/interfaces"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ValidationError describes an argument that does not satisfy the tool's input schema
+type ValidationError struct {
+	// Param is the argument path, e.g. "ratio" or "steps[0].type"
+	Param string
+	// Reason explains which constraint was violated
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("Invalid argument %s: %s", e.Param, e.Reason)
+}
+
+// WithValidation wraps a tool handler so the call arguments are checked against
+// the tool's declared input schema before the handler runs. Invalid calls are
+// answered with a tool error and never reach the handler.
+func WithValidation(tool interfaces.GaiaTool) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if err := ValidateArguments(tool.MCPTool(), req.GetArguments()); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		return tool.Handler(ctx, req)
+	}
+}
+
+// ValidateArguments checks the arguments of a tool call against the tool's input schema.
+//
+// It enforces required arguments, types, enums, numeric ranges, string lengths
+// and array sizes, recursing into array items and object properties. Arguments
+// not declared in the schema are ignored.
+//
+// Returns a *ValidationError for the first violated constraint, or nil.
+func ValidateArguments(tool mcp.Tool, args map[string]any) error {
+	return validateObject("", tool.InputSchema.Properties, tool.InputSchema.Required, args)
+}
+
+// validateObject checks the required properties and every declared property present in values
+func validateObject(path string, properties map[string]any, required []string, values map[string]any) error {
+	for _, name := range required {
+		if value, ok := values[name]; !ok || value == nil {
+			return &ValidationError{Param: joinPath(path, name), Reason: "is required"}
+		}
+	}
+
+	// Check in name order so the reported error is deterministic
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := values[name]
+		schema, ok := properties[name].(map[string]any)
+		if !ok || value == nil {
+			continue
+		}
+		if err := validateValue(joinPath(path, name), schema, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateValue checks a single value against its property schema
+func validateValue(path string, schema map[string]any, value any) error {
+	switch schema["type"] {
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return &ValidationError{Param: path, Reason: "must be a string"}
+		}
+		return validateString(path, schema, str)
+
+	case "number", "integer":
+		number, ok := toFloat(value)
+		if !ok {
+			return &ValidationError{Param: path, Reason: "must be a number"}
+		}
+		if schema["type"] == "integer" && number != float64(int64(number)) {
+			return &ValidationError{Param: path, Reason: "must be an integer"}
+		}
+		return validateNumber(path, schema, number)
+
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return &ValidationError{Param: path, Reason: "must be a boolean"}
+		}
+
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			return &ValidationError{Param: path, Reason: "must be an array"}
+		}
+		return validateArray(path, schema, items)
+
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return &ValidationError{Param: path, Reason: "must be an object"}
+		}
+		properties, _ := schema["properties"].(map[string]any)
+		required, _ := schema["required"].([]string)
+		return validateObject(path, properties, required, object)
+	}
+
+	return nil
+}
+
+// validateString checks the enum, minLength and maxLength constraints
+func validateString(path string, schema map[string]any, value string) error {
+	if enum, ok := schema["enum"].([]string); ok && len(enum) > 0 {
+		found := false
+		for _, allowed := range enum {
+			if value == allowed {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return &ValidationError{Param: path, Reason: fmt.Sprintf("must be one of: %s", strings.Join(enum, ", "))}
+		}
+	}
+
+	length := len([]rune(value))
+	if minimum, ok := toFloat(schema["minLength"]); ok && float64(length) < minimum {
+		return &ValidationError{Param: path, Reason: fmt.Sprintf("must be at least %v characters", minimum)}
+	}
+	if maximum, ok := toFloat(schema["maxLength"]); ok && float64(length) > maximum {
+		return &ValidationError{Param: path, Reason: fmt.Sprintf("must be at most %v characters", maximum)}
+	}
+
+	return nil
+}
+
+// validateNumber checks the minimum and maximum constraints
+func validateNumber(path string, schema map[string]any, value float64) error {
+	minimum, hasMin := toFloat(schema["minimum"])
+	maximum, hasMax := toFloat(schema["maximum"])
+
+	if (hasMin && value < minimum) || (hasMax && value > maximum) {
+		switch {
+		case hasMin && hasMax:
+			return &ValidationError{Param: path, Reason: fmt.Sprintf("must be between %v and %v", minimum, maximum)}
+		case hasMin:
+			return &ValidationError{Param: path, Reason: fmt.Sprintf("must be at least %v", minimum)}
+		default:
+			return &ValidationError{Param: path, Reason: fmt.Sprintf("must be at most %v", maximum)}
+		}
+	}
+
+	return nil
+}
+
+// validateArray checks the minItems and maxItems constraints and every item
+func validateArray(path string, schema map[string]any, items []any) error {
+	if minimum, ok := toFloat(schema["minItems"]); ok && float64(len(items)) < minimum {
+		return &ValidationError{Param: path, Reason: fmt.Sprintf("must contain at least %v items", minimum)}
+	}
+	if maximum, ok := toFloat(schema["maxItems"]); ok && float64(len(items)) > maximum {
+		return &ValidationError{Param: path, Reason: fmt.Sprintf("must contain at most %v items", maximum)}
+	}
+
+	itemSchema, ok := schema["items"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	for i, item := range items {
+		itemPath := fmt.Sprintf("%s[%d]", path, i)
+		if item == nil {
+			return &ValidationError{Param: itemPath, Reason: "must not be null"}
+		}
+		if err := validateValue(itemPath, itemSchema, item); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// toFloat converts JSON and Go numeric values to float64
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// joinPath appends a property name to an argument path
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
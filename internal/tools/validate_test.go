@@ -0,0 +1,132 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"gaia-mcp-go/internal/interfaces"
+	"gaia-mcp-go/internal/testutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateArguments(t *testing.T) {
+	const imageUrl = "https://cdn.protogaia.com/input.png"
+
+	tests := []struct {
+		name          string
+		tool          interfaces.GaiaTool
+		args          map[string]any
+		expectedParam string
+		expectedError string
+	}{
+		// generate_image
+		{name: "generate_image valid", tool: NewGenerateImageTool(nil), args: map[string]any{"prompt": "A red fox", "aspectRatio": "16:9"}},
+		{name: "generate_image prompts valid", tool: NewGenerateImageTool(nil), args: map[string]any{"prompts": []any{"A red fox", "A grey wolf"}}},
+		{name: "generate_image prompts item not a string", tool: NewGenerateImageTool(nil), args: map[string]any{"prompts": []any{"A red fox", 7}}, expectedParam: "prompts[1]", expectedError: "Invalid argument prompts[1]: must be a string"},
+		{name: "generate_image prompt not a string", tool: NewGenerateImageTool(nil), args: map[string]any{"prompt": 42}, expectedParam: "prompt", expectedError: "Invalid argument prompt: must be a string"},
+		{name: "generate_image unknown aspectRatio", tool: NewGenerateImageTool(nil), args: map[string]any{"prompt": "A red fox", "aspectRatio": "4:3"}, expectedParam: "aspectRatio", expectedError: "must be one of: "},
+		{name: "generate_image unknown promptStyle", tool: NewGenerateImageTool(nil), args: map[string]any{"prompt": "A red fox", "promptStyle": "nope"}, expectedParam: "promptStyle", expectedError: "must be one of: "},
+		{name: "generate_image width above max", tool: NewGenerateImageTool(nil), args: map[string]any{"prompt": "A red fox", "width": 4096.0, "height": 1024.0}, expectedParam: "width", expectedError: "must be between 256 and 2048"},
+		{name: "generate_image persist not a boolean", tool: NewGenerateImageTool(nil), args: map[string]any{"prompt": "A red fox", "persist": "yes"}, expectedParam: "persist", expectedError: "must be a boolean"},
+
+		// remix
+		{name: "remix valid", tool: NewRemixTool(nil), args: map[string]any{"inputImage": imageUrl, "variationControl": "strong"}},
+		{name: "remix missing inputImage", tool: NewRemixTool(nil), args: map[string]any{"variationControl": "strong"}, expectedParam: "inputImage", expectedError: "is required"},
+		{name: "remix unknown variationControl", tool: NewRemixTool(nil), args: map[string]any{"inputImage": imageUrl, "variationControl": "wild"}, expectedParam: "variationControl", expectedError: "must be one of: subtle, medium, strong"},
+
+		// upscaler
+		{name: "upscaler valid", tool: NewUpscalerTool(nil), args: map[string]any{"image_url": imageUrl, "ratio": 4.0}},
+		{name: "upscaler integer ratio", tool: NewUpscalerTool(nil), args: map[string]any{"image_url": imageUrl, "ratio": 3}},
+		{name: "upscaler ratio above max", tool: NewUpscalerTool(nil), args: map[string]any{"image_url": imageUrl, "ratio": 5.0}, expectedParam: "ratio", expectedError: "Invalid argument ratio: must be between 1 and 4"},
+		{name: "upscaler ratio below min", tool: NewUpscalerTool(nil), args: map[string]any{"image_url": imageUrl, "ratio": 0.5}, expectedParam: "ratio", expectedError: "must be between 1 and 4"},
+		{name: "upscaler ratio not a number", tool: NewUpscalerTool(nil), args: map[string]any{"image_url": imageUrl, "ratio": "2"}, expectedParam: "ratio", expectedError: "must be a number"},
+
+		// face_enhancer
+		{name: "face_enhancer valid", tool: NewFaceEnhancerTool(nil), args: map[string]any{"image_url": imageUrl, "include_input": true}},
+		{name: "face_enhancer missing image_url", tool: NewFaceEnhancerTool(nil), args: map[string]any{"prompt": "sharper eyes"}, expectedParam: "image_url", expectedError: "is required"},
+		{name: "face_enhancer null image_url", tool: NewFaceEnhancerTool(nil), args: map[string]any{"image_url": nil}, expectedParam: "image_url", expectedError: "is required"},
+
+		// upload_image
+		{name: "upload_image valid", tool: NewUploadImageTool(nil), args: map[string]any{"image_urls": []any{imageUrl}}},
+		{name: "upload_image not an array", tool: NewUploadImageTool(nil), args: map[string]any{"image_urls": imageUrl}, expectedParam: "image_urls", expectedError: "must be an array"},
+		{name: "upload_image item not a string", tool: NewUploadImageTool(nil), args: map[string]any{"image_urls": []any{imageUrl, 7}}, expectedParam: "image_urls[1]", expectedError: "must be a string"},
+
+		// download_image
+		{name: "download_image valid", tool: NewDownloadImageTool(), args: map[string]any{"image_url": imageUrl, "output_path": "fox.png"}},
+		{name: "download_image missing image_url", tool: NewDownloadImageTool(), args: map[string]any{"output_path": "fox.png"}, expectedParam: "image_url", expectedError: "is required"},
+
+		// upload_and_create_style
+		{name: "upload_and_create_style valid", tool: NewUploadAndCreateStyleTool(nil), args: map[string]any{"image_urls": []any{imageUrl}, "name": "Watercolor"}},
+		{name: "upload_and_create_style missing name", tool: NewUploadAndCreateStyleTool(nil), args: map[string]any{"image_urls": []any{imageUrl}}, expectedParam: "name", expectedError: "is required"},
+
+		// pipeline
+		{name: "pipeline valid", tool: NewPipelineTool(nil), args: map[string]any{"steps": []any{map[string]any{"type": "generate_image", "params": map[string]any{"prompt": "A red fox"}}}}},
+		{name: "pipeline unknown step type", tool: NewPipelineTool(nil), args: map[string]any{"steps": []any{map[string]any{"type": "sharpen"}}}, expectedParam: "steps[0].type", expectedError: "must be one of: generate_image, remix, upscaler, face_enhancer"},
+		{name: "pipeline step missing type", tool: NewPipelineTool(nil), args: map[string]any{"steps": []any{map[string]any{"params": map[string]any{}}}}, expectedParam: "steps[0].type", expectedError: "is required"},
+		{name: "pipeline step params not an object", tool: NewPipelineTool(nil), args: map[string]any{"steps": []any{map[string]any{"type": "remix", "params": "strong"}}}, expectedParam: "steps[0].params", expectedError: "must be an object"},
+
+		// replay_recipe
+		{name: "replay_recipe valid", tool: NewReplayRecipeTool(nil), args: map[string]any{"recipe": "{}"}},
+		{name: "replay_recipe missing recipe", tool: NewReplayRecipeTool(nil), args: map[string]any{}, expectedParam: "recipe", expectedError: "is required"},
+
+		// reimagine
+		{name: "reimagine valid", tool: NewReimagineTool(nil), args: map[string]any{"image_url": imageUrl}},
+		{name: "reimagine missing image_url", tool: NewReimagineTool(nil), args: map[string]any{"aspectRatio": "1:1"}, expectedParam: "image_url", expectedError: "is required"},
+
+		// Undeclared arguments are left to the handler
+		{name: "unknown arguments are ignored", tool: NewRemixTool(nil), args: map[string]any{"inputImage": imageUrl, "extra": 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateArguments(tt.tool.MCPTool(), tt.args)
+
+			if tt.expectedParam == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			var validationErr *ValidationError
+			require.True(t, errors.As(err, &validationErr), "expected a ValidationError, got %v", err)
+			assert.Equal(t, tt.expectedParam, validationErr.Param)
+			assert.Contains(t, err.Error(), tt.expectedError)
+		})
+	}
+}
+
+func TestWithValidation(t *testing.T) {
+	t.Run("Invalid arguments never reach the handler", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		tool := NewUpscalerTool(newTestApi(server))
+		result, err := WithValidation(tool)(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"image_url": "https://cdn.protogaia.com/input.png",
+			"ratio":     10.0,
+		}))
+
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Equal(t, "Invalid argument ratio: must be between 1 and 4", resultText(result))
+		assert.Empty(t, server.Requests("POST", createTaskPath))
+	})
+
+	t.Run("Valid arguments are passed to the handler", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		server.AddResponse("POST", createTaskPath, generatedResponse(addMockImage(server, "/result.png")))
+
+		tool := NewUpscalerTool(newTestApi(server))
+		result, err := WithValidation(tool)(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"image_url": "https://cdn.protogaia.com/input.png",
+			"ratio":     4.0,
+		}))
+
+		require.NoError(t, err)
+		assert.False(t, result.IsError, resultText(result))
+		assert.Len(t, server.Requests("POST", createTaskPath), 1)
+	})
+}
@@ -0,0 +1,68 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"gaia-mcp-go/internal/api"
+	"gaia-mcp-go/pkg/imageutil"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// withZipParam declares the optional zip argument of tools that can return several images
+func withZipParam() mcp.ToolOption {
+	return mcp.WithBoolean(
+		"zip",
+		mcp.DefaultBool(false),
+		mcp.Description("When true, also return all generated images as a single zip archive"),
+	)
+}
+
+// zipImages downloads the images and packages them into a zip archive with
+// entries named image_1.png, image_2.jpg, ... in result order
+func zipImages(ctx context.Context, gaiaApi api.GaiaApi, imageUrls []string) ([]byte, error) {
+	entries := make([]imageutil.ZipEntry, 0, len(imageUrls))
+	for i, imageUrl := range imageUrls {
+		data, mimeType, err := gaiaApi.DownloadImageBytes(ctx, imageUrl)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download image %d: %w", i+1, err)
+		}
+		entries = append(entries, imageutil.ZipEntry{
+			Name: fmt.Sprintf("image_%d%s", i+1, imageutil.ExtensionForMimeType(mimeType)),
+			Data: data,
+		})
+	}
+
+	return imageutil.CreateZip(entries)
+}
+
+// zipContents packages the images into a zip archive and returns it as MCP content.
+//
+// The archive is embedded as a base64 resource, unless it exceeds the payload
+// limit of the server mode. Nothing is written to disk, so no files are left
+// behind and remote clients get the archive too. Failures are reported as text
+// so the generated images are not lost.
+func (d ToolDefaults) zipContents(ctx context.Context, gaiaApi api.GaiaApi, imageUrls []string) []mcp.Content {
+	data, err := zipImages(ctx, gaiaApi, imageUrls)
+	if err != nil {
+		return []mcp.Content{mcp.NewTextContent(fmt.Sprintf("Failed to create zip archive: %v", err))}
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(data)
+	if limit := d.ImagePayloadLimit(); len(encoded) > limit {
+		return []mcp.Content{mcp.NewTextContent(fmt.Sprintf(
+			"Warning: the zip archive is too large to return inline (%d bytes encoded, limit %d). Download the images from their urls instead.",
+			len(encoded), limit,
+		))}
+	}
+
+	return []mcp.Content{
+		mcp.NewTextContent(fmt.Sprintf("Zip archive with %d images attached", len(imageUrls))),
+		mcp.NewEmbeddedResource(mcp.BlobResourceContents{
+			URI:      "gaia://images.zip",
+			MIMEType: imageutil.ZipMimeType,
+			Blob:     encoded,
+		}),
+	}
+}
@@ -0,0 +1,133 @@
+package tools
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"gaia-mcp-go/internal/api"
+	"gaia-mcp-go/internal/testutil"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// zipEntryNames returns the sorted entry names of a zip archive
+func zipEntryNames(t *testing.T, data []byte) []string {
+	t.Helper()
+
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(reader.File))
+	for _, file := range reader.File {
+		names = append(names, file.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// zipBlob returns the decoded zip archive embedded in a tool result
+func zipBlob(t *testing.T, result *mcp.CallToolResult) []byte {
+	t.Helper()
+
+	for _, content := range result.Content {
+		if resource, ok := mcp.AsEmbeddedResource(content); ok {
+			blob, ok := mcp.AsBlobResourceContents(resource.Resource)
+			require.True(t, ok, "embedded resource is not a blob")
+			assert.Equal(t, "application/zip", blob.MIMEType)
+
+			data, err := base64.StdEncoding.DecodeString(blob.Blob)
+			require.NoError(t, err)
+			return data
+		}
+	}
+	t.Fatal("result has no embedded zip archive")
+	return nil
+}
+
+func TestGenerateImage_Zip(t *testing.T) {
+	// newZipServer serves two generated images from a CDN hosted by the test server
+	newZipServer := func() (*testutil.TestServer, api.GaiaApi) {
+		server := testutil.NewTestServer()
+		server.AddResponse("POST", createTaskPath, generatedResponse(
+			addMockImage(server, "/result-1.png"),
+			addMockImage(server, "/result-2.png"),
+		))
+		return server, api.NewGaiaApi(api.GaiaApiConfig{BaseUrl: server.URL, CdnUrl: server.URL, ApiKey: "test-key"})
+	}
+
+	t.Run("Stdio mode embeds the archive", func(t *testing.T) {
+		server, gaiaApi := newZipServer()
+		defer server.Close()
+
+		tool := NewGenerateImageTool(gaiaApi)
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"prompt": "A red fox",
+			"zip":    true,
+		}))
+
+		require.NoError(t, err)
+		assert.False(t, result.IsError, resultText(result))
+		assert.Contains(t, resultText(result), "Zip archive with 2 images attached")
+		assert.Equal(t, []string{"image_1.png", "image_2.png"}, zipEntryNames(t, zipBlob(t, result)))
+	})
+
+	t.Run("HTTP mode embeds the archive without writing a file", func(t *testing.T) {
+		server, gaiaApi := newZipServer()
+		defer server.Close()
+
+		tempDir := t.TempDir()
+		t.Setenv("TMPDIR", tempDir)
+
+		tool := NewGenerateImageTool(gaiaApi, ToolDefaults{ServerMode: ServerModeHTTP})
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"prompt": "A red fox",
+			"zip":    true,
+		}))
+
+		require.NoError(t, err)
+		assert.Contains(t, resultText(result), "Zip archive with 2 images attached")
+		assert.Equal(t, []string{"image_1.png", "image_2.png"}, zipEntryNames(t, zipBlob(t, result)))
+
+		entries, err := os.ReadDir(tempDir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("Download failures keep the generated image", func(t *testing.T) {
+		server := testutil.NewTestServer()
+		defer server.Close()
+
+		// The default CDN does not serve the test images
+		server.AddResponse("POST", createTaskPath, generatedResponse(addMockImage(server, "/result-1.png")))
+
+		tool := NewGenerateImageTool(newTestApi(server))
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"prompt": "A red fox",
+			"zip":    true,
+		}))
+
+		require.NoError(t, err)
+		assert.False(t, result.IsError)
+		assert.Equal(t, 1, countImages(result))
+		assert.Contains(t, resultText(result), "Failed to create zip archive")
+	})
+
+	t.Run("Zip is off by default", func(t *testing.T) {
+		server, gaiaApi := newZipServer()
+		defer server.Close()
+
+		tool := NewGenerateImageTool(gaiaApi)
+		result, err := tool.Handler(context.Background(), newCallToolRequest(tool.ToolName(), map[string]any{
+			"prompt": "A red fox",
+		}))
+
+		require.NoError(t, err)
+		assert.NotContains(t, resultText(result), "Zip archive")
+	})
+}
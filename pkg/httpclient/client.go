@@ -4,12 +4,19 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"gaia-mcp-go/pkg/retry"
 	"io"
 	"log"
+	"log/slog"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
+
+	"golang.org/x/time/rate"
 )
 
 // HeaderInterceptor is a function that can modify headers before a request is sent
@@ -21,12 +28,24 @@ type Client struct {
 	baseURL            string              // Base URL for all requests
 	timeout            time.Duration       // Request timeout
 	maxRetries         int                 // Maximum number of retry attempts
-	retryDelay         time.Duration       // Delay between retries
+	backoff            retry.Backoff       // Delay schedule between retries
+	maxRetryAfter      time.Duration       // Longest Retry-After wait honored before retrying
 	debug              bool                // Enable debug logging
 	defaultHeaders     map[string]string   // Headers applied to every request
 	headerInterceptors []HeaderInterceptor // Functions to modify headers before requests
+	retryStatuses      map[int]bool        // Status codes that trigger a retry
+	limiter            *rate.Limiter       // Paces requests; nil when unlimited
+	maxTotalDuration   time.Duration       // Budget for all attempts of a request; zero when unbounded
+	logger             *slog.Logger        // Receives debug logs; nil to use the log package
+	onResponse         ResponseHook        // Called after every attempt that got a response
+	compression        bool                // Request gzip responses
+	compressRequestMin int                 // Gzip request bodies of at least this many bytes; zero to never
 }
 
+// ResponseHook is called with the response of every attempt, retries included,
+// and how long the attempt took. It must not read or close the body.
+type ResponseHook func(resp *http.Response, latency time.Duration)
+
 // Config holds configuration options for creating a new HTTP client
 type Config struct {
 	BaseURL        string            // Base URL for the API
@@ -35,12 +54,50 @@ type Config struct {
 	RetryDelay     time.Duration     // Delay between retries (default: 1 second)
 	Debug          bool              // Enable debug logging
 	DefaultHeaders map[string]string // Headers to add to every request
+	RetryStatuses  []int             // Status codes to retry (default: DefaultRetryStatuses, empty slice: none)
+
+	// MaxRetryAfter is the longest Retry-After wait honored before retrying a 429
+	// (default: DefaultMaxRetryAfter). A longer wait returns the 429 to the caller
+	// right away, so it can react, e.g. by switching to another API key.
+	MaxRetryAfter time.Duration
+
+	RequestsPerSecond float64 // Client-side limit on requests per second, retries included (default: 0, no limit)
+	Burst             int     // Requests allowed at once before RequestsPerSecond applies (default: 1)
+
+	// MaxTotalDuration bounds a request's attempts and the waits between them
+	// (default: 0, only the context deadline applies). The earlier of the two wins.
+	MaxTotalDuration time.Duration
+
+	Logger     *slog.Logger // Receives the debug logs at debug level (default: nil, the log package)
+	OnResponse ResponseHook // Records the status code and latency of every attempt (default: nil)
+
+	EnableCompression           bool // Send Accept-Encoding: gzip (default: false)
+	RequestCompressionThreshold int  // With EnableCompression, gzip request bodies of at least this many bytes (default: 0, never)
+}
+
+// ErrRetryBudgetExhausted is returned when a failed attempt is not retried
+// because the next retry would not finish within the request's deadline
+var ErrRetryBudgetExhausted = errors.New("request deadline budget exhausted")
+
+// DefaultRetryStatuses are the status codes retried when Config.RetryStatuses is nil
+var DefaultRetryStatuses = []int{
+	http.StatusTooManyRequests,     // 429
+	http.StatusInternalServerError, // 500
+	http.StatusBadGateway,          // 502
+	http.StatusServiceUnavailable,  // 503
+	http.StatusGatewayTimeout,      // 504
+}
+
+// requestOptions holds per-request overrides of the client configuration
+type requestOptions struct {
+	retryStatuses map[int]bool // Overrides the client's retryable status codes when non-nil
 }
 
 // APIError represents an error returned by the API
 type APIError struct {
-	StatusCode int    `json:"status_code"`
-	Message    string `json:"message"`
+	StatusCode int         `json:"status_code"`
+	Message    string      `json:"message"`
+	Headers    http.Header `json:"-"` // Response headers (e.g. Retry-After)
 }
 
 // Error implements the error interface for APIError
@@ -48,6 +105,44 @@ func (e *APIError) Error() string {
 	return fmt.Sprintf("API Error %d: %s", e.StatusCode, e.Message)
 }
 
+// DefaultMaxRetryAfter is the longest Retry-After wait honored when Config.MaxRetryAfter is not set
+const DefaultMaxRetryAfter = 10 * time.Second
+
+// maxBodySnippetBytes caps how much of a response body a DecodeError carries
+const maxBodySnippetBytes = 512
+
+// DecodeError is returned when a successful response body does not unmarshal
+// into the target type, usually because the API's response format changed
+type DecodeError struct {
+	StatusCode int    // Status code of the response
+	Snippet    string // Start of the response body, at most maxBodySnippetBytes
+	Err        error  // The unmarshal error
+}
+
+// Error implements the error interface for DecodeError
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("failed to unmarshal response (status %d): %v; body: %s", e.StatusCode, e.Err, e.Snippet)
+}
+
+// Unwrap returns the unmarshal error
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// bodySnippet returns the start of body for error messages, cut on a rune
+// boundary and marked when truncated
+func bodySnippet(body []byte) string {
+	if len(body) <= maxBodySnippetBytes {
+		return string(body)
+	}
+
+	cut := maxBodySnippetBytes
+	for cut > 0 && !utf8.RuneStart(body[cut]) {
+		cut--
+	}
+	return fmt.Sprintf("%s... (%d bytes total)", body[:cut], len(body))
+}
+
 // APIResponse represents a generic API response wrapper
 type APIResponse[T any] struct {
 	Success bool   `json:"success"`
@@ -83,6 +178,15 @@ func New(config Config) *Client {
 	if config.DefaultHeaders == nil {
 		config.DefaultHeaders = make(map[string]string)
 	}
+	if config.MaxRetryAfter == 0 {
+		config.MaxRetryAfter = DefaultMaxRetryAfter
+	}
+	if config.RetryStatuses == nil {
+		config.RetryStatuses = DefaultRetryStatuses
+	}
+	if config.Burst <= 0 {
+		config.Burst = 1
+	}
 
 	// Create the underlying HTTP client with timeout
 	httpClient := &http.Client{
@@ -95,18 +199,41 @@ func New(config Config) *Client {
 		},
 	}
 
+	// Only pace requests when a rate is configured
+	var limiter *rate.Limiter
+	if config.RequestsPerSecond > 0 {
+		limiter = rate.NewLimiter(rate.Limit(config.RequestsPerSecond), config.Burst)
+	}
+
 	return &Client{
 		client:             httpClient,
 		baseURL:            config.BaseURL,
 		timeout:            config.Timeout,
 		maxRetries:         config.MaxRetries,
-		retryDelay:         config.RetryDelay,
+		backoff:            retry.Backoff{Strategy: retry.StrategyLinear, Initial: config.RetryDelay},
+		maxRetryAfter:      config.MaxRetryAfter,
 		debug:              config.Debug,
 		defaultHeaders:     config.DefaultHeaders,
 		headerInterceptors: make([]HeaderInterceptor, 0),
+		retryStatuses:      statusSet(config.RetryStatuses),
+		limiter:            limiter,
+		maxTotalDuration:   config.MaxTotalDuration,
+		logger:             config.Logger,
+		onResponse:         config.OnResponse,
+		compression:        config.EnableCompression,
+		compressRequestMin: config.RequestCompressionThreshold,
 	}
 }
 
+// statusSet converts a list of status codes into a lookup set
+func statusSet(statuses []int) map[int]bool {
+	set := make(map[int]bool, len(statuses))
+	for _, status := range statuses {
+		set[status] = true
+	}
+	return set
+}
+
 // Generic HTTP Methods - Type-safe versions
 
 // GetJSON performs a GET request and returns typed data
@@ -215,11 +342,24 @@ type TypedRequestBuilder struct {
 	endpoint string
 	headers  map[string]string
 	payload  interface{}
+	options  requestOptions
+}
+
+// WithRetryStatuses overrides the retryable status codes for this request only.
+// Calling it with no status codes disables status-based retries for the request.
+func (rb *TypedRequestBuilder) WithRetryStatuses(statuses ...int) *TypedRequestBuilder {
+	rb.options.retryStatuses = statusSet(statuses)
+	return rb
+}
+
+// do executes the request with the builder's options
+func (rb *TypedRequestBuilder) do() (*http.Response, error) {
+	return rb.client.doRequestWithOptions(rb.ctx, rb.method, rb.endpoint, rb.payload, rb.headers, rb.options)
 }
 
 // Into executes the request and unmarshals the response into the specified type
 func (rb *TypedRequestBuilder) Into(target interface{}) error {
-	resp, err := rb.client.doRequest(rb.ctx, rb.method, rb.endpoint, rb.payload, rb.headers)
+	resp, err := rb.do()
 	if err != nil {
 		return err
 	}
@@ -229,7 +369,7 @@ func (rb *TypedRequestBuilder) Into(target interface{}) error {
 // As executes the request and returns the response as the specified type
 func As[T any](rb *TypedRequestBuilder) (T, error) {
 	var result T
-	resp, err := rb.client.doRequest(rb.ctx, rb.method, rb.endpoint, rb.payload, rb.headers)
+	resp, err := rb.do()
 	if err != nil {
 		return result, err
 	}
@@ -241,7 +381,7 @@ func As[T any](rb *TypedRequestBuilder) (T, error) {
 // AsResponse executes the request and returns the response wrapped in APIResponse
 func AsResponse[T any](rb *TypedRequestBuilder) (APIResponse[T], error) {
 	var result APIResponse[T]
-	resp, err := rb.client.doRequest(rb.ctx, rb.method, rb.endpoint, rb.payload, rb.headers)
+	resp, err := rb.do()
 	if err != nil {
 		return result, err
 	}
@@ -253,7 +393,7 @@ func AsResponse[T any](rb *TypedRequestBuilder) (APIResponse[T], error) {
 // AsPaginated executes the request and returns a paginated response
 func AsPaginated[T any](rb *TypedRequestBuilder) (PaginatedResponse[T], error) {
 	var result PaginatedResponse[T]
-	resp, err := rb.client.doRequest(rb.ctx, rb.method, rb.endpoint, rb.payload, rb.headers)
+	resp, err := rb.do()
 	if err != nil {
 		return result, err
 	}
@@ -391,23 +531,72 @@ func (c *Client) DELETE(ctx context.Context, endpoint string, headers map[string
 
 // doRequest is the core method that handles all HTTP requests with retry logic
 func (c *Client) doRequest(ctx context.Context, method, endpoint string, payload interface{}, headers map[string]string) (*http.Response, error) {
+	return c.doRequestWithOptions(ctx, method, endpoint, payload, headers, requestOptions{})
+}
+
+// doRequestWithOptions performs the request with retries, applying per-request overrides.
+// All attempts share one deadline budget: the context deadline, shortened to
+// MaxTotalDuration when that is set.
+func (c *Client) doRequestWithOptions(ctx context.Context, method, endpoint string, payload interface{}, headers map[string]string, options requestOptions) (*http.Response, error) {
+	if c.maxTotalDuration <= 0 {
+		return c.doAttempts(ctx, method, endpoint, payload, headers, options)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.maxTotalDuration)
+	resp, err := c.doAttempts(ctx, method, endpoint, payload, headers, options)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	// The body is read after we return, so the budget ends when it is closed
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// doAttempts sends the request until it succeeds, fails with a status that
+// is not retried, or runs out of attempts or deadline budget
+func (c *Client) doAttempts(ctx context.Context, method, endpoint string, payload interface{}, headers map[string]string, options requestOptions) (*http.Response, error) {
 	// Build the full URL
 	url := c.baseURL + endpoint
 
-	// Prepare the request body if payload is provided
-	var body io.Reader
+	// Marshal the payload once; each attempt reads it through a fresh reader
+	var jsonData []byte
 	if payload != nil {
-		jsonData, err := json.Marshal(payload)
+		var err error
+		jsonData, err = json.Marshal(payload)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal payload: %w", err)
 		}
-		body = bytes.NewBuffer(jsonData)
 	}
 
-	// Retry logic with exponential backoff
+	// Compress large payloads once as well
+	compressed := false
+	if c.shouldCompressRequest(len(jsonData)) {
+		var err error
+		jsonData, err = gzipBytes(jsonData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compress payload: %w", err)
+		}
+		compressed = true
+	}
+
+	// Retry logic with linear backoff
 	var lastErr error
 	for attempt := 0; attempt <= c.maxRetries; attempt++ {
-		// Create a new request for each attempt
+		// Pace every attempt, retries included, to stay under the server's rate limit
+		if c.limiter != nil {
+			if err := c.limiter.Wait(ctx); err != nil {
+				return nil, fmt.Errorf("request cancelled while waiting for the rate limiter: %w", err)
+			}
+		}
+
+		// Create a new request for each attempt. A reader drained by a previous
+		// attempt would send an empty body, so the body is rebuilt every time.
+		var body io.Reader
+		if jsonData != nil {
+			body = bytes.NewReader(jsonData)
+		}
 		req, err := http.NewRequestWithContext(ctx, method, url, body)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create request: %w", err)
@@ -417,6 +606,9 @@ func (c *Client) doRequest(ctx context.Context, method, endpoint string, payload
 		if err := c.applyHeaders(req, headers); err != nil {
 			return nil, fmt.Errorf("failed to apply headers: %w", err)
 		}
+		if compressed {
+			req.Header.Set("Content-Encoding", "gzip")
+		}
 
 		// Log the request if debug is enabled
 		if c.debug {
@@ -424,30 +616,55 @@ func (c *Client) doRequest(ctx context.Context, method, endpoint string, payload
 		}
 
 		// Perform the request
+		start := time.Now()
 		resp, err := c.client.Do(req)
+		latency := time.Since(start)
 		if err != nil {
 			lastErr = err
 			if attempt < c.maxRetries {
-				// Wait before retrying
-				time.Sleep(c.retryDelay * time.Duration(attempt+1)) // Exponential backoff
+				delay := c.backoff.Delay(attempt + 1)
+				if !withinDeadline(ctx, delay) {
+					return nil, fmt.Errorf("%w after %d attempts: %w", ErrRetryBudgetExhausted, attempt+1, err)
+				}
+				// Wait before retrying, giving up early if the context is done
+				if err := retry.Sleep(ctx, delay); err != nil {
+					return nil, fmt.Errorf("request cancelled while waiting to retry: %w", err)
+				}
 				continue
 			}
 			return nil, fmt.Errorf("request failed after %d attempts: %w", c.maxRetries+1, err)
 		}
 
-		// Check if we should retry based on status code
-		if c.shouldRetry(resp.StatusCode) && attempt < c.maxRetries {
-			resp.Body.Close() // Important: close the response body
+		if c.onResponse != nil {
+			c.onResponse(resp, latency)
+		}
+
+		// Check if we should retry based on status code. Without enough budget
+		// left for another attempt, the response is returned as if it were the last.
+		if c.shouldRetry(resp.StatusCode, options) && attempt < c.maxRetries {
+			delay, ok := c.retryDelay(resp, attempt+1, time.Now())
+			if ok && withinDeadline(ctx, delay) {
+				resp.Body.Close() // Important: close the response body
+				if c.debug {
+					c.debugf("Retrying request due to status code %d", resp.StatusCode)
+				}
+				if err := retry.Sleep(ctx, delay); err != nil {
+					return nil, fmt.Errorf("request cancelled while waiting to retry: %w", err)
+				}
+				continue
+			}
 			if c.debug {
-				log.Printf("Retrying request due to status code %d", resp.StatusCode)
+				if ok {
+					c.debugf("Not retrying status code %d: the request deadline budget is spent", resp.StatusCode)
+				} else {
+					c.debugf("Not retrying status code %d: Retry-After of %s is longer than %s", resp.StatusCode, delay, c.maxRetryAfter)
+				}
 			}
-			time.Sleep(c.retryDelay * time.Duration(attempt+1))
-			continue
 		}
 
 		// Log successful response if debug is enabled
 		if c.debug {
-			log.Printf("Request completed with status %d", resp.StatusCode)
+			c.debugf("Request completed with status %d", resp.StatusCode)
 		}
 
 		return resp, nil
@@ -456,12 +673,56 @@ func (c *Client) doRequest(ctx context.Context, method, endpoint string, payload
 	return nil, lastErr
 }
 
+// retryDelay returns how long to wait before retrying after resp. A 429
+// response's Retry-After header wins over the backoff delay of the 1-based attempt.
+// It returns false when the Retry-After wait is longer than maxRetryAfter, in
+// which case the response should not be retried.
+func (c *Client) retryDelay(resp *http.Response, attempt int, now time.Time) (time.Duration, bool) {
+	if resp.StatusCode == http.StatusTooManyRequests {
+		if at, ok := parseRetryAfter(resp.Header.Get("Retry-After"), now); ok {
+			delay := max(at.Sub(now), 0)
+			return delay, delay <= c.maxRetryAfter
+		}
+	}
+	return c.backoff.Delay(attempt), true
+}
+
+// shouldCompressRequest reports whether a payload of size bytes is sent gzipped
+func (c *Client) shouldCompressRequest(size int) bool {
+	return c.compression && c.compressRequestMin > 0 && size >= c.compressRequestMin
+}
+
+// withinDeadline reports whether waiting for delay still leaves time for
+// another attempt before the context deadline
+func withinDeadline(ctx context.Context, delay time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+	return !ok || time.Until(deadline) > delay
+}
+
+// cancelOnClose releases a request's deadline budget once its response body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close closes the body and cancels the request's context
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
 // applyHeaders applies headers in the correct order: defaults -> custom -> interceptors
 func (c *Client) applyHeaders(req *http.Request, customHeaders map[string]string) error {
 	// Step 1: Set standard headers
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("User-Agent", "gaia-mcp-go/1.0")
+	if c.compression {
+		// Setting the header ourselves turns off the transport's transparent
+		// decompression, so readBody decodes the response instead
+		req.Header.Set("Accept-Encoding", "gzip")
+	}
 
 	// Step 2: Apply default headers (can override standard headers)
 	for key, value := range c.defaultHeaders {
@@ -483,8 +744,22 @@ func (c *Client) applyHeaders(req *http.Request, customHeaders map[string]string
 	return nil
 }
 
+// debugf writes a debug log to the configured logger, or the log package without one
+func (c *Client) debugf(format string, args ...any) {
+	if c.logger != nil {
+		c.logger.Debug(fmt.Sprintf(format, args...))
+		return
+	}
+	log.Printf(format, args...)
+}
+
 // logRequest logs request details when debug is enabled
 func (c *Client) logRequest(req *http.Request, method, url string, attempt int) {
+	if c.logger != nil {
+		c.logRequestAttrs(req, method, url, attempt)
+		return
+	}
+
 	log.Printf("Making %s request to %s (attempt %d/%d)", method, url, attempt+1, c.maxRetries+1)
 
 	// Log important headers (but hide sensitive ones)
@@ -497,6 +772,26 @@ func (c *Client) logRequest(req *http.Request, method, url string, attempt int)
 	}
 }
 
+// logRequestAttrs logs request details as a single structured record
+func (c *Client) logRequestAttrs(req *http.Request, method, url string, attempt int) {
+	headers := make([]any, 0, len(req.Header))
+	for key, values := range req.Header {
+		value := strings.Join(values, ", ")
+		if c.isSensitiveHeader(key) {
+			value = "[REDACTED]"
+		}
+		headers = append(headers, slog.String(key, value))
+	}
+
+	c.logger.Debug("Making request",
+		"method", method,
+		"url", url,
+		"attempt", attempt+1,
+		"maxAttempts", c.maxRetries+1,
+		slog.Group("headers", headers...),
+	)
+}
+
 // isSensitiveHeader checks if a header contains sensitive information
 func (c *Client) isSensitiveHeader(key string) bool {
 	sensitiveHeaders := []string{
@@ -516,25 +811,62 @@ func (c *Client) isSensitiveHeader(key string) bool {
 }
 
 // shouldRetry determines if a request should be retried based on the status code
-func (c *Client) shouldRetry(statusCode int) bool {
-	// Retry on server errors (5xx) and specific client errors
-	switch statusCode {
-	case http.StatusTooManyRequests, // 429
-		http.StatusInternalServerError, // 500
-		http.StatusBadGateway,          // 502
-		http.StatusServiceUnavailable,  // 503
-		http.StatusGatewayTimeout:      // 504
-		return true
+func (c *Client) shouldRetry(statusCode int, options requestOptions) bool {
+	// Per-request overrides take precedence over the client's configured set
+	if options.retryStatuses != nil {
+		return options.retryStatuses[statusCode]
+	}
+	return c.retryStatuses[statusCode]
+}
+
+// ParseRateLimitReset determines when a rate limit resets from the response headers.
+//
+// It supports the standard Retry-After header (delta-seconds or HTTP-date) and the
+// common X-RateLimit-Reset header (Unix seconds, Unix milliseconds, or delta-seconds).
+// Returns false when neither header is present or parseable.
+func ParseRateLimitReset(header http.Header, now time.Time) (time.Time, bool) {
+	if at, ok := parseRetryAfter(header.Get("Retry-After"), now); ok {
+		return at, true
+	}
+
+	if reset := strings.TrimSpace(header.Get("X-RateLimit-Reset")); reset != "" {
+		if value, err := strconv.ParseInt(reset, 10, 64); err == nil && value >= 0 {
+			switch {
+			case value >= 1e12: // Unix milliseconds
+				return time.UnixMilli(value), true
+			case value >= 1e9: // Unix seconds
+				return time.Unix(value, 0), true
+			default: // Seconds until reset
+				return now.Add(time.Duration(value) * time.Second), true
+			}
+		}
 	}
-	return false
+
+	return time.Time{}, false
+}
+
+// parseRetryAfter parses a Retry-After value, either delta-seconds or an
+// HTTP-date. Returns false when the value is empty or unparseable.
+func parseRetryAfter(value string, now time.Time) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return now.Add(time.Duration(seconds) * time.Second), true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return date, true
+	}
+	return time.Time{}, false
 }
 
 // parseJSONResponse is the internal method used by generic functions
 func (c *Client) parseJSONResponse(resp *http.Response, target interface{}) error {
 	defer resp.Body.Close() // Always close the response body
 
-	// Read the response body
-	body, err := io.ReadAll(resp.Body)
+	// Read the response body, decompressing it if needed
+	body, err := readBody(resp)
 	if err != nil {
 		return fmt.Errorf("failed to read response body: %w", err)
 	}
@@ -545,6 +877,7 @@ func (c *Client) parseJSONResponse(resp *http.Response, target interface{}) erro
 		var apiErr APIError
 		if json.Unmarshal(body, &apiErr) == nil && apiErr.Message != "" {
 			apiErr.StatusCode = resp.StatusCode
+			apiErr.Headers = resp.Header
 			return &apiErr
 		}
 
@@ -552,12 +885,17 @@ func (c *Client) parseJSONResponse(resp *http.Response, target interface{}) erro
 		return &APIError{
 			StatusCode: resp.StatusCode,
 			Message:    string(body),
+			Headers:    resp.Header,
 		}
 	}
 
 	// Parse successful response
 	if err := json.Unmarshal(body, target); err != nil {
-		return fmt.Errorf("failed to unmarshal response: %w", err)
+		return &DecodeError{
+			StatusCode: resp.StatusCode,
+			Snippet:    bodySnippet(body),
+			Err:        err,
+		}
 	}
 
 	return nil
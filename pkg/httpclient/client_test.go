@@ -0,0 +1,567 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newStatusServer returns a server that replies with failStatus until it has
+// been called failures times, then replies 200 with an empty JSON object
+func newStatusServer(failStatus int, failures int32, calls *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(calls, 1) <= failures {
+			w.WriteHeader(failStatus)
+			w.Write([]byte(`{"message": "try again"}`))
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+}
+
+func TestClient_RetryStatuses(t *testing.T) {
+	tests := []struct {
+		name            string
+		retryStatuses   []int
+		requestStatuses []int
+		overrideRequest bool
+		status          int
+		expectedCalls   int32
+		expectError     bool
+	}{
+		{
+			name:          "Default set retries 503",
+			status:        http.StatusServiceUnavailable,
+			expectedCalls: 2,
+		},
+		{
+			name:          "Default set does not retry 409",
+			status:        http.StatusConflict,
+			expectedCalls: 1,
+			expectError:   true,
+		},
+		{
+			name:          "Custom client set retries 409",
+			retryStatuses: append([]int{http.StatusConflict}, DefaultRetryStatuses...),
+			status:        http.StatusConflict,
+			expectedCalls: 2,
+		},
+		{
+			name:          "Empty client set disables status retries",
+			retryStatuses: []int{},
+			status:        http.StatusServiceUnavailable,
+			expectedCalls: 1,
+			expectError:   true,
+		},
+		{
+			name:            "Per-request set adds 409",
+			requestStatuses: []int{http.StatusConflict},
+			overrideRequest: true,
+			status:          http.StatusConflict,
+			expectedCalls:   2,
+		},
+		{
+			name:            "Per-request set without 429 disables its retry",
+			requestStatuses: []int{http.StatusServiceUnavailable},
+			overrideRequest: true,
+			status:          http.StatusTooManyRequests,
+			expectedCalls:   1,
+			expectError:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			server := newStatusServer(tt.status, 1, &calls)
+			defer server.Close()
+
+			client := New(Config{
+				BaseURL:       server.URL,
+				RetryDelay:    time.Millisecond,
+				RetryStatuses: tt.retryStatuses,
+			})
+
+			builder := client.GetJSON(context.Background(), "/resource", nil)
+			if tt.overrideRequest {
+				builder = builder.WithRetryStatuses(tt.requestStatuses...)
+			}
+			_, err := As[map[string]interface{}](builder)
+
+			if tt.expectError {
+				var apiErr *APIError
+				require.ErrorAs(t, err, &apiErr)
+				assert.Equal(t, tt.status, apiErr.StatusCode)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedCalls, atomic.LoadInt32(&calls))
+		})
+	}
+}
+
+func TestClient_RetryWaitStopsOnCancel(t *testing.T) {
+	tests := []struct {
+		name      string
+		newServer func(calls *int32) *httptest.Server
+	}{
+		{
+			name: "Backoff after a retryable status",
+			newServer: func(calls *int32) *httptest.Server {
+				return newStatusServer(http.StatusServiceUnavailable, 10, calls)
+			},
+		},
+		{
+			name: "Retry-After wait",
+			newServer: func(calls *int32) *httptest.Server {
+				return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					atomic.AddInt32(calls, 1)
+					w.Header().Set("Retry-After", "5")
+					w.WriteHeader(http.StatusTooManyRequests)
+				}))
+			},
+		},
+		{
+			name: "Backoff after a network error",
+			newServer: func(calls *int32) *httptest.Server {
+				return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					atomic.AddInt32(calls, 1)
+					// Drop the connection without a response
+					conn, _, err := w.(http.Hijacker).Hijack()
+					if err == nil {
+						conn.Close()
+					}
+				}))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			server := tt.newServer(&calls)
+			defer server.Close()
+
+			client := New(Config{
+				BaseURL:    server.URL,
+				MaxRetries: 3,
+				RetryDelay: time.Minute,
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(20*time.Millisecond, cancel)
+
+			start := time.Now()
+			_, err := client.GET(ctx, "/resource", nil)
+
+			require.ErrorIs(t, err, context.Canceled)
+			assert.Less(t, time.Since(start), 5*time.Second)
+			assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+		})
+	}
+}
+
+func TestClient_RetryResendsBody(t *testing.T) {
+	var calls int32
+	bodies := make(chan string, 2)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies <- string(body)
+
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := New(Config{BaseURL: server.URL, RetryDelay: time.Millisecond})
+	resp, err := client.POST(context.Background(), "/resource", map[string]string{"prompt": "A red fox"}, nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	assert.JSONEq(t, `{"prompt": "A red fox"}`, <-bodies)
+	assert.JSONEq(t, `{"prompt": "A red fox"}`, <-bodies, "the retry must send the full payload")
+}
+
+func TestClient_RateLimit(t *testing.T) {
+	t.Run("Requests are paced to the configured rate", func(t *testing.T) {
+		var calls int32
+		server := newStatusServer(http.StatusOK, 0, &calls)
+		defer server.Close()
+
+		// The first request uses the burst, the remaining 4 wait 50ms each
+		client := New(Config{BaseURL: server.URL, RequestsPerSecond: 20, Burst: 1})
+
+		start := time.Now()
+		for i := 0; i < 5; i++ {
+			resp, err := client.GET(context.Background(), "/resource", nil)
+			require.NoError(t, err)
+			resp.Body.Close()
+		}
+
+		assert.GreaterOrEqual(t, time.Since(start), 190*time.Millisecond)
+		assert.Equal(t, int32(5), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("Burst requests are not delayed", func(t *testing.T) {
+		var calls int32
+		server := newStatusServer(http.StatusOK, 0, &calls)
+		defer server.Close()
+
+		client := New(Config{BaseURL: server.URL, RequestsPerSecond: 0.1, Burst: 3})
+
+		start := time.Now()
+		for i := 0; i < 3; i++ {
+			resp, err := client.GET(context.Background(), "/resource", nil)
+			require.NoError(t, err)
+			resp.Body.Close()
+		}
+
+		assert.Less(t, time.Since(start), 5*time.Second)
+	})
+
+	t.Run("Waiting stops when the context is done", func(t *testing.T) {
+		var calls int32
+		server := newStatusServer(http.StatusOK, 0, &calls)
+		defer server.Close()
+
+		client := New(Config{BaseURL: server.URL, RequestsPerSecond: 0.01})
+		resp, err := client.GET(context.Background(), "/resource", nil)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		_, err = client.GET(ctx, "/resource", nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "waiting for the rate limiter")
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+}
+
+func TestClient_DeadlineBudget(t *testing.T) {
+	t.Run("MaxTotalDuration bounds all attempts", func(t *testing.T) {
+		var calls int32
+		server := newStatusServer(http.StatusServiceUnavailable, 100, &calls)
+		defer server.Close()
+
+		// Retries wait 50ms, 100ms, 150ms, ...: the third wait no longer fits the budget
+		client := New(Config{
+			BaseURL:          server.URL,
+			MaxRetries:       5,
+			RetryDelay:       50 * time.Millisecond,
+			MaxTotalDuration: 250 * time.Millisecond,
+		})
+
+		start := time.Now()
+		_, err := GetJSON[map[string]any](client, context.Background(), "/resource", nil)
+		elapsed := time.Since(start)
+
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+		assert.Less(t, elapsed, 250*time.Millisecond)
+		assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("The context deadline bounds all attempts", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			time.Sleep(60 * time.Millisecond)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		// Every attempt may take the full per-request timeout, but the retries
+		// together stay within the context deadline
+		client := New(Config{
+			BaseURL:    server.URL,
+			Timeout:    time.Minute,
+			MaxRetries: 10,
+			RetryDelay: 20 * time.Millisecond,
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		resp, err := client.GET(ctx, "/resource", nil)
+		elapsed := time.Since(start)
+		if err == nil {
+			resp.Body.Close()
+		}
+
+		assert.Less(t, elapsed, 300*time.Millisecond+50*time.Millisecond)
+		assert.Less(t, atomic.LoadInt32(&calls), int32(11))
+	})
+
+	t.Run("Network errors stop retrying when the budget is spent", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		url := server.URL
+		server.Close()
+
+		client := New(Config{
+			BaseURL:          url,
+			MaxRetries:       5,
+			RetryDelay:       time.Minute,
+			MaxTotalDuration: time.Second,
+		})
+
+		start := time.Now()
+		_, err := client.GET(context.Background(), "/resource", nil)
+
+		require.ErrorIs(t, err, ErrRetryBudgetExhausted)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("The body is readable after the request returns", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"name": "fox"}`))
+		}))
+		defer server.Close()
+
+		client := New(Config{BaseURL: server.URL, MaxTotalDuration: time.Second})
+		result, err := GetJSON[map[string]string](client, context.Background(), "/resource", nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, "fox", result["name"])
+	})
+}
+
+func TestClient_RetryDelay(t *testing.T) {
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	client := New(Config{RetryDelay: 100 * time.Millisecond})
+
+	tests := []struct {
+		name       string
+		status     int
+		retryAfter string
+		expected   time.Duration
+		retry      bool
+	}{
+		{"Delta seconds", http.StatusTooManyRequests, "3", 3 * time.Second, true},
+		{"HTTP date", http.StatusTooManyRequests, now.Add(5 * time.Second).Format(http.TimeFormat), 5 * time.Second, true},
+		{"HTTP date in the past", http.StatusTooManyRequests, now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+		{"Longer than the maximum", http.StatusTooManyRequests, "60", time.Minute, false},
+		{"Missing header", http.StatusTooManyRequests, "", 200 * time.Millisecond, true},
+		{"Unparseable header", http.StatusTooManyRequests, "soon", 200 * time.Millisecond, true},
+		{"Negative seconds", http.StatusTooManyRequests, "-1", 200 * time.Millisecond, true},
+		{"Other statuses use the backoff", http.StatusServiceUnavailable, "60", 200 * time.Millisecond, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+			if tt.retryAfter != "" {
+				resp.Header.Set("Retry-After", tt.retryAfter)
+			}
+
+			delay, retry := client.retryDelay(resp, 2, now)
+			assert.Equal(t, tt.expected, delay)
+			assert.Equal(t, tt.retry, retry)
+		})
+	}
+}
+
+func TestClient_HonorsRetryAfter(t *testing.T) {
+	tests := []struct {
+		name       string
+		retryAfter func() string
+		minElapsed time.Duration
+		maxElapsed time.Duration
+	}{
+		{"Numeric seconds", func() string { return "1" }, 900 * time.Millisecond, 5 * time.Second},
+		{"HTTP date", func() string { return time.Now().Add(2 * time.Second).UTC().Format(http.TimeFormat) }, 900 * time.Millisecond, 5 * time.Second},
+		{"Missing header", func() string { return "" }, 0, 500 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&calls, 1) == 1 {
+					if value := tt.retryAfter(); value != "" {
+						w.Header().Set("Retry-After", value)
+					}
+					w.WriteHeader(http.StatusTooManyRequests)
+					return
+				}
+				w.Write([]byte(`{}`))
+			}))
+			defer server.Close()
+
+			client := New(Config{BaseURL: server.URL, RetryDelay: time.Millisecond})
+
+			start := time.Now()
+			resp, err := client.GET(context.Background(), "/resource", nil)
+			elapsed := time.Since(start)
+
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+			assert.GreaterOrEqual(t, elapsed, tt.minElapsed)
+			assert.Less(t, elapsed, tt.maxElapsed)
+		})
+	}
+}
+
+func TestClient_OnResponse(t *testing.T) {
+	type hookCall struct {
+		status  int
+		latency time.Duration
+	}
+
+	tests := []struct {
+		name     string
+		failures int32
+		expected []int
+	}{
+		{"Successful request", 0, []int{http.StatusOK}},
+		{"Retried request", 1, []int{http.StatusServiceUnavailable, http.StatusOK}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			server := newStatusServer(http.StatusServiceUnavailable, tt.failures, &calls)
+			defer server.Close()
+
+			var hookCalls []hookCall
+			client := New(Config{
+				BaseURL:    server.URL,
+				RetryDelay: time.Millisecond,
+				OnResponse: func(resp *http.Response, latency time.Duration) {
+					hookCalls = append(hookCalls, hookCall{status: resp.StatusCode, latency: latency})
+				},
+			})
+
+			_, err := GetJSON[map[string]any](client, context.Background(), "/resource", nil)
+			require.NoError(t, err)
+
+			statuses := make([]int, len(hookCalls))
+			for i, call := range hookCalls {
+				statuses[i] = call.status
+				assert.Positive(t, call.latency)
+			}
+			assert.Equal(t, tt.expected, statuses)
+		})
+	}
+}
+
+func TestClient_Logger(t *testing.T) {
+	var calls int32
+	server := newStatusServer(http.StatusServiceUnavailable, 1, &calls)
+	defer server.Close()
+
+	var logs bytes.Buffer
+	client := New(Config{
+		BaseURL:    server.URL,
+		RetryDelay: time.Millisecond,
+		Debug:      true,
+		Logger:     slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	})
+	client.SetBearerToken("secret-token")
+
+	_, err := GetJSON[map[string]any](client, context.Background(), "/resource", nil)
+	require.NoError(t, err)
+
+	output := logs.String()
+	assert.Contains(t, output, `msg="Making request" method=GET`)
+	assert.Contains(t, output, "attempt=2")
+	assert.Contains(t, output, "headers.Authorization=[REDACTED]")
+	assert.NotContains(t, output, "secret-token")
+	assert.Contains(t, output, `msg="Retrying request due to status code 503"`)
+	assert.Contains(t, output, `msg="Request completed with status 200"`)
+}
+
+func TestClient_DecodeError(t *testing.T) {
+	tests := []struct {
+		name            string
+		body            string
+		expectedSnippet string
+	}{
+		{"Malformed JSON", `{"id": "style-123",`, `{"id": "style-123",`},
+		{"Unexpected type", `{"id": 123}`, `{"id": 123}`},
+		{"Long body is truncated", `{"id": "` + strings.Repeat("a", 1000) + `"`, `{"id": "` + strings.Repeat("a", 504) + `... (1009 bytes total)`},
+		{"Truncation keeps runes whole", strings.Repeat("a", 511) + "é" + strings.Repeat("b", 10), strings.Repeat("a", 511) + `... (523 bytes total)`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := New(Config{BaseURL: server.URL})
+			_, err := GetJSON[struct {
+				Id string `json:"id"`
+			}](client, context.Background(), "/resource", nil)
+
+			var decodeErr *DecodeError
+			require.ErrorAs(t, err, &decodeErr)
+			assert.Equal(t, http.StatusOK, decodeErr.StatusCode)
+			assert.Equal(t, tt.expectedSnippet, decodeErr.Snippet)
+			assert.Contains(t, err.Error(), "failed to unmarshal response (status 200)")
+			assert.Contains(t, err.Error(), tt.expectedSnippet)
+		})
+	}
+}
+
+func TestClient_EmptySuccessBody(t *testing.T) {
+	for _, status := range []int{http.StatusOK, http.StatusNoContent} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(status)
+			}))
+			defer server.Close()
+
+			client := New(Config{BaseURL: server.URL})
+			_, err := As[map[string]any](client.PostJSON(context.Background(), "/resource", nil, nil))
+
+			// An empty body is not a valid value, so callers that expect one decide to accept it
+			var decodeErr *DecodeError
+			require.ErrorAs(t, err, &decodeErr)
+			assert.Equal(t, status, decodeErr.StatusCode)
+			assert.Empty(t, decodeErr.Snippet)
+		})
+	}
+}
+
+func TestClient_ReturnsLongRetryAfter(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := New(Config{BaseURL: server.URL, RetryDelay: time.Millisecond, MaxRetryAfter: time.Second})
+
+	start := time.Now()
+	resp, err := client.GET(context.Background(), "/resource", nil)
+
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	assert.Less(t, time.Since(start), time.Second)
+}
@@ -0,0 +1,169 @@
+package httpclient
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func brotliEncode(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	writer := brotli.NewWriter(&buf)
+	_, err := writer.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	return buf.Bytes()
+}
+
+func gzipEncode(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	_, err := writer.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	return buf.Bytes()
+}
+
+func TestClient_DecompressesResponses(t *testing.T) {
+	const payload = `{"name": "compressed", "count": 3}`
+
+	tests := []struct {
+		name     string
+		encoding string
+		body     []byte
+	}{
+		{name: "Brotli", encoding: "br", body: brotliEncode(t, []byte(payload))},
+		{name: "Gzip sent without being requested", encoding: "gzip", body: gzipEncode(t, []byte(payload))},
+		{name: "Uncompressed", body: []byte(payload)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.encoding != "" {
+					w.Header().Set("Content-Encoding", tt.encoding)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Write(tt.body)
+			}))
+			defer server.Close()
+
+			client := New(Config{BaseURL: server.URL})
+			result, err := As[map[string]interface{}](client.GetJSON(context.Background(), "/resource", nil))
+
+			require.NoError(t, err)
+			assert.Equal(t, "compressed", result["name"])
+			assert.Equal(t, float64(3), result["count"])
+		})
+	}
+}
+
+func TestClient_DecompressesBrotliErrors(t *testing.T) {
+	body := brotliEncode(t, []byte(`{"message": "not found"}`))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "br")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	client := New(Config{BaseURL: server.URL})
+	_, err := As[map[string]interface{}](client.GetJSON(context.Background(), "/resource", nil))
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, "not found", apiErr.Message)
+}
+
+func TestDecodingReader_UnsupportedEncoding(t *testing.T) {
+	_, err := decodingReader("compress", io.NopCloser(bytes.NewReader(nil)))
+	assert.ErrorContains(t, err, "unsupported content encoding")
+}
+
+func TestClient_Compression(t *testing.T) {
+	const payload = `{"name": "compressed", "count": 3}`
+
+	t.Run("Responses", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			compress bool
+		}{
+			{name: "Gzip response", compress: true},
+			{name: "Server ignores Accept-Encoding", compress: false},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var acceptEncoding string
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					acceptEncoding = r.Header.Get("Accept-Encoding")
+					w.Header().Set("Content-Type", "application/json")
+					if tt.compress {
+						w.Header().Set("Content-Encoding", "gzip")
+						w.Write(gzipEncode(t, []byte(payload)))
+						return
+					}
+					w.Write([]byte(payload))
+				}))
+				defer server.Close()
+
+				client := New(Config{BaseURL: server.URL, EnableCompression: true})
+				result, err := As[map[string]interface{}](client.GetJSON(context.Background(), "/resource", nil))
+
+				require.NoError(t, err)
+				assert.Equal(t, "gzip", acceptEncoding)
+				assert.Equal(t, "compressed", result["name"])
+				assert.Equal(t, float64(3), result["count"])
+			})
+		}
+	})
+
+	t.Run("Requests", func(t *testing.T) {
+		tests := []struct {
+			name             string
+			config           Config
+			expectedEncoding string
+		}{
+			{name: "Over the threshold", config: Config{EnableCompression: true, RequestCompressionThreshold: 10}, expectedEncoding: "gzip"},
+			{name: "Under the threshold", config: Config{EnableCompression: true, RequestCompressionThreshold: 1 << 20}},
+			{name: "Without a threshold", config: Config{EnableCompression: true}},
+			{name: "Compression disabled", config: Config{RequestCompressionThreshold: 10}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var contentEncoding string
+				var received []byte
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					contentEncoding = r.Header.Get("Content-Encoding")
+					reader, err := decodingReader(contentEncoding, r.Body)
+					require.NoError(t, err)
+					received, _ = io.ReadAll(reader)
+					w.Write([]byte(`{}`))
+				}))
+				defer server.Close()
+
+				tt.config.BaseURL = server.URL
+				client := New(tt.config)
+				_, err := As[map[string]interface{}](client.PostJSON(context.Background(), "/resource", map[string]string{"prompt": "A red fox in the snow"}, nil))
+
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedEncoding, contentEncoding)
+				assert.JSONEq(t, `{"prompt": "A red fox in the snow"}`, string(received))
+			})
+		}
+	})
+}
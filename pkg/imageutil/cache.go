@@ -0,0 +1,111 @@
+package imageutil
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCacheTTL is how long a cached result is kept when CacheTTL is unset
+const DefaultCacheTTL = 5 * time.Minute
+
+// cachedResult is a processed image kept by resultCache
+type cachedResult struct {
+	key       string
+	data      string
+	mimeType  string
+	expiresAt time.Time
+}
+
+// resultCache is a least-recently-used cache of processed images with a
+// time to live. A cache belongs to a single Processor, so its entries are
+// keyed by the image urls under that processor's configuration.
+type resultCache struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	entries map[string]*list.Element
+	order   *list.List // front is the most recently used
+	now     func() time.Time
+}
+
+// newResultCache returns a cache holding up to size results for ttl each,
+// or nil when size is zero or less, which disables caching
+func newResultCache(size int, ttl time.Duration) *resultCache {
+	if size <= 0 {
+		return nil
+	}
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	return &resultCache{
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[string]*list.Element, size),
+		order:   list.New(),
+		now:     time.Now,
+	}
+}
+
+// resultCacheKey returns the cache key of an output kind for the image urls
+func resultCacheKey(kind string, urls []string) string {
+	return kind + "\x00" + strings.Join(urls, "\x00")
+}
+
+// get returns the cached result for key, if present and not expired
+func (c *resultCache) get(key string) (data, mimeType string, ok bool) {
+	if c == nil {
+		return "", "", false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return "", "", false
+	}
+	entry := elem.Value.(*cachedResult)
+	if !c.now().Before(entry.expiresAt) {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return "", "", false
+	}
+	c.order.MoveToFront(elem)
+	return entry.data, entry.mimeType, true
+}
+
+// put stores a result under key, evicting the least recently used result
+// when the cache is full
+func (c *resultCache) put(key, data, mimeType string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := c.now().Add(c.ttl)
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*cachedResult)
+		entry.data, entry.mimeType, entry.expiresAt = data, mimeType, expiresAt
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&cachedResult{key: key, data: data, mimeType: mimeType, expiresAt: expiresAt})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cachedResult).key)
+	}
+}
+
+// len returns the number of cached results, including expired ones not yet removed
+func (c *resultCache) len() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
@@ -0,0 +1,116 @@
+package imageutil
+
+import (
+	"context"
+	"gaia-mcp-go/internal/testutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessor_Cache(t *testing.T) {
+	testServer := testutil.NewTestServer()
+	defer testServer.Close()
+
+	addImage := func(path string) string {
+		testServer.AddResponse("GET", path, testutil.MockResponse{
+			StatusCode: http.StatusOK,
+			Body:       testutil.CreateMockImage(),
+			Headers:    map[string]string{"Content-Type": "image/png"},
+		})
+		return testServer.URL + path
+	}
+
+	t.Run("Second call is served from the cache", func(t *testing.T) {
+		imageURL := addImage("/cached.png")
+		processor := NewQuickProcessor().WithCache(8, time.Minute).Build()
+
+		first, mimeType, err := processor.ProcessImageFromURLForMCP(context.Background(), imageURL)
+		require.NoError(t, err)
+		second, secondMimeType, err := processor.ProcessImageFromURLForMCP(context.Background(), imageURL)
+		require.NoError(t, err)
+
+		assert.Equal(t, first, second)
+		assert.Equal(t, mimeType, secondMimeType)
+		assert.Len(t, testServer.Requests("GET", "/cached.png"), 1)
+
+		// The data URL output is cached separately
+		dataURL, err := processor.ProcessImageFromURL(context.Background(), imageURL)
+		require.NoError(t, err)
+		_, err = processor.ProcessImageFromURL(context.Background(), imageURL)
+		require.NoError(t, err)
+		assert.Contains(t, dataURL, "data:image/png;base64,")
+		assert.Len(t, testServer.Requests("GET", "/cached.png"), 2)
+	})
+
+	t.Run("Disabled by default", func(t *testing.T) {
+		imageURL := addImage("/uncached.png")
+		processor := NewDefaultProcessor()
+
+		for range 2 {
+			_, _, err := processor.ProcessImageFromURLForMCP(context.Background(), imageURL)
+			require.NoError(t, err)
+		}
+		assert.Len(t, testServer.Requests("GET", "/uncached.png"), 2)
+	})
+
+	t.Run("Expired entries are downloaded again", func(t *testing.T) {
+		imageURL := addImage("/expiring.png")
+		processor := NewQuickProcessor().WithCache(8, time.Minute).Build()
+		now := time.Now()
+		processor.cache.now = func() time.Time { return now }
+
+		_, _, err := processor.ProcessImageFromURLForMCP(context.Background(), imageURL)
+		require.NoError(t, err)
+		now = now.Add(time.Minute)
+		_, _, err = processor.ProcessImageFromURLForMCP(context.Background(), imageURL)
+		require.NoError(t, err)
+
+		assert.Len(t, testServer.Requests("GET", "/expiring.png"), 2)
+	})
+
+	t.Run("Errors are not cached", func(t *testing.T) {
+		testServer.AddResponseSequence("GET", "/flaky.png",
+			testutil.MockResponse{StatusCode: http.StatusServiceUnavailable, Body: map[string]string{"message": "unavailable"}},
+			testutil.MockResponse{StatusCode: http.StatusOK, Body: testutil.CreateMockImage(), Headers: map[string]string{"Content-Type": "image/png"}},
+		)
+		processor := NewQuickProcessor().WithCache(8, time.Minute).Build()
+
+		_, _, err := processor.ProcessImageFromURLForMCP(context.Background(), testServer.URL+"/flaky.png")
+		require.Error(t, err)
+		_, _, err = processor.ProcessImageFromURLForMCP(context.Background(), testServer.URL+"/flaky.png")
+		require.NoError(t, err)
+	})
+}
+
+func TestResultCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newResultCache(2, time.Minute)
+	cache.put("a", "A", "image/png")
+	cache.put("b", "B", "image/png")
+
+	// Using "a" makes "b" the least recently used
+	_, _, ok := cache.get("a")
+	require.True(t, ok)
+	cache.put("c", "C", "image/png")
+
+	assert.Equal(t, 2, cache.len())
+	_, _, ok = cache.get("b")
+	assert.False(t, ok)
+	data, _, ok := cache.get("a")
+	assert.True(t, ok)
+	assert.Equal(t, "A", data)
+	_, _, ok = cache.get("c")
+	assert.True(t, ok)
+}
+
+func TestNewResultCache_Disabled(t *testing.T) {
+	assert.Nil(t, newResultCache(0, time.Minute))
+
+	var cache *resultCache
+	cache.put("a", "A", "image/png")
+	_, _, ok := cache.get("a")
+	assert.False(t, ok)
+}
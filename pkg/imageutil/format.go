@@ -0,0 +1,95 @@
+package imageutil
+
+import (
+	"image"
+	"image/color"
+	"strings"
+
+	"golang.org/x/image/draw"
+)
+
+// encodableFormats lists the output formats the processor can encode.
+// Other decodable formats, such as WebP, GIF and BMP, are re-encoded as PNG.
+var encodableFormats = map[string]bool{
+	"jpeg": true,
+	"png":  true,
+}
+
+// alphaFormats lists the encodable formats that preserve transparency
+var alphaFormats = map[string]bool{
+	"png": true,
+}
+
+// SelectOutputFormat picks the encoding for a processed image.
+//
+// Without preferences the source format is kept when it can be encoded, and
+// PNG is used otherwise. With preferences, the first supported format that
+// preserves the image's features wins: formats without alpha support (JPEG)
+// are skipped for images with transparent pixels. PNG is the fallback when
+// no preference qualifies, since it can encode any image losslessly.
+func SelectOutputFormat(img image.Image, sourceFormat string, preferred []string) string {
+	if len(preferred) == 0 {
+		if format := normalizeFormat(sourceFormat); encodableFormats[format] {
+			return format
+		}
+		return "png"
+	}
+
+	needsAlpha := !isOpaque(img)
+	for _, candidate := range preferred {
+		format := normalizeFormat(candidate)
+		if !encodableFormats[format] {
+			continue
+		}
+		if needsAlpha && !alphaFormats[format] {
+			continue
+		}
+		return format
+	}
+
+	return "png"
+}
+
+// normalizeFormat maps format names and aliases to the names used by the image package
+func normalizeFormat(format string) string {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "jpg" {
+		return "jpeg"
+	}
+	return format
+}
+
+// isOpaque reports whether every pixel of the image is fully opaque
+func isOpaque(img image.Image) bool {
+	// The standard image types can answer this without a full scan
+	if opaque, ok := img.(interface{ Opaque() bool }); ok {
+		return opaque.Opaque()
+	}
+
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if _, _, _, a := img.At(x, y).RGBA(); a != 0xffff {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// flatten composites an image with transparent pixels over a solid background.
+// Opaque images are returned unchanged. A nil background uses white.
+func flatten(img image.Image, background color.Color) image.Image {
+	if isOpaque(img) {
+		return img
+	}
+	if background == nil {
+		background = color.White
+	}
+
+	bounds := img.Bounds()
+	flattened := image.NewRGBA(bounds)
+	draw.Draw(flattened, bounds, image.NewUniform(background), image.Point{}, draw.Src)
+	draw.Draw(flattened, bounds, img, bounds.Min, draw.Over)
+	return flattened
+}
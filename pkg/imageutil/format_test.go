@@ -0,0 +1,179 @@
+package imageutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"gaia-mcp-go/internal/testutil"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// opaqueImage returns a fully opaque RGBA image
+func opaqueImage() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			img.Set(x, y, color.RGBA{R: 200, G: 100, B: 50, A: 255})
+		}
+	}
+	return img
+}
+
+// transparentImage returns an RGBA image with one semi-transparent pixel
+func transparentImage() image.Image {
+	img := opaqueImage().(*image.RGBA)
+	img.Set(1, 1, color.RGBA{R: 200, G: 100, B: 50, A: 128})
+	return img
+}
+
+// pixelImage hides the Opaque method of the wrapped image to force a pixel scan
+type pixelImage struct {
+	image.Image
+}
+
+func TestSelectOutputFormat(t *testing.T) {
+	tests := []struct {
+		name         string
+		img          image.Image
+		sourceFormat string
+		preferred    []string
+		expected     string
+	}{
+		{name: "No preference keeps JPEG source", img: opaqueImage(), sourceFormat: "jpeg", expected: "jpeg"},
+		{name: "No preference keeps PNG source", img: transparentImage(), sourceFormat: "png", expected: "png"},
+		{name: "No preference falls back to PNG", img: opaqueImage(), sourceFormat: "gif", expected: "png"},
+		{name: "Opaque image prefers JPEG", img: opaqueImage(), sourceFormat: "png", preferred: []string{"jpeg", "png"}, expected: "jpeg"},
+		{name: "Alpha image skips JPEG", img: transparentImage(), sourceFormat: "png", preferred: []string{"jpeg", "png"}, expected: "png"},
+		{name: "Alpha image without alpha preference falls back to PNG", img: transparentImage(), sourceFormat: "png", preferred: []string{"jpeg"}, expected: "png"},
+		{name: "Opaque image prefers PNG", img: opaqueImage(), sourceFormat: "jpeg", preferred: []string{"png", "jpeg"}, expected: "png"},
+		{name: "Unsupported preferences are skipped", img: opaqueImage(), sourceFormat: "png", preferred: []string{"avif", "jpg"}, expected: "jpeg"},
+		{name: "Preferences are case-insensitive", img: opaqueImage(), sourceFormat: "png", preferred: []string{" JPEG "}, expected: "jpeg"},
+		{name: "Pixel scan detects alpha", img: pixelImage{transparentImage()}, sourceFormat: "png", preferred: []string{"jpeg", "png"}, expected: "png"},
+		{name: "Pixel scan detects opaque", img: pixelImage{opaqueImage()}, sourceFormat: "png", preferred: []string{"jpeg", "png"}, expected: "jpeg"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, SelectOutputFormat(tt.img, tt.sourceFormat, tt.preferred))
+		})
+	}
+}
+
+func TestEncodeImageToBase64Pure_PreferredFormats(t *testing.T) {
+	processor := NewQuickProcessor().WithPreferredFormats("jpeg", "png").Build()
+
+	_, mimeType, err := processor.EncodeImageToBase64Pure(opaqueImage(), "png")
+	assert.NoError(t, err)
+	assert.Equal(t, "image/jpeg", mimeType)
+
+	_, mimeType, err = processor.EncodeImageToBase64Pure(transparentImage(), "png")
+	assert.NoError(t, err)
+	assert.Equal(t, "image/png", mimeType)
+}
+
+func TestEncodeImageToBase64Pure_FlattenBackground(t *testing.T) {
+	// A fully transparent image, so every pixel shows the background
+	transparent := image.NewNRGBA(image.Rect(0, 0, 8, 8))
+
+	tests := []struct {
+		name       string
+		background color.Color
+		expected   color.Gray
+	}{
+		{name: "Default is white", background: nil, expected: color.Gray{Y: 255}},
+		{name: "White", background: color.White, expected: color.Gray{Y: 255}},
+		{name: "Black", background: color.Black, expected: color.Gray{Y: 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := NewQuickProcessor()
+			if tt.background != nil {
+				builder = builder.WithFlattenBackground(tt.background)
+			}
+
+			encoded, mimeType, err := builder.Build().EncodeImageToBase64Pure(transparent, "jpeg")
+			require.NoError(t, err)
+			assert.Equal(t, "image/jpeg", mimeType)
+
+			data, err := base64.StdEncoding.DecodeString(encoded)
+			require.NoError(t, err)
+			decoded, err := jpeg.Decode(bytes.NewReader(data))
+			require.NoError(t, err)
+
+			// JPEG is lossy, so allow a small difference in the corner pixel
+			corner := color.GrayModel.Convert(decoded.At(0, 0)).(color.Gray)
+			assert.InDelta(t, tt.expected.Y, corner.Y, 2)
+		})
+	}
+
+	t.Run("Semi-transparent pixels are blended", func(t *testing.T) {
+		img := image.NewNRGBA(image.Rect(0, 0, 8, 8))
+		for y := 0; y < 8; y++ {
+			for x := 0; x < 8; x++ {
+				img.Set(x, y, color.NRGBA{A: 128})
+			}
+		}
+
+		flattened := flatten(img, color.White)
+		gray := color.GrayModel.Convert(flattened.At(0, 0)).(color.Gray)
+		assert.InDelta(t, 127, gray.Y, 2)
+	})
+
+	t.Run("Opaque images are unchanged", func(t *testing.T) {
+		img := opaqueImage()
+		assert.Same(t, img.(*image.RGBA), flatten(img, color.Black).(*image.RGBA))
+	})
+}
+
+func TestProcessImageFromURLForMCP_GIFAndBMP(t *testing.T) {
+	testServer := testutil.NewTestServer()
+	defer testServer.Close()
+
+	tests := []struct {
+		name        string
+		path        string
+		contentType string
+		body        []byte
+		color       color.RGBA
+	}{
+		// Only the first (red) frame of an animated GIF is kept
+		{"animated GIF", "/animated.gif", "image/gif", testutil.CreateMockGIF(6, 4), color.RGBA{R: 255, A: 255}},
+		{"BMP", "/bitmap.bmp", "image/bmp", testutil.CreateMockBMP(6, 4), color.RGBA{G: 255, A: 255}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testServer.AddResponse("GET", tt.path, testutil.MockResponse{
+				StatusCode: http.StatusOK,
+				Body:       tt.body,
+				Headers:    map[string]string{"Content-Type": tt.contentType},
+			})
+			imageURL := testServer.URL + tt.path
+
+			base64Data, mimeType, err := NewDefaultProcessor().ProcessImageFromURLForMCP(context.Background(), imageURL)
+			require.NoError(t, err)
+			assert.Equal(t, "image/png", mimeType)
+
+			data, err := base64.StdEncoding.DecodeString(base64Data)
+			require.NoError(t, err)
+			img, err := png.Decode(bytes.NewReader(data))
+			require.NoError(t, err)
+			assert.Equal(t, 6, img.Bounds().Dx())
+			assert.Equal(t, 4, img.Bounds().Dy())
+			assert.Equal(t, tt.color, color.RGBAModel.Convert(img.At(0, 0)))
+
+			width, height, err := GetImageDimensions(context.Background(), imageURL)
+			require.NoError(t, err)
+			assert.Equal(t, 6, width)
+			assert.Equal(t, 4, height)
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package imageutil
+
+import (
+	"bytes"
+	"context"
+	"gaia-mcp-go/internal/testutil"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadOrientation(t *testing.T) {
+	for orientation := 1; orientation <= 8; orientation++ {
+		assert.Equal(t, orientation, ReadOrientation(testutil.CreateMockOrientedJPEG(4, 2, orientation)))
+	}
+
+	var plain bytes.Buffer
+	require.NoError(t, jpeg.Encode(&plain, image.NewRGBA(image.Rect(0, 0, 4, 2)), nil))
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"JPEG without EXIF", plain.Bytes()},
+		{"PNG", testutil.CreateMockImage()},
+		{"empty", nil},
+		{"truncated EXIF", testutil.CreateMockOrientedJPEG(4, 2, 6)[:20]},
+		{"out of range orientation", testutil.CreateMockOrientedJPEG(4, 2, 9)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, 1, ReadOrientation(tt.data))
+		})
+	}
+}
+
+func TestApplyOrientation(t *testing.T) {
+	// A 3x2 image with a red top-left pixel, the rest blue
+	src := image.NewNRGBA(image.Rect(0, 0, 3, 2))
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 3; x++ {
+			src.Set(x, y, color.NRGBA{B: 255, A: 255})
+		}
+	}
+	src.Set(0, 0, color.NRGBA{R: 255, A: 255})
+
+	tests := []struct {
+		orientation int
+		width       int
+		height      int
+		red         image.Point
+	}{
+		{1, 3, 2, image.Pt(0, 0)},
+		{2, 3, 2, image.Pt(2, 0)},
+		{3, 3, 2, image.Pt(2, 1)},
+		{4, 3, 2, image.Pt(0, 1)},
+		{5, 2, 3, image.Pt(0, 0)},
+		{6, 2, 3, image.Pt(1, 0)},
+		{7, 2, 3, image.Pt(1, 2)},
+		{8, 2, 3, image.Pt(0, 2)},
+	}
+	for _, tt := range tests {
+		img := ApplyOrientation(src, tt.orientation)
+		bounds := img.Bounds()
+		assert.Equal(t, tt.width, bounds.Dx(), "orientation %d width", tt.orientation)
+		assert.Equal(t, tt.height, bounds.Dy(), "orientation %d height", tt.orientation)
+
+		r, _, _, _ := img.At(bounds.Min.X+tt.red.X, bounds.Min.Y+tt.red.Y).RGBA()
+		assert.Equal(t, uint32(0xFFFF), r, "orientation %d red pixel", tt.orientation)
+	}
+
+	assert.Same(t, src, ApplyOrientation(src, 0).(*image.NRGBA))
+}
+
+func TestDownloadImage_Orientation(t *testing.T) {
+	testServer := testutil.NewTestServer()
+	defer testServer.Close()
+
+	tests := []struct {
+		name        string
+		orientation int
+		width       int
+		height      int
+	}{
+		{"upright", 1, 8, 4},
+		{"rotated clockwise", 6, 4, 8},
+		{"rotated counter-clockwise", 8, 4, 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := "/photo-" + tt.name + ".jpg"
+			testServer.AddResponse("GET", path, testutil.MockResponse{
+				StatusCode: http.StatusOK,
+				Body:       testutil.CreateMockOrientedJPEG(8, 4, tt.orientation),
+				Headers:    map[string]string{"Content-Type": "image/jpeg"},
+			})
+
+			img, format, err := NewDefaultProcessor().DownloadImage(context.Background(), testServer.URL+path)
+			require.NoError(t, err)
+			assert.Equal(t, "jpeg", format)
+			assert.Equal(t, tt.width, img.Bounds().Dx())
+			assert.Equal(t, tt.height, img.Bounds().Dy())
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package imageutil
+
+import (
+	"bytes"
+	"image/png"
+	"sync"
+)
+
+// maxPooledBufferBytes caps the size of buffers returned to the pool. Larger
+// buffers are left to the GC, so a single huge image does not pin its memory.
+const maxPooledBufferBytes = 8 << 20
+
+// bufferPool holds the buffers used to download and encode images. Reusing
+// them avoids allocating fresh buffers for every image under load.
+var bufferPool = sync.Pool{
+	New: func() any {
+		return new(bytes.Buffer)
+	},
+}
+
+// getBuffer returns an empty buffer from the pool
+func getBuffer() *bytes.Buffer {
+	return bufferPool.Get().(*bytes.Buffer)
+}
+
+// putBuffer returns a buffer to the pool. The buffer's contents must not be
+// used afterwards.
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBufferBytes {
+		return
+	}
+	buf.Reset()
+	bufferPool.Put(buf)
+}
+
+// pngBufferPool lets PNG encodes reuse their compression state, which is
+// the largest allocation of an encode
+type pngBufferPool struct {
+	pool sync.Pool
+}
+
+func (p *pngBufferPool) Get() *png.EncoderBuffer {
+	buf, _ := p.pool.Get().(*png.EncoderBuffer)
+	return buf
+}
+
+func (p *pngBufferPool) Put(buf *png.EncoderBuffer) {
+	p.pool.Put(buf)
+}
+
+// pngEncoder encodes PNGs with pooled buffers
+var pngEncoder = &png.Encoder{BufferPool: &pngBufferPool{}}
@@ -0,0 +1,66 @@
+package imageutil
+
+import (
+	"image"
+	"image/color"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBufferPool(t *testing.T) {
+	t.Run("Returned buffers are empty", func(t *testing.T) {
+		buf := getBuffer()
+		buf.WriteString("leftover")
+		putBuffer(buf)
+
+		assert.Zero(t, getBuffer().Len())
+	})
+
+	t.Run("Oversized buffers are not pooled", func(t *testing.T) {
+		buf := getBuffer()
+		buf.Grow(maxPooledBufferBytes + 1)
+		buf.WriteString("leftover")
+		putBuffer(buf)
+
+		// The dropped buffer keeps its contents since it was never reset
+		assert.Equal(t, "leftover", buf.String())
+	})
+}
+
+func TestEncodeImageToBase64Pure_PooledBuffersAreNotShared(t *testing.T) {
+	processor := NewDefaultProcessor()
+
+	// Each image has its own color, so a buffer shared between concurrent
+	// encodes would produce a mismatched result
+	colors := []color.RGBA{{255, 0, 0, 255}, {0, 255, 0, 255}, {0, 0, 255, 255}, {255, 255, 0, 255}}
+	expected := make([]string, len(colors))
+	images := make([]image.Image, len(colors))
+	for i, c := range colors {
+		img := image.NewRGBA(image.Rect(0, 0, 64, 64))
+		for p := 0; p < len(img.Pix); p += 4 {
+			img.Pix[p], img.Pix[p+1], img.Pix[p+2], img.Pix[p+3] = c.R, c.G, c.B, c.A
+		}
+		images[i] = img
+
+		encoded, _, err := processor.EncodeImageToBase64Pure(img, "png")
+		require.NoError(t, err)
+		expected[i] = encoded
+	}
+
+	var wg sync.WaitGroup
+	for round := 0; round < 20; round++ {
+		for i := range images {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				encoded, _, err := processor.EncodeImageToBase64Pure(images[i], "png")
+				assert.NoError(t, err)
+				assert.Equal(t, expected[i], encoded)
+			}(i)
+		}
+	}
+	wg.Wait()
+}
@@ -1,18 +1,25 @@
 package imageutil
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"image"
+	"image/color"
+	// Registers the GIF decoder with image.Decode, which reads the first frame
+	_ "image/gif"
 	"image/jpeg"
-	"image/png"
 	"io"
 	"net/http"
-	"strings"
 	"time"
 
+	// Registers the BMP decoder with image.Decode
+	_ "golang.org/x/image/bmp"
 	"golang.org/x/image/draw"
+	// Registers the WebP decoder with image.Decode
+	_ "golang.org/x/image/webp"
 )
 
 // ProcessorConfig holds configuration for image processing
@@ -27,16 +34,89 @@ type ProcessorConfig struct {
 	JPEGQuality int
 	// UserAgent for HTTP requests
 	UserAgent string
+	// CropAspectRatio center-crops images to this width/height ratio (e.g. 16.0/9.0).
+	// Zero disables cropping.
+	CropAspectRatio float64
+	// Stages is the ordered list of processing stages applied after decoding.
+	// Nil uses DefaultStages; an empty, non-nil slice disables all stages.
+	Stages []Stage
+	// PreferredFormats is the order of output encodings to try (e.g. "jpeg", "png").
+	// The first supported format that keeps the image's alpha channel is used.
+	// Empty keeps the source format.
+	PreferredFormats []string
+	// FlattenBackground fills the transparent areas of an image encoded to a
+	// format without alpha support (JPEG). Nil uses white.
+	FlattenBackground color.Color
+	// MaxDownloadBytes is the largest image body that is downloaded, so huge or
+	// malicious URLs cannot exhaust memory. Zero or less uses DefaultMaxDownloadBytes.
+	MaxDownloadBytes int64
+	// Resampler is the interpolation used when resizing: draw.NearestNeighbor,
+	// draw.ApproxBiLinear, draw.BiLinear or draw.CatmullRom. CatmullRom is the
+	// slowest but keeps downscaled thumbnails sharpest. Nil uses draw.BiLinear.
+	Resampler draw.Interpolator
+	// MinWidth and MinHeight reject source images smaller than this, since tiny
+	// images give poor results (e.g. as style references). Zero is unchecked.
+	MinWidth  int
+	MinHeight int
+	// MaxAspectRatio rejects source images whose longer side is more than this
+	// many times their shorter side, in either orientation (e.g. 4 allows 4:1
+	// and 1:4). Zero is unchecked.
+	MaxAspectRatio float64
+	// Transport performs the HTTP requests that download images, for example
+	// to route them through a proxy or use custom TLS settings. Nil uses
+	// http.DefaultTransport.
+	Transport http.RoundTripper
+	// CacheSize is the number of processed images kept in memory, so the same
+	// url processed again is neither downloaded nor encoded twice. Zero or less
+	// disables the cache.
+	CacheSize int
+	// CacheTTL is how long a processed image stays cached. Zero or less uses
+	// DefaultCacheTTL.
+	CacheTTL time.Duration
+	// AllowLocalFiles lets file:// URLs read images from the local disk. It is
+	// off by default, so urls that come from untrusted input, such as tool
+	// arguments, cannot read arbitrary files.
+	AllowLocalFiles bool
 }
 
+// DefaultMaxDownloadBytes is the default limit on the size of a downloaded image
+const DefaultMaxDownloadBytes int64 = 32 << 20
+
+// ErrImageTooLarge is returned when an image body exceeds MaxDownloadBytes
+var ErrImageTooLarge = errors.New("image exceeds max download size")
+
+// ErrLocalFilesDisabled is returned for a file:// URL when AllowLocalFiles is off
+var ErrLocalFilesDisabled = errors.New("reading local image files is disabled")
+
+// ErrInvalidDimensions is returned when an image violates MinWidth, MinHeight or MaxAspectRatio
+var ErrInvalidDimensions = errors.New("invalid image dimensions")
+
+// Stage identifies a step of the image processing pipeline
+type Stage string
+
+const (
+	// StageCrop center-crops the image to CropAspectRatio (no-op when unset)
+	StageCrop Stage = "crop"
+	// StageResize downscales the image to fit within MaxWidth x MaxHeight
+	StageResize Stage = "resize"
+)
+
+// DefaultStages is the default processing order: crop to the target aspect ratio
+// first, then downscale. Cropping first means the resize works on only the pixels
+// that are kept, which yields sharper output at the final dimensions.
+var DefaultStages = []Stage{StageCrop, StageResize}
+
 // DefaultConfig returns a sensible default configuration
 func DefaultConfig() ProcessorConfig {
 	return ProcessorConfig{
-		MaxWidth:    1024,
-		MaxHeight:   1024,
-		Timeout:     30 * time.Second,
-		JPEGQuality: 90,
-		UserAgent:   "Gaia-MCP-Go/1.0",
+		MaxWidth:          1024,
+		MaxHeight:         1024,
+		Timeout:           30 * time.Second,
+		JPEGQuality:       90,
+		UserAgent:         "Gaia-MCP-Go/1.0",
+		FlattenBackground: color.White,
+		MaxDownloadBytes:  DefaultMaxDownloadBytes,
+		Resampler:         draw.BiLinear,
 	}
 }
 
@@ -44,6 +124,7 @@ func DefaultConfig() ProcessorConfig {
 type Processor struct {
 	config ProcessorConfig
 	client *http.Client
+	cache  *resultCache
 }
 
 // NewProcessor creates a new image processor with the given configuration
@@ -51,8 +132,10 @@ func NewProcessor(config ProcessorConfig) *Processor {
 	return &Processor{
 		config: config,
 		client: &http.Client{
-			Timeout: config.Timeout,
+			Timeout:   config.Timeout,
+			Transport: config.Transport,
 		},
+		cache: newResultCache(config.CacheSize, config.CacheTTL),
 	}
 }
 
@@ -63,41 +146,66 @@ func NewDefaultProcessor() *Processor {
 
 // ProcessImageFromURL downloads an image from URL, resizes it, and returns base64 encoded string
 func (p *Processor) ProcessImageFromURL(ctx context.Context, imageURL string) (string, error) {
+	cacheKey := resultCacheKey("dataurl", []string{imageURL})
+	if base64Str, _, ok := p.cache.get(cacheKey); ok {
+		return base64Str, nil
+	}
+
 	// Step 1: Download the image
 	img, format, err := p.downloadImage(ctx, imageURL)
 	if err != nil {
 		return "", fmt.Errorf("downloading image: %w", err)
 	}
+	if err := p.validateDimensions(img); err != nil {
+		return "", err
+	}
 
-	// Step 2: Resize the image
-	resizedImg := p.resizeImage(img)
+	// Step 2: Apply the processing stages (crop, resize)
+	processedImg := p.applyStages(img)
 
 	// Step 3: Encode to base64
-	base64Str, err := p.encodeImageToBase64(resizedImg, format)
+	base64Str, err := p.encodeImageToBase64(processedImg, format)
 	if err != nil {
 		return "", fmt.Errorf("encoding image to base64: %w", err)
 	}
 
+	p.cache.put(cacheKey, base64Str, "")
 	return base64Str, nil
 }
 
 // ProcessImageFromURLForMCP downloads an image from URL, resizes it, and returns pure base64 data and MIME type for MCP
 func (p *Processor) ProcessImageFromURLForMCP(ctx context.Context, imageURL string) (base64Data string, mimeType string, err error) {
+	return p.ProcessImageFromURLsForMCP(ctx, []string{imageURL})
+}
+
+// ProcessImageFromURLsForMCP is ProcessImageFromURLForMCP for an image
+// available at several urls, such as a CDN url and its origin. The urls are
+// tried in order and the first one that downloads is used.
+func (p *Processor) ProcessImageFromURLsForMCP(ctx context.Context, imageURLs []string) (base64Data string, mimeType string, err error) {
+	cacheKey := resultCacheKey("mcp", imageURLs)
+	if data, cachedMimeType, ok := p.cache.get(cacheKey); ok {
+		return data, cachedMimeType, nil
+	}
+
 	// Step 1: Download the image
-	img, format, err := p.downloadImage(ctx, imageURL)
+	img, format, err := p.downloadFirstImage(ctx, imageURLs)
 	if err != nil {
 		return "", "", fmt.Errorf("downloading image: %w", err)
 	}
+	if err := p.validateDimensions(img); err != nil {
+		return "", "", err
+	}
 
-	// Step 2: Resize the image
-	resizedImg := p.resizeImage(img)
+	// Step 2: Apply the processing stages (crop, resize)
+	processedImg := p.applyStages(img)
 
 	// Step 3: Encode to base64 (pure base64, no data URL prefix)
-	base64Data, mimeType, err = p.encodeImageToBase64Pure(resizedImg, format)
+	base64Data, mimeType, err = p.encodeImageToBase64Pure(processedImg, format)
 	if err != nil {
 		return "", "", fmt.Errorf("encoding image to base64: %w", err)
 	}
 
+	p.cache.put(cacheKey, base64Data, mimeType)
 	return base64Data, mimeType, nil
 }
 
@@ -106,11 +214,35 @@ func (p *Processor) DownloadImage(ctx context.Context, url string) (image.Image,
 	return p.downloadImage(ctx, url)
 }
 
+// DownloadFirstImage tries the candidate urls of an image in order and returns
+// the first one that downloads and decodes, along with its format
+func (p *Processor) DownloadFirstImage(ctx context.Context, urls []string) (image.Image, string, error) {
+	return p.downloadFirstImage(ctx, urls)
+}
+
 // ResizeImage resizes an image to fit within the configured dimensions
 func (p *Processor) ResizeImage(img image.Image) image.Image {
 	return p.resizeImage(img)
 }
 
+// ResizeImageToExactSize resizes an image to exact dimensions with the
+// configured resampler (may distort aspect ratio)
+func (p *Processor) ResizeImageToExactSize(img image.Image, width, height int) image.Image {
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	p.resampler().Scale(dst, dst.Bounds(), img, img.Bounds(), draw.Over, nil)
+	return dst
+}
+
+// CropImage center-crops an image to the configured aspect ratio
+func (p *Processor) CropImage(img image.Image) image.Image {
+	return p.cropImage(img)
+}
+
+// ApplyStages runs the configured processing stages on an image in order
+func (p *Processor) ApplyStages(img image.Image) image.Image {
+	return p.applyStages(img)
+}
+
 // EncodeImageToBase64 encodes an image to base64 string with data URL prefix
 func (p *Processor) EncodeImageToBase64(img image.Image, format string) (string, error) {
 	return p.encodeImageToBase64(img, format)
@@ -121,12 +253,72 @@ func (p *Processor) EncodeImageToBase64Pure(img image.Image, format string) (str
 	return p.encodeImageToBase64Pure(img, format)
 }
 
+// DownloadImageBytes downloads an image without decoding it and returns its
+// raw bytes with the MIME type sniffed from them. The body is subject to the
+// same size limit and error page detection as DownloadImage.
+func (p *Processor) DownloadImageBytes(ctx context.Context, url string) ([]byte, string, error) {
+	var buf bytes.Buffer
+	if err := p.fetchImage(ctx, url, &buf); err != nil {
+		return nil, "", err
+	}
+	data := buf.Bytes()
+	return data, http.DetectContentType(data), nil
+}
+
 // downloadImage downloads an image from the given URL and returns the decoded image
 func (p *Processor) downloadImage(ctx context.Context, url string) (image.Image, string, error) {
+	// Read the body into a pooled buffer, which is only referenced until the
+	// image is decoded
+	buf := getBuffer()
+	defer putBuffer(buf)
+	if err := p.fetchImage(ctx, url, buf); err != nil {
+		return nil, "", err
+	}
+	data := buf.Bytes()
+
+	// Decode the image
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("decoding image: %w", err)
+	}
+
+	// Phone photos are often stored sideways with an EXIF orientation tag
+	img = applyOrientation(img, format, data)
+
+	return img, format, nil
+}
+
+// fetchImage reads the image at url into buf. The url may be an http(s) URL,
+// a data URI, or a file:// URL when AllowLocalFiles is set.
+// It returns an error when the image cannot be read, exceeds the download
+// limit or is not an image.
+func (p *Processor) fetchImage(ctx context.Context, url string, buf *bytes.Buffer) error {
+	var err error
+	if isDataURI(url) {
+		err = p.decodeDataURI(url, buf)
+	} else if path, ok := localImagePath(url); ok {
+		if !p.config.AllowLocalFiles {
+			return fmt.Errorf("%w: %s", ErrLocalFilesDisabled, url)
+		}
+		err = p.readImageFile(path, buf)
+	} else {
+		err = p.fetchHTTPImage(ctx, url, buf)
+	}
+	if err != nil {
+		return err
+	}
+
+	// CDNs sometimes serve an error page with an image content type
+	return CheckImageBody(buf.Bytes())
+}
+
+// fetchHTTPImage downloads the body at url into buf. It returns an error
+// when the status is not 200 or the body exceeds the download limit.
+func (p *Processor) fetchHTTPImage(ctx context.Context, url string, buf *bytes.Buffer) error {
 	// Create request with context
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return nil, "", fmt.Errorf("creating request: %w", err)
+		return fmt.Errorf("creating request: %w", err)
 	}
 
 	// Set user agent to avoid blocking
@@ -135,28 +327,139 @@ func (p *Processor) downloadImage(ctx context.Context, url string) (image.Image,
 	// Download the image
 	resp, err := p.client.Do(req)
 	if err != nil {
-		return nil, "", fmt.Errorf("downloading image: %w", err)
+		return fmt.Errorf("downloading image: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// Check if the response is successful
 	if resp.StatusCode != http.StatusOK {
-		return nil, "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 
-	// Read response body
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, "", fmt.Errorf("reading response body: %w", err)
+	// Fail fast when the server announces a body over the limit
+	maxBytes := p.maxDownloadBytes()
+	if resp.ContentLength > maxBytes {
+		return fmt.Errorf("%w: %d bytes exceeds the %d byte limit", ErrImageTooLarge, resp.ContentLength, maxBytes)
 	}
 
-	// Decode the image
-	img, format, err := image.Decode(strings.NewReader(string(data)))
-	if err != nil {
-		return nil, "", fmt.Errorf("decoding image: %w", err)
+	// Read one byte past the limit to detect oversized bodies sent without a
+	// Content-Length
+	if resp.ContentLength > 0 {
+		buf.Grow(int(resp.ContentLength) + bytes.MinRead)
+	}
+	if _, err := buf.ReadFrom(io.LimitReader(resp.Body, maxBytes+1)); err != nil {
+		return fmt.Errorf("reading response body: %w", err)
+	}
+	if int64(buf.Len()) > maxBytes {
+		return fmt.Errorf("%w: the body exceeds the %d byte limit", ErrImageTooLarge, maxBytes)
 	}
+	return nil
+}
 
-	return img, format, nil
+// downloadFirstImage tries each url in order until one downloads, which
+// covers a primary url that is not available yet, e.g. a CDN url that has not
+// propagated. It returns the error of a single url as is, and the errors of
+// every url joined when several were tried. It stops early once ctx is done.
+func (p *Processor) downloadFirstImage(ctx context.Context, urls []string) (image.Image, string, error) {
+	if len(urls) == 0 {
+		return nil, "", errors.New("no image url")
+	}
+	if len(urls) == 1 {
+		return p.downloadImage(ctx, urls[0])
+	}
+
+	var errs []error
+	for _, url := range urls {
+		img, format, err := p.downloadImage(ctx, url)
+		if err == nil {
+			return img, format, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", url, err))
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, "", fmt.Errorf("all %d image urls failed: %w", len(urls), errors.Join(errs...))
+}
+
+// maxDownloadBytes returns the configured download limit, or the default when unset
+func (p *Processor) maxDownloadBytes() int64 {
+	if p.config.MaxDownloadBytes <= 0 {
+		return DefaultMaxDownloadBytes
+	}
+	return p.config.MaxDownloadBytes
+}
+
+// validateDimensions checks a decoded image against the configured MinWidth,
+// MinHeight and MaxAspectRatio, returning an ErrInvalidDimensions error that
+// names the violated limit
+func (p *Processor) validateDimensions(img image.Image) error {
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	if width < p.config.MinWidth || height < p.config.MinHeight {
+		return fmt.Errorf("%w: the image is %dx%d pixels, smaller than the minimum of %dx%d",
+			ErrInvalidDimensions, width, height, p.config.MinWidth, p.config.MinHeight)
+	}
+
+	if p.config.MaxAspectRatio > 0 && width > 0 && height > 0 {
+		ratio := float64(max(width, height)) / float64(min(width, height))
+		if ratio > p.config.MaxAspectRatio {
+			return fmt.Errorf("%w: the image is %dx%d pixels, an aspect ratio of %.2g:1, more extreme than the maximum of %g:1",
+				ErrInvalidDimensions, width, height, ratio, p.config.MaxAspectRatio)
+		}
+	}
+	return nil
+}
+
+// applyStages runs the configured processing stages on an image in order
+func (p *Processor) applyStages(img image.Image) image.Image {
+	stages := p.config.Stages
+	if stages == nil {
+		stages = DefaultStages
+	}
+
+	for _, stage := range stages {
+		switch stage {
+		case StageCrop:
+			img = p.cropImage(img)
+		case StageResize:
+			img = p.resizeImage(img)
+		}
+	}
+
+	return img
+}
+
+// cropImage center-crops an image to CropAspectRatio, returning it untouched when unset
+func (p *Processor) cropImage(src image.Image) image.Image {
+	ratio := p.config.CropAspectRatio
+	if ratio <= 0 {
+		return src
+	}
+
+	srcBounds := src.Bounds()
+	srcWidth := srcBounds.Dx()
+	srcHeight := srcBounds.Dy()
+
+	// Keep the full height and trim the sides, or keep the full width and trim top/bottom
+	cropWidth := srcWidth
+	cropHeight := int(float64(srcWidth) / ratio)
+	if cropHeight > srcHeight {
+		cropHeight = srcHeight
+		cropWidth = int(float64(srcHeight) * ratio)
+	}
+
+	if cropWidth == srcWidth && cropHeight == srcHeight {
+		return src
+	}
+
+	offsetX := srcBounds.Min.X + (srcWidth-cropWidth)/2
+	offsetY := srcBounds.Min.Y + (srcHeight-cropHeight)/2
+
+	dst := image.NewRGBA(image.Rect(0, 0, cropWidth, cropHeight))
+	draw.Copy(dst, image.Point{}, src, image.Rect(offsetX, offsetY, offsetX+cropWidth, offsetY+cropHeight), draw.Src, nil)
+
+	return dst
 }
 
 // resizeImage resizes an image to fit within maxWidth x maxHeight while maintaining aspect ratio
@@ -188,38 +491,50 @@ func (p *Processor) resizeImage(src image.Image) image.Image {
 	// Create new image
 	dst := image.NewRGBA(image.Rect(0, 0, newWidth, newHeight))
 
-	// Resize using high-quality scaling
-	draw.BiLinear.Scale(dst, dst.Bounds(), src, srcBounds, draw.Over, nil)
+	// Resize using the configured interpolation
+	p.resampler().Scale(dst, dst.Bounds(), src, srcBounds, draw.Over, nil)
 
 	return dst
 }
 
+// resampler returns the configured interpolation, or bilinear when unset
+func (p *Processor) resampler() draw.Interpolator {
+	if p.config.Resampler == nil {
+		return draw.BiLinear
+	}
+	return p.config.Resampler
+}
+
 // encodeImageToBase64Pure encodes an image to pure base64 string without data URL prefix
 func (p *Processor) encodeImageToBase64Pure(img image.Image, format string) (base64Data string, mimeType string, err error) {
-	var buf strings.Builder
+	buf := getBuffer()
+	defer putBuffer(buf)
 
-	// Determine the MIME type and encoding based on original format
-	switch format {
+	// Determine the MIME type and encoding based on the format preferences
+	switch SelectOutputFormat(img, format, p.config.PreferredFormats) {
 	case "jpeg", "jpg":
 		mimeType = "image/jpeg"
-		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: p.config.JPEGQuality}); err != nil {
+		// JPEG has no alpha channel, so transparent areas are filled with the background
+		img = flatten(img, p.config.FlattenBackground)
+		if err := jpeg.Encode(buf, img, &jpeg.Options{Quality: p.config.JPEGQuality}); err != nil {
 			return "", "", fmt.Errorf("encoding JPEG: %w", err)
 		}
 	case "png":
 		mimeType = "image/png"
-		if err := png.Encode(&buf, img); err != nil {
+		if err := pngEncoder.Encode(buf, img); err != nil {
 			return "", "", fmt.Errorf("encoding PNG: %w", err)
 		}
 	default:
-		// Default to PNG for unknown formats
+		// Default to PNG for other formats, including WebP, GIF and BMP
+		// which can be decoded but not encoded
 		mimeType = "image/png"
-		if err := png.Encode(&buf, img); err != nil {
+		if err := pngEncoder.Encode(buf, img); err != nil {
 			return "", "", fmt.Errorf("encoding PNG: %w", err)
 		}
 	}
 
 	// Encode to base64 (pure base64, no prefix)
-	encoded := base64.StdEncoding.EncodeToString([]byte(buf.String()))
+	encoded := base64.StdEncoding.EncodeToString(buf.Bytes())
 
 	return encoded, mimeType, nil
 }
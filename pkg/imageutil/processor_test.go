@@ -1,15 +1,23 @@
 package imageutil
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"gaia-mcp-go/internal/testutil"
 	"image"
+	"image/color"
+	"image/png"
+	"io"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/draw"
 )
 
 // TestDefaultConfig tests the default configuration
@@ -21,6 +29,9 @@ func TestDefaultConfig(t *testing.T) {
 	assert.Equal(t, 30*time.Second, config.Timeout)
 	assert.Equal(t, 90, config.JPEGQuality)
 	assert.Equal(t, "Gaia-MCP-Go/1.0", config.UserAgent)
+	assert.Equal(t, color.White, config.FlattenBackground)
+	assert.Equal(t, DefaultMaxDownloadBytes, config.MaxDownloadBytes)
+	assert.Equal(t, draw.BiLinear, config.Resampler)
 }
 
 // TestNewProcessor tests processor creation
@@ -81,6 +92,34 @@ func TestProcessImageFromURL(t *testing.T) {
 		assert.Contains(t, result, "base64,", "Result should contain base64 data")
 	})
 
+	t.Run("WebP image is re-encoded as PNG", func(t *testing.T) {
+		testServer.AddResponse("GET", "/test-image.webp", testutil.MockResponse{
+			StatusCode: http.StatusOK,
+			Body:       testutil.CreateMockWebPImage(),
+			Headers: map[string]string{
+				"Content-Type": "image/webp",
+			},
+		})
+
+		processor := NewDefaultProcessor()
+		result, err := processor.ProcessImageFromURL(context.Background(), testServer.URL+"/test-image.webp")
+		require.NoError(t, err)
+
+		encoded, ok := strings.CutPrefix(result, "data:image/png;base64,")
+		require.True(t, ok, "Result should be a PNG data URL: %s", result)
+
+		data, err := base64.StdEncoding.DecodeString(encoded)
+		require.NoError(t, err)
+		img, err := png.Decode(bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Equal(t, image.Rect(0, 0, 1, 1), img.Bounds())
+
+		// The source format is still reported by the download
+		_, format, err := processor.DownloadImage(context.Background(), testServer.URL+"/test-image.webp")
+		require.NoError(t, err)
+		assert.Equal(t, "webp", format)
+	})
+
 	t.Run("HTTP error handling", func(t *testing.T) {
 		// Setup mock 404 response
 		testServer.AddResponse("GET", "/not-found.png", testutil.MockResponse{
@@ -199,6 +238,123 @@ func TestResizeImage(t *testing.T) {
 	})
 }
 
+// TestResizeImage_Resampler tests the configurable resize interpolation
+func TestResizeImage_Resampler(t *testing.T) {
+	// A horizontal gradient, inverted in alternating 16x16 tiles so the
+	// downscaled image keeps fine detail for the interpolations to disagree on
+	src := image.NewGray(image.Rect(0, 0, 256, 256))
+	for y := 0; y < 256; y++ {
+		for x := 0; x < 256; x++ {
+			value := uint8(x)
+			if (x/16+y/16)%2 == 0 {
+				value = 255 - value
+			}
+			src.SetGray(x, y, color.Gray{Y: value})
+		}
+	}
+
+	resize := func(resampler draw.Interpolator) *image.RGBA {
+		processor := NewQuickProcessor().WithMaxSize(50, 50).WithResampler(resampler).Build()
+		resized, ok := processor.ResizeImage(src).(*image.RGBA)
+		require.True(t, ok)
+		require.Equal(t, image.Rect(0, 0, 50, 50), resized.Bounds())
+		return resized
+	}
+
+	// variance of the red channel, which equals the gray value
+	variance := func(img *image.RGBA) float64 {
+		var sum, sumSquares float64
+		for i := 0; i < len(img.Pix); i += 4 {
+			value := float64(img.Pix[i])
+			sum += value
+			sumSquares += value * value
+		}
+		n := float64(len(img.Pix) / 4)
+		mean := sum / n
+		return sumSquares/n - mean*mean
+	}
+
+	nearest := resize(draw.NearestNeighbor)
+	bilinear := resize(draw.BiLinear)
+	catmullRom := resize(draw.CatmullRom)
+
+	assert.NotEqual(t, nearest.Pix, catmullRom.Pix, "CatmullRom should differ from NearestNeighbor")
+	assert.Greater(t, variance(catmullRom), variance(bilinear), "CatmullRom should be sharper than BiLinear")
+
+	// Nil keeps the bilinear default
+	assert.Equal(t, bilinear.Pix, resize(nil).Pix)
+
+	// Exact-size resizing uses the configured resampler too
+	exact := NewQuickProcessor().WithResampler(draw.CatmullRom).Build().ResizeImageToExactSize(src, 50, 50)
+	assert.Equal(t, catmullRom.Pix, exact.(*image.RGBA).Pix)
+	assert.Equal(t, bilinear.Pix, ResizeImageToExactSize(src, 50, 50).(*image.RGBA).Pix)
+}
+
+// TestApplyStages tests the configurable crop/resize pipeline
+func TestApplyStages(t *testing.T) {
+	// A 400x200 (2:1) source image
+	src := image.NewRGBA(image.Rect(0, 0, 400, 200))
+
+	tests := []struct {
+		name           string
+		builder        *QuickProcessConfig
+		expectedWidth  int
+		expectedHeight int
+	}{
+		{
+			name:           "Default order crops then resizes",
+			builder:        NewQuickProcessor().WithMaxSize(100, 100).WithCropAspectRatio(1),
+			expectedWidth:  100,
+			expectedHeight: 100,
+		},
+		{
+			name:           "Resize then crop",
+			builder:        NewQuickProcessor().WithMaxSize(100, 100).WithCropAspectRatio(1).WithStages(StageResize, StageCrop),
+			expectedWidth:  50,
+			expectedHeight: 50,
+		},
+		{
+			name:           "Disabling resize only crops",
+			builder:        NewQuickProcessor().WithMaxSize(100, 100).WithCropAspectRatio(1).WithStages(StageCrop),
+			expectedWidth:  200,
+			expectedHeight: 200,
+		},
+		{
+			name:           "Disabling all stages leaves dimensions untouched",
+			builder:        NewQuickProcessor().WithMaxSize(100, 100).WithCropAspectRatio(1).WithStages(),
+			expectedWidth:  400,
+			expectedHeight: 200,
+		},
+		{
+			name:           "No crop ratio only resizes",
+			builder:        NewQuickProcessor().WithMaxSize(100, 100),
+			expectedWidth:  100,
+			expectedHeight: 50,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			processed := tt.builder.Build().ApplyStages(src)
+			bounds := processed.Bounds()
+
+			assert.Equal(t, tt.expectedWidth, bounds.Dx())
+			assert.Equal(t, tt.expectedHeight, bounds.Dy())
+		})
+	}
+}
+
+// TestCropImage tests center-cropping to an aspect ratio
+func TestCropImage(t *testing.T) {
+	processor := NewProcessor(ProcessorConfig{CropAspectRatio: 16.0 / 9.0})
+
+	// A tall 900x900 image should lose height to become 16:9
+	cropped := processor.CropImage(image.NewRGBA(image.Rect(0, 0, 900, 900)))
+
+	assert.Equal(t, 900, cropped.Bounds().Dx())
+	assert.Equal(t, 506, cropped.Bounds().Dy())
+}
+
 // TestEncodeImageToBase64 tests the base64 encoding
 func TestEncodeImageToBase64(t *testing.T) {
 	processor := NewDefaultProcessor()
@@ -297,6 +453,25 @@ func TestDownloadImage(t *testing.T) {
 		assert.Empty(t, format)
 	})
 
+	t.Run("HTML error page with image content type", func(t *testing.T) {
+		testServer.AddResponse("GET", "/html-error.png", testutil.MockResponse{
+			StatusCode: http.StatusOK,
+			Body:       "<!DOCTYPE html><html><head><title>Access Denied</title></head></html>",
+			Headers: map[string]string{
+				"Content-Type": "image/png",
+			},
+		})
+
+		ctx := context.Background()
+		img, format, err := processor.DownloadImage(ctx, testServer.URL+"/html-error.png")
+
+		require.Error(t, err)
+		assert.Nil(t, img)
+		assert.Empty(t, format)
+		assert.Contains(t, err.Error(), "HTML document instead of an image")
+		assert.Contains(t, err.Error(), "Access Denied")
+	})
+
 	t.Run("Invalid image data", func(t *testing.T) {
 		testServer.AddResponse("GET", "/invalid.png", testutil.MockResponse{
 			StatusCode: http.StatusOK,
@@ -315,6 +490,182 @@ func TestDownloadImage(t *testing.T) {
 	})
 }
 
+// TestDownloadImage_MaxDownloadBytes tests the download size limit
+func TestDownloadImage_MaxDownloadBytes(t *testing.T) {
+	testServer := testutil.NewTestServer()
+	defer testServer.Close()
+
+	mockImageData := testutil.CreateMockImage()
+	processor := NewQuickProcessor().WithMaxDownloadBytes(int64(len(mockImageData))).Build()
+
+	t.Run("Image within the limit", func(t *testing.T) {
+		testServer.AddResponse("GET", "/small.png", testutil.MockResponse{
+			StatusCode: http.StatusOK,
+			Body:       mockImageData,
+			Headers: map[string]string{
+				"Content-Type": "image/png",
+			},
+		})
+
+		img, format, err := processor.DownloadImage(context.Background(), testServer.URL+"/small.png")
+
+		require.NoError(t, err)
+		assert.NotNil(t, img)
+		assert.Equal(t, "png", format)
+	})
+
+	t.Run("Content-Length over the limit", func(t *testing.T) {
+		testServer.AddResponse("GET", "/large.png", testutil.MockResponse{
+			StatusCode: http.StatusOK,
+			Body:       append(mockImageData, make([]byte, 1024)...),
+			Headers: map[string]string{
+				"Content-Type": "image/png",
+			},
+		})
+
+		img, format, err := processor.DownloadImage(context.Background(), testServer.URL+"/large.png")
+
+		require.ErrorIs(t, err, ErrImageTooLarge)
+		assert.Nil(t, img)
+		assert.Empty(t, format)
+		assert.Contains(t, err.Error(), "image exceeds max download size")
+	})
+
+	t.Run("Streamed body over the limit", func(t *testing.T) {
+		// Flushing before the body is complete sends it chunked, without a Content-Length
+		streamServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(mockImageData)
+			w.(http.Flusher).Flush()
+			w.Write(make([]byte, 1024))
+		}))
+		defer streamServer.Close()
+
+		img, format, err := processor.DownloadImage(context.Background(), streamServer.URL+"/stream.png")
+
+		require.ErrorIs(t, err, ErrImageTooLarge)
+		assert.Nil(t, img)
+		assert.Empty(t, format)
+	})
+
+	t.Run("Zero limit uses the default", func(t *testing.T) {
+		config := DefaultConfig()
+		config.MaxDownloadBytes = 0
+
+		img, _, err := NewProcessor(config).DownloadImage(context.Background(), testServer.URL+"/large.png")
+
+		require.NoError(t, err)
+		assert.NotNil(t, img)
+	})
+}
+
+// TestDownloadImageBytes tests downloading an image without decoding it
+func TestDownloadImageBytes(t *testing.T) {
+	testServer := testutil.NewTestServer()
+	defer testServer.Close()
+
+	mockImageData := testutil.CreateMockImage()
+	processor := NewDefaultProcessor()
+
+	t.Run("Returns the raw bytes", func(t *testing.T) {
+		testServer.AddResponse("GET", "/raw.png", testutil.MockResponse{
+			StatusCode: http.StatusOK,
+			Body:       mockImageData,
+			Headers:    map[string]string{"Content-Type": "application/octet-stream"},
+		})
+
+		data, mimeType, err := processor.DownloadImageBytes(context.Background(), testServer.URL+"/raw.png")
+
+		require.NoError(t, err)
+		assert.Equal(t, mockImageData, data)
+		assert.Equal(t, "image/png", mimeType)
+	})
+
+	t.Run("HTML error page", func(t *testing.T) {
+		testServer.AddResponse("GET", "/denied.png", testutil.MockResponse{
+			StatusCode: http.StatusOK,
+			Body:       "<!DOCTYPE html><html><head><title>Access Denied</title></head></html>",
+		})
+
+		_, _, err := processor.DownloadImageBytes(context.Background(), testServer.URL+"/denied.png")
+
+		assert.ErrorContains(t, err, "HTML document instead of an image")
+	})
+
+	t.Run("Over the limit", func(t *testing.T) {
+		testServer.AddResponse("GET", "/huge.png", testutil.MockResponse{
+			StatusCode: http.StatusOK,
+			Body:       append(mockImageData, make([]byte, 1024)...),
+		})
+
+		limited := NewQuickProcessor().WithMaxDownloadBytes(int64(len(mockImageData))).Build()
+		_, _, err := limited.DownloadImageBytes(context.Background(), testServer.URL+"/huge.png")
+
+		assert.ErrorIs(t, err, ErrImageTooLarge)
+	})
+}
+
+// TestDownloadFirstImage tests falling back to alternate image urls
+func TestDownloadFirstImage(t *testing.T) {
+	testServer := testutil.NewTestServer()
+	defer testServer.Close()
+
+	testServer.AddResponse("GET", "/origin.png", testutil.MockResponse{
+		StatusCode: http.StatusOK,
+		Body:       testutil.CreateMockImage(),
+		Headers: map[string]string{
+			"Content-Type": "image/png",
+		},
+	})
+	missingUrl := testServer.URL + "/not-propagated.png"
+	originUrl := testServer.URL + "/origin.png"
+
+	processor := NewDefaultProcessor()
+	ctx := context.Background()
+
+	t.Run("Falls back when the primary url fails", func(t *testing.T) {
+		img, format, err := processor.DownloadFirstImage(ctx, []string{missingUrl, originUrl})
+
+		require.NoError(t, err)
+		assert.NotNil(t, img)
+		assert.Equal(t, "png", format)
+
+		base64Data, mimeType, err := processor.ProcessImageFromURLsForMCP(ctx, []string{missingUrl, originUrl})
+		require.NoError(t, err)
+		assert.NotEmpty(t, base64Data)
+		assert.Equal(t, "image/png", mimeType)
+	})
+
+	t.Run("Uses the primary url when it works", func(t *testing.T) {
+		_, _, err := processor.DownloadFirstImage(ctx, []string{originUrl, missingUrl})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("Reports every failure when all urls fail", func(t *testing.T) {
+		secondMissingUrl := testServer.URL + "/also-missing.png"
+		img, _, err := processor.DownloadFirstImage(ctx, []string{missingUrl, secondMissingUrl})
+
+		require.Error(t, err)
+		assert.Nil(t, img)
+		assert.Contains(t, err.Error(), "all 2 image urls failed")
+		assert.Contains(t, err.Error(), missingUrl+": unexpected status code: 404")
+		assert.Contains(t, err.Error(), secondMissingUrl+": unexpected status code: 404")
+	})
+
+	t.Run("A single url reports its error as is", func(t *testing.T) {
+		_, _, err := processor.DownloadFirstImage(ctx, []string{missingUrl})
+
+		assert.EqualError(t, err, "unexpected status code: 404")
+	})
+
+	t.Run("No urls", func(t *testing.T) {
+		_, _, err := processor.DownloadFirstImage(ctx, nil)
+
+		assert.Error(t, err)
+	})
+}
+
 // TestGetImageDimensions tests dimension extraction
 func TestGetImageDimensions(t *testing.T) {
 	testServer := testutil.NewTestServer()
@@ -372,3 +723,119 @@ func BenchmarkResizeImage(b *testing.B) {
 		_ = processor.ResizeImage(src)
 	}
 }
+
+func BenchmarkProcessImageFromURLForMCP(b *testing.B) {
+	// A noisy image, so the encoded buffers are realistically large
+	src := image.NewRGBA(image.Rect(0, 0, 512, 512))
+	for i := range src.Pix {
+		src.Pix[i] = uint8(i * 31)
+	}
+	var encoded bytes.Buffer
+	require.NoError(b, png.Encode(&encoded, src))
+
+	testServer := testutil.NewTestServer()
+	defer testServer.Close()
+	testServer.AddResponse("GET", "/bench-image.png", testutil.MockResponse{
+		StatusCode: http.StatusOK,
+		Body:       encoded.Bytes(),
+		Headers:    map[string]string{"Content-Type": "image/png"},
+	})
+
+	processor := NewProcessor(MCPConfig())
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := processor.ProcessImageFromURLForMCP(ctx, testServer.URL+"/bench-image.png"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// recordingTransport serves a mock image for every request and records the requests
+type recordingTransport struct {
+	requests []*http.Request
+}
+
+func (rt *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.requests = append(rt.requests, req)
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"image/png"}},
+		Body:       io.NopCloser(bytes.NewReader(testutil.CreateMockImage())),
+		Request:    req,
+	}, nil
+}
+
+// TestProcessor_Transport tests that downloads go through a configured transport
+func TestProcessor_Transport(t *testing.T) {
+	transport := &recordingTransport{}
+	processor := NewQuickProcessor().WithTransport(transport).Build()
+
+	// The host does not exist, so the image can only come from the transport
+	img, format, err := processor.DownloadImage(context.Background(), "http://images.invalid/fox.png")
+	require.NoError(t, err)
+	assert.Equal(t, "png", format)
+	assert.Equal(t, 1, img.Bounds().Dx())
+
+	require.Len(t, transport.requests, 1)
+	assert.Equal(t, "images.invalid", transport.requests[0].URL.Host)
+	assert.Equal(t, DefaultConfig().UserAgent, transport.requests[0].Header.Get("User-Agent"))
+
+	t.Run("Nil uses the default transport", func(t *testing.T) {
+		assert.Nil(t, NewDefaultProcessor().client.Transport)
+	})
+}
+
+// TestProcessImageFromURL_DimensionLimits tests that images outside the configured limits are rejected
+func TestProcessImageFromURL_DimensionLimits(t *testing.T) {
+	testServer := testutil.NewTestServer()
+	defer testServer.Close()
+
+	addImage := func(path string, width, height int) string {
+		var buf bytes.Buffer
+		require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height))))
+		testServer.AddResponse("GET", path, testutil.MockResponse{
+			StatusCode: http.StatusOK,
+			Body:       buf.Bytes(),
+			Headers:    map[string]string{"Content-Type": "image/png"},
+		})
+		return testServer.URL + path
+	}
+	smallURL := addImage("/small.png", 32, 24)
+	panoramaURL := addImage("/panorama.png", 1000, 100)
+	portraitURL := addImage("/portrait.png", 100, 1000)
+	squareURL := addImage("/square.png", 300, 300)
+
+	limited := NewQuickProcessor().WithMinSize(64, 64).WithMaxAspectRatio(4).Build()
+
+	t.Run("Too small", func(t *testing.T) {
+		_, err := limited.ProcessImageFromURL(context.Background(), smallURL)
+		require.ErrorIs(t, err, ErrInvalidDimensions)
+		assert.Contains(t, err.Error(), "32x24 pixels, smaller than the minimum of 64x64")
+	})
+
+	t.Run("Extreme panorama", func(t *testing.T) {
+		_, err := limited.ProcessImageFromURL(context.Background(), panoramaURL)
+		require.ErrorIs(t, err, ErrInvalidDimensions)
+		assert.Contains(t, err.Error(), "aspect ratio of 10:1, more extreme than the maximum of 4:1")
+	})
+
+	t.Run("Extreme portrait", func(t *testing.T) {
+		_, _, err := limited.ProcessImageFromURLForMCP(context.Background(), portraitURL)
+		assert.ErrorIs(t, err, ErrInvalidDimensions)
+	})
+
+	t.Run("Within the limits", func(t *testing.T) {
+		_, err := limited.ProcessImageFromURL(context.Background(), squareURL)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Unchecked by default", func(t *testing.T) {
+		for _, imageURL := range []string{smallURL, panoramaURL} {
+			_, err := NewDefaultProcessor().ProcessImageFromURL(context.Background(), imageURL)
+			assert.NoError(t, err)
+		}
+	})
+}
@@ -0,0 +1,75 @@
+package imageutil
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxErrorBodySnippet is the maximum number of body bytes included in a NonImageError
+const maxErrorBodySnippet = 200
+
+// NonImageError is returned when a URL served an HTML or JSON document instead of an image,
+// which typically happens when a CDN answers with an error page but an image content type.
+type NonImageError struct {
+	// Kind is the detected document type, "HTML" or "JSON"
+	Kind string
+	// Snippet is the start of the body, truncated to maxErrorBodySnippet bytes
+	Snippet string
+}
+
+func (e *NonImageError) Error() string {
+	return fmt.Sprintf("the URL returned a %s document instead of an image: %s", e.Kind, e.Snippet)
+}
+
+// CheckImageBody sniffs the leading bytes of a downloaded body and returns a
+// *NonImageError when it looks like HTML or JSON rather than image data.
+// It returns nil for anything else, leaving the final verdict to the decoder.
+func CheckImageBody(data []byte) error {
+	trimmed := bytes.TrimLeft(data, " \t\r\n\ufeff")
+	if len(trimmed) == 0 {
+		return nil
+	}
+
+	kind := ""
+	switch {
+	case trimmed[0] == '<' && looksLikeHTML(trimmed):
+		kind = "HTML"
+	case trimmed[0] == '{' || trimmed[0] == '[':
+		kind = "JSON"
+	default:
+		return nil
+	}
+
+	return &NonImageError{Kind: kind, Snippet: bodySnippet(trimmed)}
+}
+
+// looksLikeHTML reports whether a body starting with '<' is an HTML or XML document
+func looksLikeHTML(data []byte) bool {
+	head := strings.ToLower(string(data[:min(len(data), 64)]))
+	for _, prefix := range []string{"<!doctype html", "<html", "<head", "<body", "<?xml", "<!--", "<title", "<h1", "<div", "<p>", "<pre"} {
+		if strings.HasPrefix(head, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// bodySnippet returns the body as a single line, truncated to maxErrorBodySnippet bytes
+func bodySnippet(data []byte) string {
+	truncated := len(data) > maxErrorBodySnippet
+	if truncated {
+		data = data[:maxErrorBodySnippet]
+		// Do not cut a multi-byte character in half
+		for len(data) > 0 && !utf8.Valid(data) {
+			data = data[:len(data)-1]
+		}
+	}
+
+	snippet := strings.Join(strings.Fields(string(data)), " ")
+	if truncated {
+		snippet += "..."
+	}
+	return snippet
+}
@@ -0,0 +1,54 @@
+package imageutil
+
+import (
+	"errors"
+	"gaia-mcp-go/internal/testutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckImageBody(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		expectedKind string
+	}{
+		{name: "PNG data", body: string(testutil.CreateMockImage())},
+		{name: "Empty body", body: ""},
+		{name: "Plain text", body: "not an image"},
+		{name: "HTML doctype", body: "<!DOCTYPE html><html><body>403 Forbidden</body></html>", expectedKind: "HTML"},
+		{name: "HTML with leading whitespace", body: "\n\n  <html><head><title>Not Found</title></head></html>", expectedKind: "HTML"},
+		{name: "XML error", body: `<?xml version="1.0"?><Error><Code>AccessDenied</Code></Error>`, expectedKind: "HTML"},
+		{name: "JSON object", body: `{"error":"not found"}`, expectedKind: "JSON"},
+		{name: "JSON array", body: `[{"error":"not found"}]`, expectedKind: "JSON"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckImageBody([]byte(tt.body))
+			if tt.expectedKind == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			var nonImageErr *NonImageError
+			require.True(t, errors.As(err, &nonImageErr))
+			assert.Equal(t, tt.expectedKind, nonImageErr.Kind)
+			assert.Contains(t, err.Error(), tt.expectedKind+" document instead of an image")
+		})
+	}
+}
+
+func TestCheckImageBody_TruncatesSnippet(t *testing.T) {
+	body := "<html><body>" + strings.Repeat("error ", 100) + "</body></html>"
+
+	var nonImageErr *NonImageError
+	require.True(t, errors.As(CheckImageBody([]byte(body)), &nonImageErr))
+
+	assert.True(t, strings.HasPrefix(nonImageErr.Snippet, "<html><body>error error"))
+	assert.True(t, strings.HasSuffix(nonImageErr.Snippet, "..."))
+	assert.LessOrEqual(t, len(nonImageErr.Snippet), maxErrorBodySnippet+len("..."))
+}
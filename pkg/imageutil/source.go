@@ -0,0 +1,104 @@
+package imageutil
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// isDataURI reports whether the image reference is a data URI, such as
+// "data:image/png;base64,iVBORw0KGgo..."
+func isDataURI(ref string) bool {
+	return len(ref) >= len("data:") && strings.EqualFold(ref[:len("data:")], "data:")
+}
+
+// localImagePath returns the file path of a file:// URL. Bare paths are not
+// accepted, so a reference is only read from disk when it says so explicitly.
+// It returns false for every other reference.
+func localImagePath(ref string) (string, bool) {
+	if len(ref) < len("file://") || !strings.EqualFold(ref[:len("file://")], "file://") {
+		return "", false
+	}
+	parsed, err := url.Parse(ref)
+	if err != nil || parsed.Path == "" {
+		return "", false
+	}
+	return parsed.Path, true
+}
+
+// readImageFile reads the image file at path into buf, subject to the same
+// size limit as downloads
+func (p *Processor) readImageFile(path string, buf *bytes.Buffer) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening image file: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("reading image file: %w", err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("reading image file: %s is a directory", path)
+	}
+
+	// Fail fast on a file over the limit, as for an announced Content-Length
+	maxBytes := p.maxDownloadBytes()
+	if info.Size() > maxBytes {
+		return fmt.Errorf("%w: %d bytes exceeds the %d byte limit", ErrImageTooLarge, info.Size(), maxBytes)
+	}
+
+	// The file may grow while it is read, so the limit is checked again
+	buf.Grow(int(info.Size()) + bytes.MinRead)
+	if _, err := buf.ReadFrom(io.LimitReader(file, maxBytes+1)); err != nil {
+		return fmt.Errorf("reading image file: %w", err)
+	}
+	if int64(buf.Len()) > maxBytes {
+		return fmt.Errorf("%w: the file exceeds the %d byte limit", ErrImageTooLarge, maxBytes)
+	}
+	return nil
+}
+
+// decodeDataURI decodes the data of a data URI into buf, subject to the same
+// size limit as downloads. Both base64 and percent-encoded data are supported.
+func (p *Processor) decodeDataURI(ref string, buf *bytes.Buffer) error {
+	header, data, ok := strings.Cut(ref[len("data:"):], ",")
+	if !ok {
+		return fmt.Errorf("decoding data URI: missing ',' before the data")
+	}
+
+	maxBytes := p.maxDownloadBytes()
+	if !strings.HasSuffix(strings.ToLower(header), ";base64") {
+		decoded, err := url.PathUnescape(data)
+		if err != nil {
+			return fmt.Errorf("decoding data URI: %w", err)
+		}
+		if int64(len(decoded)) > maxBytes {
+			return fmt.Errorf("%w: %d bytes exceeds the %d byte limit", ErrImageTooLarge, len(decoded), maxBytes)
+		}
+		buf.WriteString(decoded)
+		return nil
+	}
+
+	// Check the size before decoding so a huge URI is not decoded in full
+	if size := int64(base64.StdEncoding.DecodedLen(len(data))); size > maxBytes+2 {
+		return fmt.Errorf("%w: %d bytes exceeds the %d byte limit", ErrImageTooLarge, size, maxBytes)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		// Some encoders leave out the padding
+		if decoded, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(data, "=")); err != nil {
+			return fmt.Errorf("decoding data URI: %w", err)
+		}
+	}
+	if int64(len(decoded)) > maxBytes {
+		return fmt.Errorf("%w: %d bytes exceeds the %d byte limit", ErrImageTooLarge, len(decoded), maxBytes)
+	}
+	buf.Write(decoded)
+	return nil
+}
@@ -0,0 +1,102 @@
+package imageutil
+
+import (
+	"context"
+	"encoding/base64"
+	"gaia-mcp-go/internal/testutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownloadImage_Sources(t *testing.T) {
+	mockImageData := testutil.CreateMockImage()
+
+	testServer := testutil.NewTestServer()
+	defer testServer.Close()
+	testServer.AddResponse("GET", "/test-image.png", testutil.MockResponse{
+		StatusCode: http.StatusOK,
+		Body:       mockImageData,
+		Headers:    map[string]string{"Content-Type": "image/png"},
+	})
+
+	path := filepath.Join(t.TempDir(), "test-image.png")
+	require.NoError(t, os.WriteFile(path, mockImageData, 0o600))
+
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{"file URL", "file://" + filepath.ToSlash(path)},
+		{"base64 data URI", "data:image/png;base64," + base64.StdEncoding.EncodeToString(mockImageData)},
+		{"HTTP URL", testServer.URL + "/test-image.png"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			processor := NewQuickProcessor().WithAllowLocalFiles().Build()
+			img, format, err := processor.DownloadImage(context.Background(), tt.url)
+			require.NoError(t, err)
+			assert.Equal(t, "png", format)
+			assert.Equal(t, 1, img.Bounds().Dx())
+			assert.Equal(t, 1, img.Bounds().Dy())
+		})
+	}
+}
+
+func TestDownloadImage_SourceErrors(t *testing.T) {
+	mockImageData := testutil.CreateMockImage()
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "image.png")
+	require.NoError(t, os.WriteFile(path, mockImageData, 0o600))
+	fileUrl := "file://" + filepath.ToSlash(path)
+
+	t.Run("Local files are disabled by default", func(t *testing.T) {
+		_, _, err := NewDefaultProcessor().DownloadImage(context.Background(), fileUrl)
+		assert.ErrorIs(t, err, ErrLocalFilesDisabled)
+	})
+
+	t.Run("Bare path is not read from disk", func(t *testing.T) {
+		processor := NewQuickProcessor().WithAllowLocalFiles().Build()
+		_, _, err := processor.DownloadImage(context.Background(), path)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported protocol scheme")
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		processor := NewQuickProcessor().WithAllowLocalFiles().Build()
+		_, _, err := processor.DownloadImage(context.Background(), "file://"+filepath.ToSlash(filepath.Join(dir, "missing.png")))
+		require.Error(t, err)
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("File over the size limit", func(t *testing.T) {
+		limited := NewQuickProcessor().WithAllowLocalFiles().WithMaxDownloadBytes(int64(len(mockImageData)) - 1).Build()
+		_, _, err := limited.DownloadImage(context.Background(), fileUrl)
+		assert.ErrorIs(t, err, ErrImageTooLarge)
+	})
+
+	t.Run("Data URI over the size limit", func(t *testing.T) {
+		uri := "data:image/png;base64," + base64.StdEncoding.EncodeToString(append(mockImageData, make([]byte, 64)...))
+
+		limited := NewQuickProcessor().WithMaxDownloadBytes(int64(len(mockImageData))).Build()
+		_, _, err := limited.DownloadImage(context.Background(), uri)
+		assert.ErrorIs(t, err, ErrImageTooLarge)
+	})
+
+	t.Run("Malformed data URI", func(t *testing.T) {
+		_, _, err := NewDefaultProcessor().DownloadImage(context.Background(), "data:image/png;base64")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "decoding data URI")
+	})
+
+	t.Run("Data URI that is not an image", func(t *testing.T) {
+		uri := "data:text/plain;base64," + base64.StdEncoding.EncodeToString([]byte("<html>Not Found</html>"))
+		_, _, err := NewDefaultProcessor().DownloadImage(context.Background(), uri)
+		assert.Error(t, err)
+	})
+}
@@ -4,7 +4,11 @@ import (
 	"context"
 	"fmt"
 	"image"
+	"image/color"
+	"net/http"
 	"time"
+
+	"golang.org/x/image/draw"
 )
 
 // QuickProcessConfig provides a simple configuration builder
@@ -44,6 +48,97 @@ func (q *QuickProcessConfig) WithJPEGQuality(quality int) *QuickProcessConfig {
 	return q
 }
 
+// WithCropAspectRatio center-crops images to the given width/height ratio before resizing
+func (q *QuickProcessConfig) WithCropAspectRatio(ratio float64) *QuickProcessConfig {
+	config := q.processor.config
+	config.CropAspectRatio = ratio
+	q.processor = NewProcessor(config)
+	return q
+}
+
+// WithStages sets the processing stages and their order. Passing no stages disables processing.
+func (q *QuickProcessConfig) WithStages(stages ...Stage) *QuickProcessConfig {
+	config := q.processor.config
+	config.Stages = append([]Stage{}, stages...)
+	q.processor = NewProcessor(config)
+	return q
+}
+
+// WithPreferredFormats sets the output encodings to try in order, e.g. "jpeg", "png"
+func (q *QuickProcessConfig) WithPreferredFormats(formats ...string) *QuickProcessConfig {
+	config := q.processor.config
+	config.PreferredFormats = append([]string{}, formats...)
+	q.processor = NewProcessor(config)
+	return q
+}
+
+// WithFlattenBackground sets the color that fills transparent areas when encoding to JPEG
+func (q *QuickProcessConfig) WithFlattenBackground(background color.Color) *QuickProcessConfig {
+	config := q.processor.config
+	config.FlattenBackground = background
+	q.processor = NewProcessor(config)
+	return q
+}
+
+// WithAllowLocalFiles lets file:// URLs read images from the local disk
+func (q *QuickProcessConfig) WithAllowLocalFiles() *QuickProcessConfig {
+	config := q.processor.config
+	config.AllowLocalFiles = true
+	q.processor = NewProcessor(config)
+	return q
+}
+
+// WithMaxDownloadBytes sets the largest image body that is downloaded
+func (q *QuickProcessConfig) WithMaxDownloadBytes(maxBytes int64) *QuickProcessConfig {
+	config := q.processor.config
+	config.MaxDownloadBytes = maxBytes
+	q.processor = NewProcessor(config)
+	return q
+}
+
+// WithResampler sets the interpolation used when resizing, e.g. draw.CatmullRom
+func (q *QuickProcessConfig) WithResampler(resampler draw.Interpolator) *QuickProcessConfig {
+	config := q.processor.config
+	config.Resampler = resampler
+	q.processor = NewProcessor(config)
+	return q
+}
+
+// WithMinSize rejects images smaller than width x height
+func (q *QuickProcessConfig) WithMinSize(width, height int) *QuickProcessConfig {
+	config := q.processor.config
+	config.MinWidth = width
+	config.MinHeight = height
+	q.processor = NewProcessor(config)
+	return q
+}
+
+// WithMaxAspectRatio rejects images whose longer side exceeds ratio times their shorter side
+func (q *QuickProcessConfig) WithMaxAspectRatio(ratio float64) *QuickProcessConfig {
+	config := q.processor.config
+	config.MaxAspectRatio = ratio
+	q.processor = NewProcessor(config)
+	return q
+}
+
+// WithTransport sets the HTTP transport used to download images, e.g. one with a proxy
+func (q *QuickProcessConfig) WithTransport(transport http.RoundTripper) *QuickProcessConfig {
+	config := q.processor.config
+	config.Transport = transport
+	q.processor = NewProcessor(config)
+	return q
+}
+
+// WithCache keeps up to size processed images in memory for ttl each, so the
+// same url processed again is served from memory
+func (q *QuickProcessConfig) WithCache(size int, ttl time.Duration) *QuickProcessConfig {
+	config := q.processor.config
+	config.CacheSize = size
+	config.CacheTTL = ttl
+	q.processor = NewProcessor(config)
+	return q
+}
+
 // Build returns the configured processor
 func (q *QuickProcessConfig) Build() *Processor {
 	return q.processor
@@ -104,30 +199,11 @@ func ValidateImageURL(ctx context.Context, imageURL string) error {
 	return nil
 }
 
-// ResizeImageToExactSize resizes an image to exact dimensions (may distort aspect ratio)
+// ResizeImageToExactSize resizes an image to exact dimensions (may distort aspect ratio).
+// It uses the default bilinear resampler; use Processor.ResizeImageToExactSize
+// to choose another.
 func ResizeImageToExactSize(img image.Image, width, height int) image.Image {
-	dst := image.NewRGBA(image.Rect(0, 0, width, height))
-	// Use bilinear scaling for smooth resizing
-	// Note: This will distort the image if aspect ratios don't match
-	srcBounds := img.Bounds()
-	for y := 0; y < height; y++ {
-		for x := 0; x < width; x++ {
-			// Map destination coordinates to source coordinates
-			srcX := int(float64(x) * float64(srcBounds.Dx()) / float64(width))
-			srcY := int(float64(y) * float64(srcBounds.Dy()) / float64(height))
-
-			// Ensure we don't go out of bounds
-			if srcX >= srcBounds.Dx() {
-				srcX = srcBounds.Dx() - 1
-			}
-			if srcY >= srcBounds.Dy() {
-				srcY = srcBounds.Dy() - 1
-			}
-
-			dst.Set(x, y, img.At(srcBounds.Min.X+srcX, srcBounds.Min.Y+srcY))
-		}
-	}
-	return dst
+	return NewDefaultProcessor().ResizeImageToExactSize(img, width, height)
 }
 
 // MCP-specific convenience functions that return pure base64 and MIME type
@@ -139,16 +215,43 @@ func ProcessImageDefaultForMCP(ctx context.Context, imageURL string) (base64Data
 	return processor.ProcessImageFromURLForMCP(ctx, imageURL)
 }
 
+// MCP response defaults used by MCPConfig and ProcessImageQuickForMCP.
+// Deployments can change them at startup to tune the size/quality tradeoff;
+// they must not be modified while images are being processed.
+var (
+	// MCPMaxWidth is the maximum width of images returned in MCP responses
+	MCPMaxWidth = 512
+	// MCPMaxHeight is the maximum height of images returned in MCP responses
+	MCPMaxHeight = 512
+	// MCPJPEGQuality is the JPEG quality (1-100) of images returned in MCP responses
+	MCPJPEGQuality = 70 // Lower quality for smaller file size while maintaining visual quality
+)
+
+// MCPConfig returns the conservative configuration used for MCP responses
+// Uses MCPMaxWidth x MCPMaxHeight (512x512) and MCPJPEGQuality (70) by default to stay under MCP size limits
+func MCPConfig() ProcessorConfig {
+	config := DefaultConfig()
+	config.MaxWidth = MCPMaxWidth
+	config.MaxHeight = MCPMaxHeight
+	config.JPEGQuality = MCPJPEGQuality
+	return config
+}
+
+// FullResolutionConfig returns a configuration that keeps the original image dimensions
+// Only suitable for transports that can carry large payloads
+func FullResolutionConfig() ProcessorConfig {
+	config := DefaultConfig()
+	// Set very large max dimensions to effectively disable resizing
+	config.MaxWidth = 100000  // 100k pixels width
+	config.MaxHeight = 100000 // 100k pixels height
+	return config
+}
+
 // ProcessImageQuickForMCP processes an image with MCP-optimized settings and returns data suitable for MCP
-// Uses smaller dimensions (512x512) and moderate quality (70) to stay under MCP size limits
+// Uses the MCP response defaults (512x512, quality 70 unless overridden) to stay under MCP size limits
 func ProcessImageQuickForMCP(ctx context.Context, imageURL string) (base64Data string, mimeType string, err error) {
 	// Use MCP-optimized configuration to avoid size limit errors
-	config := DefaultConfig()
-	config.MaxWidth = 512
-	config.MaxHeight = 512
-	config.JPEGQuality = 70 // Lower quality for smaller file size while maintaining visual quality
-
-	processor := NewProcessor(config)
+	processor := NewProcessor(MCPConfig())
 	return processor.ProcessImageFromURLForMCP(ctx, imageURL)
 }
 
@@ -188,11 +291,6 @@ func ProcessImageNoResize(ctx context.Context, imageURL string) (string, error)
 // ProcessImageNoResizeForMCP processes an image without resizing and returns data suitable for MCP
 // This is useful when you want to preserve original image dimensions for MCP responses
 func ProcessImageNoResizeForMCP(ctx context.Context, imageURL string) (base64Data string, mimeType string, err error) {
-	config := DefaultConfig()
-	// Set very large max dimensions to effectively disable resizing
-	config.MaxWidth = 100000  // 100k pixels width
-	config.MaxHeight = 100000 // 100k pixels height
-
-	processor := NewProcessor(config)
+	processor := NewProcessor(FullResolutionConfig())
 	return processor.ProcessImageFromURLForMCP(ctx, imageURL)
 }
@@ -0,0 +1,89 @@
+package imageutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"gaia-mcp-go/internal/testutil"
+	"image"
+	"image/png"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// overrideMCPDefaults sets the MCP response defaults for the duration of the test
+func overrideMCPDefaults(t *testing.T, width, height, quality int) {
+	previousWidth, previousHeight, previousQuality := MCPMaxWidth, MCPMaxHeight, MCPJPEGQuality
+	t.Cleanup(func() {
+		MCPMaxWidth, MCPMaxHeight, MCPJPEGQuality = previousWidth, previousHeight, previousQuality
+	})
+	MCPMaxWidth, MCPMaxHeight, MCPJPEGQuality = width, height, quality
+}
+
+func TestProcessImageQuickForMCP_Defaults(t *testing.T) {
+	testServer := testutil.NewTestServer()
+	defer testServer.Close()
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 800, 400))))
+	testServer.AddResponse("GET", "/wide.png", testutil.MockResponse{
+		StatusCode: http.StatusOK,
+		Body:       buf.String(),
+		Headers:    map[string]string{"Content-Type": "image/png"},
+	})
+
+	tests := []struct {
+		name           string
+		override       func(t *testing.T)
+		expectedWidth  int
+		expectedHeight int
+	}{
+		{
+			name:           "Built-in defaults",
+			override:       func(t *testing.T) {},
+			expectedWidth:  512,
+			expectedHeight: 256,
+		},
+		{
+			name:           "Overridden defaults",
+			override:       func(t *testing.T) { overrideMCPDefaults(t, 200, 200, 50) },
+			expectedWidth:  200,
+			expectedHeight: 100,
+		},
+		{
+			name:           "Overridden height limit",
+			override:       func(t *testing.T) { overrideMCPDefaults(t, 1024, 50, 70) },
+			expectedWidth:  100,
+			expectedHeight: 50,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.override(t)
+
+			data, _, err := ProcessImageQuickForMCP(context.Background(), testServer.URL+"/wide.png")
+			require.NoError(t, err)
+
+			decoded, err := base64.StdEncoding.DecodeString(data)
+			require.NoError(t, err)
+			config, _, err := image.DecodeConfig(bytes.NewReader(decoded))
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedWidth, config.Width)
+			assert.Equal(t, tt.expectedHeight, config.Height)
+		})
+	}
+
+	t.Run("MCPConfig reflects the overrides", func(t *testing.T) {
+		overrideMCPDefaults(t, 300, 200, 40)
+
+		config := MCPConfig()
+		assert.Equal(t, 300, config.MaxWidth)
+		assert.Equal(t, 200, config.MaxHeight)
+		assert.Equal(t, 40, config.JPEGQuality)
+	})
+}
@@ -0,0 +1,75 @@
+package imageutil
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// ZipMimeType is the MIME type of archives created by CreateZip
+const ZipMimeType = "application/zip"
+
+// ZipEntry is a file added to a zip archive
+type ZipEntry struct {
+	// Name is the file name inside the archive
+	Name string
+	// Data is the file content
+	Data []byte
+}
+
+// CreateZip packages the entries into an in-memory zip archive.
+//
+// Entries are stored without compression: image formats are already
+// compressed, so deflating them again costs time without saving space.
+// Entry names must be unique and non-empty.
+func CreateZip(entries []ZipEntry) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+
+	seen := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		if entry.Name == "" {
+			return nil, fmt.Errorf("zip entry name must not be empty")
+		}
+		if seen[entry.Name] {
+			return nil, fmt.Errorf("duplicate zip entry %q", entry.Name)
+		}
+		seen[entry.Name] = true
+
+		file, err := writer.CreateHeader(&zip.FileHeader{Name: entry.Name, Method: zip.Store})
+		if err != nil {
+			return nil, fmt.Errorf("creating zip entry %q: %w", entry.Name, err)
+		}
+		if _, err := file.Write(entry.Data); err != nil {
+			return nil, fmt.Errorf("writing zip entry %q: %w", entry.Name, err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("finalizing zip archive: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// ExtensionForMimeType returns the file extension, including the dot, for an image MIME type.
+// Unknown types get ".bin".
+func ExtensionForMimeType(mimeType string) string {
+	// Drop parameters such as "; charset=binary"
+	mimeType, _, _ = strings.Cut(mimeType, ";")
+
+	switch strings.ToLower(strings.TrimSpace(mimeType)) {
+	case "image/png":
+		return ".png"
+	case "image/jpeg", "image/jpg":
+		return ".jpg"
+	case "image/webp":
+		return ".webp"
+	case "image/gif":
+		return ".gif"
+	case "image/bmp":
+		return ".bmp"
+	default:
+		return ".bin"
+	}
+}
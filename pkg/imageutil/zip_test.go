@@ -0,0 +1,73 @@
+package imageutil
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateZip(t *testing.T) {
+	t.Run("Archive contains the entries", func(t *testing.T) {
+		data, err := CreateZip([]ZipEntry{
+			{Name: "image_1.png", Data: []byte("first")},
+			{Name: "image_2.jpg", Data: []byte("second")},
+		})
+		require.NoError(t, err)
+
+		reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		require.NoError(t, err)
+		require.Len(t, reader.File, 2)
+
+		contents := make(map[string]string)
+		for _, file := range reader.File {
+			assert.Equal(t, zip.Store, file.Method)
+
+			rc, err := file.Open()
+			require.NoError(t, err)
+			content, err := io.ReadAll(rc)
+			rc.Close()
+			require.NoError(t, err)
+			contents[file.Name] = string(content)
+		}
+		assert.Equal(t, map[string]string{"image_1.png": "first", "image_2.jpg": "second"}, contents)
+	})
+
+	t.Run("Empty archive", func(t *testing.T) {
+		data, err := CreateZip(nil)
+		require.NoError(t, err)
+
+		reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		require.NoError(t, err)
+		assert.Empty(t, reader.File)
+	})
+
+	t.Run("Invalid entry names", func(t *testing.T) {
+		_, err := CreateZip([]ZipEntry{{Name: "", Data: []byte("x")}})
+		assert.ErrorContains(t, err, "must not be empty")
+
+		_, err = CreateZip([]ZipEntry{{Name: "a.png"}, {Name: "a.png"}})
+		assert.ErrorContains(t, err, `duplicate zip entry "a.png"`)
+	})
+}
+
+func TestExtensionForMimeType(t *testing.T) {
+	tests := map[string]string{
+		"image/png":                ".png",
+		"image/jpeg":               ".jpg",
+		"IMAGE/JPEG":               ".jpg",
+		"image/webp":               ".webp",
+		"image/gif":                ".gif",
+		"image/bmp":                ".bmp",
+		"image/png; charset=utf-8": ".png",
+		"application/octet-stream": ".bin",
+		"":                         ".bin",
+	}
+
+	for mimeType, expected := range tests {
+		assert.Equal(t, expected, ExtensionForMimeType(mimeType), mimeType)
+	}
+}
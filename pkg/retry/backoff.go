@@ -0,0 +1,107 @@
+// Package retry provides a small backoff helper shared by the retry and polling loops.
+package retry
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Strategy controls how the delay grows between attempts
+type Strategy string
+
+const (
+	// StrategyFixed waits Initial before every attempt
+	StrategyFixed Strategy = "fixed"
+	// StrategyLinear waits Initial, 2*Initial, 3*Initial, ...
+	StrategyLinear Strategy = "linear"
+	// StrategyExponential multiplies the delay by Multiplier after every attempt
+	StrategyExponential Strategy = "exponential"
+)
+
+// DefaultMultiplier is the exponential growth factor used when Backoff.Multiplier is not set
+const DefaultMultiplier = 2.0
+
+// Backoff computes the delay before a retry.
+//
+// The zero value of every field except Initial is usable: Strategy defaults to
+// StrategyFixed, Max of zero disables the cap and Multiplier defaults to
+// DefaultMultiplier.
+type Backoff struct {
+	// Strategy is how the delay grows between attempts
+	Strategy Strategy
+	// Initial is the delay after the first attempt (and the step for linear backoff)
+	Initial time.Duration
+	// Max caps the delay. Zero means no cap.
+	Max time.Duration
+	// Multiplier is the growth factor for exponential backoff
+	Multiplier float64
+	// Jitter randomly shortens each delay by up to this fraction (0 to 1),
+	// so that many clients retrying at once spread out
+	Jitter float64
+	// Rand returns a random number in [0, 1). Defaults to math/rand; set it in tests.
+	Rand func() float64
+}
+
+// Delay returns how long to wait after the given 1-based attempt
+func (b Backoff) Delay(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+
+	var delay time.Duration
+	switch b.Strategy {
+	case StrategyLinear:
+		delay = b.Initial * time.Duration(attempt)
+	case StrategyExponential:
+		multiplier := b.Multiplier
+		if multiplier <= 1 {
+			multiplier = DefaultMultiplier
+		}
+		delay = time.Duration(float64(b.Initial) * math.Pow(multiplier, float64(attempt-1)))
+		// Guard against overflow for large attempt numbers
+		if delay < 0 {
+			delay = time.Duration(math.MaxInt64)
+		}
+	default:
+		delay = b.Initial
+	}
+
+	if b.Max > 0 && delay > b.Max {
+		delay = b.Max
+	}
+
+	if b.Jitter > 0 {
+		jitter := math.Min(b.Jitter, 1)
+		random := rand.Float64
+		if b.Rand != nil {
+			random = b.Rand
+		}
+		delay -= time.Duration(float64(delay) * jitter * random())
+	}
+
+	return delay
+}
+
+// Wait sleeps for the delay of the given 1-based attempt, or until ctx is done
+func (b Backoff) Wait(ctx context.Context, attempt int) error {
+	return Sleep(ctx, b.Delay(attempt))
+}
+
+// Sleep waits for d, returning early with the context error if ctx is done first
+func Sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
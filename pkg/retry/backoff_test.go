@@ -0,0 +1,113 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoff_Delay(t *testing.T) {
+	tests := []struct {
+		name     string
+		backoff  Backoff
+		expected []time.Duration
+	}{
+		{
+			name:     "Fixed",
+			backoff:  Backoff{Strategy: StrategyFixed, Initial: time.Second},
+			expected: []time.Duration{time.Second, time.Second, time.Second},
+		},
+		{
+			name:     "Zero strategy is fixed",
+			backoff:  Backoff{Initial: time.Second},
+			expected: []time.Duration{time.Second, time.Second},
+		},
+		{
+			name:     "Linear",
+			backoff:  Backoff{Strategy: StrategyLinear, Initial: time.Second},
+			expected: []time.Duration{time.Second, 2 * time.Second, 3 * time.Second},
+		},
+		{
+			name:     "Linear with cap",
+			backoff:  Backoff{Strategy: StrategyLinear, Initial: time.Second, Max: 2 * time.Second},
+			expected: []time.Duration{time.Second, 2 * time.Second, 2 * time.Second},
+		},
+		{
+			name:     "Exponential",
+			backoff:  Backoff{Strategy: StrategyExponential, Initial: time.Second},
+			expected: []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second},
+		},
+		{
+			name:     "Exponential with multiplier and cap",
+			backoff:  Backoff{Strategy: StrategyExponential, Initial: time.Second, Multiplier: 3, Max: 20 * time.Second},
+			expected: []time.Duration{time.Second, 3 * time.Second, 9 * time.Second, 20 * time.Second},
+		},
+		{
+			name:     "Jitter shortens the delay",
+			backoff:  Backoff{Strategy: StrategyFixed, Initial: time.Second, Jitter: 0.5, Rand: func() float64 { return 0.5 }},
+			expected: []time.Duration{750 * time.Millisecond, 750 * time.Millisecond},
+		},
+		{
+			name:     "Jitter applies after the cap",
+			backoff:  Backoff{Strategy: StrategyExponential, Initial: time.Second, Max: 2 * time.Second, Jitter: 1, Rand: func() float64 { return 0.25 }},
+			expected: []time.Duration{750 * time.Millisecond, 1500 * time.Millisecond, 1500 * time.Millisecond},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i, expected := range tt.expected {
+				assert.Equal(t, expected, tt.backoff.Delay(i+1), "attempt %d", i+1)
+			}
+		})
+	}
+
+	t.Run("Attempts below one are treated as the first", func(t *testing.T) {
+		backoff := Backoff{Strategy: StrategyLinear, Initial: time.Second}
+		assert.Equal(t, time.Second, backoff.Delay(0))
+	})
+
+	t.Run("Large exponents do not overflow", func(t *testing.T) {
+		backoff := Backoff{Strategy: StrategyExponential, Initial: time.Second, Max: time.Minute}
+		assert.Equal(t, time.Minute, backoff.Delay(200))
+	})
+
+	t.Run("Random jitter stays within bounds", func(t *testing.T) {
+		backoff := Backoff{Strategy: StrategyFixed, Initial: time.Second, Jitter: 0.2}
+		for i := 0; i < 100; i++ {
+			delay := backoff.Delay(1)
+			assert.GreaterOrEqual(t, delay, 800*time.Millisecond)
+			assert.LessOrEqual(t, delay, time.Second)
+		}
+	})
+}
+
+func TestSleep(t *testing.T) {
+	t.Run("Waits for the duration", func(t *testing.T) {
+		start := time.Now()
+		assert.NoError(t, Sleep(context.Background(), 20*time.Millisecond))
+		assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	})
+
+	t.Run("Returns early when the context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(10*time.Millisecond, cancel)
+
+		start := time.Now()
+		err := Sleep(ctx, time.Minute)
+
+		assert.True(t, errors.Is(err, context.Canceled))
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("Already cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		assert.True(t, errors.Is(Sleep(ctx, 0), context.Canceled))
+		assert.True(t, errors.Is(Backoff{Initial: time.Minute}.Wait(ctx, 1), context.Canceled))
+	})
+}
@@ -0,0 +1,55 @@
+package shared
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Dimensions returns the width and height in pixels of an image with this
+// aspect ratio whose longer edge is longEdge. Both are rounded to even
+// numbers, the long edge down so the image still fits.
+//
+// The ratio is parsed as "width:height", so ratios other than the defined
+// constants work too. It returns an error when the ratio is malformed or
+// longEdge is less than 2.
+func (r AspectRatio) Dimensions(longEdge int) (width, height int, err error) {
+	ratioWidth, ratioHeight, err := r.parse()
+	if err != nil {
+		return 0, 0, err
+	}
+	if longEdge < 2 {
+		return 0, 0, fmt.Errorf("long edge must be at least 2 pixels, got %d", longEdge)
+	}
+
+	long := longEdge - longEdge%2
+	short := roundToEven(float64(long) * math.Min(ratioWidth, ratioHeight) / math.Max(ratioWidth, ratioHeight))
+	short = max(short, 2)
+
+	if ratioWidth >= ratioHeight {
+		return long, short, nil
+	}
+	return short, long, nil
+}
+
+// parse splits the ratio into its positive width and height terms
+func (r AspectRatio) parse() (float64, float64, error) {
+	widthText, heightText, ok := strings.Cut(string(r), ":")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid aspect ratio %q: expected width:height", r)
+	}
+
+	width, widthErr := strconv.ParseFloat(strings.TrimSpace(widthText), 64)
+	height, heightErr := strconv.ParseFloat(strings.TrimSpace(heightText), 64)
+	if widthErr != nil || heightErr != nil || !(width > 0) || !(height > 0) || math.IsInf(width, 0) || math.IsInf(height, 0) {
+		return 0, 0, fmt.Errorf("invalid aspect ratio %q: width and height must be positive numbers", r)
+	}
+
+	return width, height, nil
+}
+
+// roundToEven rounds v to the nearest even integer
+func roundToEven(v float64) int {
+	return int(math.Round(v/2)) * 2
+}
@@ -0,0 +1,64 @@
+package shared
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAspectRatio_Dimensions(t *testing.T) {
+	tests := []struct {
+		ratio          AspectRatio
+		longEdge       int
+		expectedWidth  int
+		expectedHeight int
+	}{
+		{AspectRatio1_1, 1024, 1024, 1024},
+		{AspectRatio3_2, 1024, 1024, 682},
+		{AspectRatio2_3, 1024, 682, 1024},
+		{AspectRatio16_9, 1024, 1024, 576},
+		{AspectRatio9_16, 1024, 576, 1024},
+		{AspectRatio16_9, 1920, 1920, 1080},
+		{AspectRatio16_9, 1025, 1024, 576},
+		{AspectRatio("4:3"), 1024, 1024, 768},
+		{AspectRatio("21:9"), 100, 100, 42},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.ratio), func(t *testing.T) {
+			width, height, err := tt.ratio.Dimensions(tt.longEdge)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedWidth, width)
+			assert.Equal(t, tt.expectedHeight, height)
+			assert.Zero(t, width%2)
+			assert.Zero(t, height%2)
+		})
+	}
+}
+
+func TestAspectRatio_Dimensions_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		ratio    AspectRatio
+		longEdge int
+		expected string
+	}{
+		{"Missing separator", "16x9", 1024, `invalid aspect ratio "16x9": expected width:height`},
+		{"Empty", "", 1024, `invalid aspect ratio "": expected width:height`},
+		{"Not a number", "wide:9", 1024, "width and height must be positive numbers"},
+		{"Zero term", "16:0", 1024, "width and height must be positive numbers"},
+		{"Negative term", "-16:9", 1024, "width and height must be positive numbers"},
+		{"Long edge too small", AspectRatio16_9, 1, "long edge must be at least 2 pixels"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := tt.ratio.Dimensions(tt.longEdge)
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
@@ -3,5 +3,6 @@ package shared
 const (
 	HOMEPAGE_URL      = "https://protogaia.com"
 	BASE_API_URL      = "https://api.protogaia.com"
+	CDN_URL           = "https://cdn.protogaia.com"
 	UPLOAD_CHUNK_SIZE = 1024 * 1024 * 10 // 10MB chunks
 )
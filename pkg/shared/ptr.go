@@ -0,0 +1,15 @@
+package shared
+
+// Ptr returns a pointer to v, for optional fields set from constants or
+// literals, e.g. Ptr(AspectRatio16_9)
+func Ptr[T any](v T) *T {
+	return &v
+}
+
+// Deref returns the value p points to, or def when p is nil
+func Deref[T any](p *T, def T) T {
+	if p == nil {
+		return def
+	}
+	return *p
+}
@@ -0,0 +1,48 @@
+package shared
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPtr(t *testing.T) {
+	t.Run("Primitive", func(t *testing.T) {
+		p := Ptr(42)
+		assert.Equal(t, 42, *p)
+
+		// Every call returns a new pointer
+		assert.NotSame(t, p, Ptr(42))
+	})
+
+	t.Run("Enum", func(t *testing.T) {
+		p := Ptr(AspectRatio16_9)
+		assert.Equal(t, AspectRatio16_9, *p)
+	})
+
+	t.Run("Slice", func(t *testing.T) {
+		p := Ptr([]float64{0.5, 1})
+		assert.Equal(t, []float64{0.5, 1}, *p)
+	})
+}
+
+func TestDeref(t *testing.T) {
+	tests := []struct {
+		name     string
+		p        *PromptStyle
+		expected PromptStyle
+	}{
+		{"Set", Ptr(PromptStyleAnime), PromptStyleAnime},
+		{"Set to the zero value", Ptr(PromptStyle("")), ""},
+		{"Nil", nil, PromptStyleBase},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Deref(tt.p, PromptStyleBase))
+		})
+	}
+
+	assert.Equal(t, "fallback", Deref[string](nil, "fallback"))
+	assert.Equal(t, 0.5, Deref(Ptr(0.5), 1))
+}
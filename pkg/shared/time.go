@@ -0,0 +1,55 @@
+package shared
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timeLayouts are the timestamp formats ParseTimeString tries, in order
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// ParseTimeString parses a timestamp as returned by the Gaia API, such as the
+// CreatedAt fields. All-digit strings are Unix epochs, in seconds (10 digits)
+// or milliseconds (13 digits); anything else is tried against timeLayouts.
+func ParseTimeString(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+
+	if isDigits(value) && (len(value) == 10 || len(value) == 13) {
+		epoch, err := strconv.ParseInt(value, 10, 64)
+		if err == nil {
+			if len(value) == 13 {
+				return time.UnixMilli(epoch).UTC(), nil
+			}
+			return time.Unix(epoch, 0).UTC(), nil
+		}
+	}
+
+	for _, layout := range timeLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid timestamp %q", value)
+}
+
+// isDigits reports whether s is a non-empty string of ASCII digits
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
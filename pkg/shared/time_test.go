@@ -0,0 +1,43 @@
+package shared
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTimeString(t *testing.T) {
+	expected := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Time
+	}{
+		{"Unix seconds", "1714979289", expected},
+		{"Unix milliseconds", "1714979289123", expected.Add(123 * time.Millisecond)},
+		{"RFC3339", "2024-05-06T07:08:09Z", expected},
+		{"RFC3339 with fraction and offset", "2024-05-06T09:08:09.5+02:00", expected.Add(500 * time.Millisecond)},
+		{"Without a zone", "2024-05-06T07:08:09", expected},
+		{"Date only", "2024-05-06", time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC)},
+		{"Surrounding spaces", " 1714979289 ", expected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := ParseTimeString(tt.value)
+
+			require.NoError(t, err)
+			assert.True(t, tt.expected.Equal(parsed), "expected %s, got %s", tt.expected, parsed)
+		})
+	}
+
+	for _, value := range []string{"", "yesterday", "12345", "2024-13-01"} {
+		t.Run("Rejects "+value, func(t *testing.T) {
+			_, err := ParseTimeString(value)
+			assert.ErrorContains(t, err, "invalid timestamp")
+		})
+	}
+}
@@ -1,5 +1,7 @@
 package shared
 
+import "sort"
+
 type RecipeTaskStatus string
 type RecipeType string
 type PromptStyle string
@@ -88,6 +90,7 @@ const (
 
 type PromptStyleMap struct {
 	promptStyles map[PromptStyle]string
+	descriptions map[PromptStyle]string
 }
 
 // RecipeTaskStatusMap provides a mapping for RecipeTaskStatus types
@@ -146,6 +149,30 @@ func GetPromptStyleMap() *PromptStyleMap {
 			PromptStyleCraftClay:          "craft clay",
 			PromptStyleGameMinecraft:      "game-minecraft",
 		},
+		descriptions: map[PromptStyle]string{
+			PromptStyleBase:               "No added styling; the prompt is used as written",
+			PromptStyleEnhance:            "Sharper detail, richer lighting and color on top of the prompt",
+			PromptStyleAnime:              "Japanese anime illustration with clean lines and cel shading",
+			PromptStylePhotographic:       "Realistic photograph with natural lighting and depth of field",
+			PromptStyleCinematic:          "Film still with dramatic lighting, wide framing and color grading",
+			PromptStyleAnalogFilm:         "Vintage film photo with grain, faded tones and light leaks",
+			PromptStyleDigitalArt:         "Polished digital painting with vivid colors",
+			PromptStyleFantasyArt:         "Epic fantasy illustration with magical, painterly atmosphere",
+			PromptStyleLineArt:            "Clean black line drawing with little or no shading",
+			PromptStylePixelArt:           "Retro low-resolution pixel art like classic video games",
+			PromptStyleArtstyleWatercolor: "Soft watercolor painting with bleeding pigments on paper",
+			PromptStyleComicBook:          "Comic book panel with bold ink outlines and halftone colors",
+			PromptStyleNeonpunk:           "Cyberpunk scene lit by vibrant neon pinks, blues and purples",
+			PromptStyle3DModel:            "Clean 3D render with smooth materials and studio lighting",
+			PromptStyleMiscFairyTale:      "Whimsical storybook illustration with a magical mood",
+			PromptStyleMiscGothic:         "Dark gothic look with moody shadows and ornate architecture",
+			PromptStylePhotoLongExposure:  "Long exposure photo with light trails and motion blur",
+			PromptStylePhotoTiltShift:     "Tilt-shift photo that makes scenes look like miniatures",
+			PromptStyleLowpoly:            "Low-poly 3D art built from flat-shaded polygons",
+			PromptStyleOrigami:            "Folded paper origami figures and scenery",
+			PromptStyleCraftClay:          "Handmade clay sculpture look, like stop-motion animation",
+			PromptStyleGameMinecraft:      "Blocky voxel world in the style of Minecraft",
+		},
 	}
 }
 
@@ -241,8 +268,23 @@ func (m *PromptStyleMap) Get(promptStyle PromptStyle) string {
 	return m.promptStyles[promptStyle]
 }
 
+// Description returns a short description of how the prompt style looks
+func (m *PromptStyleMap) Description(promptStyle PromptStyle) string {
+	return m.descriptions[promptStyle]
+}
+
+// Styles returns every prompt style sorted by name
+func (m *PromptStyleMap) Styles() []PromptStyle {
+	styles := make([]PromptStyle, 0, len(m.promptStyles))
+	for promptStyle := range m.promptStyles {
+		styles = append(styles, promptStyle)
+	}
+	sort.Slice(styles, func(i, j int) bool { return styles[i] < styles[j] })
+	return styles
+}
+
 func (m *PromptStyleMap) ToStrings() []string {
-	strings := make([]string, len(m.promptStyles))
+	strings := make([]string, 0, len(m.promptStyles))
 	for promptStyle := range m.promptStyles {
 		strings = append(strings, string(promptStyle))
 	}
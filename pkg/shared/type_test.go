@@ -99,6 +99,19 @@ func TestPromptStyleMap(t *testing.T) {
 		assert.Contains(t, strings, "base")
 		assert.Contains(t, strings, "enhance")
 		assert.Contains(t, strings, "anime")
+		assert.NotContains(t, strings, "")
+	})
+
+	t.Run("Test Styles and Description", func(t *testing.T) {
+		styleMap := GetPromptStyleMap()
+		styles := styleMap.Styles()
+
+		assert.Len(t, styles, 22)
+		assert.IsIncreasing(t, styles)
+		for _, style := range styles {
+			assert.NotEmpty(t, styleMap.Description(style), "style %q should have a description", style)
+		}
+		assert.Empty(t, styleMap.Description(PromptStyle("unknown")))
 	})
 }
 
@@ -216,6 +229,7 @@ func TestConstants(t *testing.T) {
 	t.Run("Test exported constants", func(t *testing.T) {
 		assert.Equal(t, "https://protogaia.com", HOMEPAGE_URL)
 		assert.Equal(t, "https://api.protogaia.com", BASE_API_URL)
+		assert.Equal(t, "https://cdn.protogaia.com", CDN_URL)
 		assert.Equal(t, 1024*1024*10, UPLOAD_CHUNK_SIZE, "Upload chunk size should be 10MB")
 	})
 }
@@ -0,0 +1,95 @@
+package shared
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrInvalidValue is matched (via errors.Is) by every InvalidValueError
+var ErrInvalidValue = errors.New("invalid value")
+
+// InvalidValueError is returned when a string is not a legal value of an enum type
+type InvalidValueError struct {
+	// Type is the human-readable name of the enum, e.g. "aspect ratio"
+	Type string
+	// Value is the rejected input
+	Value string
+	// Valid lists the accepted values, sorted
+	Valid []string
+}
+
+// Error implements the error interface for InvalidValueError
+func (e *InvalidValueError) Error() string {
+	return fmt.Sprintf("invalid %s %q: must be one of %s", e.Type, e.Value, strings.Join(e.Valid, ", "))
+}
+
+// Is reports whether the target is ErrInvalidValue
+func (e *InvalidValueError) Is(target error) bool {
+	return target == ErrInvalidValue
+}
+
+// IsValid reports whether the aspect ratio is supported
+func (r AspectRatio) IsValid() bool {
+	_, ok := GetAspectRatioMap().aspectRatios[r]
+	return ok
+}
+
+// IsValid reports whether the prompt style is supported
+func (s PromptStyle) IsValid() bool {
+	_, ok := GetPromptStyleMap().promptStyles[s]
+	return ok
+}
+
+// IsValid reports whether the queue type is known
+func (q QueueType) IsValid() bool {
+	_, ok := GetQueueTypeMap().queueTypes[q]
+	return ok
+}
+
+// IsValid reports whether the recipe ID is known
+func (r RecipeId) IsValid() bool {
+	_, ok := GetRecipeIdMap().recipeIds[r]
+	return ok
+}
+
+// ParseAspectRatio returns the aspect ratio named by value, or an
+// InvalidValueError listing the supported ones
+func ParseAspectRatio(value string) (AspectRatio, error) {
+	return parseEnum("aspect ratio", value, GetAspectRatioMap().aspectRatios)
+}
+
+// ParsePromptStyle returns the prompt style named by value, or an
+// InvalidValueError listing the supported ones
+func ParsePromptStyle(value string) (PromptStyle, error) {
+	return parseEnum("prompt style", value, GetPromptStyleMap().promptStyles)
+}
+
+// ParseQueueType returns the queue type named by value, or an
+// InvalidValueError listing the known ones
+func ParseQueueType(value string) (QueueType, error) {
+	return parseEnum("queue type", value, GetQueueTypeMap().queueTypes)
+}
+
+// ParseRecipeId returns the recipe ID named by value, or an
+// InvalidValueError listing the known ones
+func ParseRecipeId(value string) (RecipeId, error) {
+	return parseEnum("recipe ID", value, GetRecipeIdMap().recipeIds)
+}
+
+// parseEnum looks value up in the keys of values. Surrounding whitespace is ignored.
+func parseEnum[T ~string](typeName string, value string, values map[T]string) (T, error) {
+	trimmed := T(strings.TrimSpace(value))
+	if _, ok := values[trimmed]; ok {
+		return trimmed, nil
+	}
+
+	valid := make([]string, 0, len(values))
+	for v := range values {
+		valid = append(valid, string(v))
+	}
+	sort.Strings(valid)
+
+	return "", &InvalidValueError{Type: typeName, Value: value, Valid: valid}
+}
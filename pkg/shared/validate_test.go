@@ -0,0 +1,87 @@
+package shared
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnumValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		isValid  func(string) bool
+		parse    func(string) (string, error)
+		expected string
+	}{
+		{"Aspect ratio", "16:9", aspectRatioValid, parseAspectRatio, "16:9"},
+		{"Aspect ratio with spaces", " 9:16 ", aspectRatioValid, parseAspectRatio, "9:16"},
+		{"Invalid aspect ratio", "4:3", aspectRatioValid, parseAspectRatio, ""},
+		{"Empty aspect ratio", "", aspectRatioValid, parseAspectRatio, ""},
+		{"Prompt style", "analog film", promptStyleValid, parsePromptStyle, "analog film"},
+		{"Invalid prompt style", "vaporwave", promptStyleValid, parsePromptStyle, ""},
+		{"Empty prompt style", "", promptStyleValid, parsePromptStyle, ""},
+		{"Queue type", "fast", queueTypeValid, parseQueueType, "fast"},
+		{"Invalid queue type", "FAST", queueTypeValid, parseQueueType, ""},
+		{"Empty queue type", "", queueTypeValid, parseQueueType, ""},
+		{"Recipe ID", "upscaler", recipeIdValid, parseRecipeId, "upscaler"},
+		{"Invalid recipe ID", "outpaint", recipeIdValid, parseRecipeId, ""},
+		{"Empty recipe ID", "", recipeIdValid, parseRecipeId, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := tt.parse(tt.value)
+
+			if tt.expected == "" {
+				assert.False(t, tt.isValid(tt.value))
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, ErrInvalidValue))
+
+				var invalidErr *InvalidValueError
+				require.ErrorAs(t, err, &invalidErr)
+				assert.Equal(t, tt.value, invalidErr.Value)
+				assert.NotEmpty(t, invalidErr.Valid)
+				assert.IsNonDecreasing(t, invalidErr.Valid)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, parsed)
+			assert.True(t, tt.isValid(tt.expected))
+		})
+	}
+}
+
+func TestInvalidValueError(t *testing.T) {
+	_, err := ParseAspectRatio("4:3")
+
+	assert.EqualError(t, err, `invalid aspect ratio "4:3": must be one of 16:9, 1:1, 2:3, 3:2, 9:16`)
+}
+
+func aspectRatioValid(v string) bool { return AspectRatio(v).IsValid() }
+func promptStyleValid(v string) bool { return PromptStyle(v).IsValid() }
+func queueTypeValid(v string) bool   { return QueueType(v).IsValid() }
+func recipeIdValid(v string) bool    { return RecipeId(v).IsValid() }
+
+func parseAspectRatio(v string) (string, error) {
+	parsed, err := ParseAspectRatio(v)
+	return string(parsed), err
+}
+
+func parsePromptStyle(v string) (string, error) {
+	parsed, err := ParsePromptStyle(v)
+	return string(parsed), err
+}
+
+func parseQueueType(v string) (string, error) {
+	parsed, err := ParseQueueType(v)
+	return string(parsed), err
+}
+
+func parseRecipeId(v string) (string, error) {
+	parsed, err := ParseRecipeId(v)
+	return string(parsed), err
+}
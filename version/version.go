@@ -103,6 +103,84 @@ func (s SemVer) IsStable() bool {
 	return s.Major >= 1 && !s.IsPreRelease()
 }
 
+// Compare returns -1, 0 or 1 when s is lower than, equal to or higher than
+// other, following semver precedence: major, minor and patch compare
+// numerically, a pre-release is lower than its release, and build metadata
+// is ignored.
+func (s SemVer) Compare(other SemVer) int {
+	if c := compareInt(s.Major, other.Major); c != 0 {
+		return c
+	}
+	if c := compareInt(s.Minor, other.Minor); c != 0 {
+		return c
+	}
+	if c := compareInt(s.Patch, other.Patch); c != 0 {
+		return c
+	}
+	return comparePreRelease(s.PreRelease, other.PreRelease)
+}
+
+// Less reports whether s has lower precedence than other, for use with sort.Slice
+func (s SemVer) Less(other SemVer) bool {
+	return s.Compare(other) < 0
+}
+
+// comparePreRelease compares pre-release strings identifier by identifier.
+// Numeric identifiers compare numerically and rank below alphanumeric ones,
+// and a shorter list ranks below a longer one it prefixes.
+func comparePreRelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1 // A release outranks its pre-releases
+	case b == "":
+		return -1
+	}
+
+	aIds := strings.Split(a, ".")
+	bIds := strings.Split(b, ".")
+	for i := 0; i < len(aIds) && i < len(bIds); i++ {
+		if c := comparePreReleaseIdentifier(aIds[i], bIds[i]); c != 0 {
+			return c
+		}
+	}
+	return compareInt(len(aIds), len(bIds))
+}
+
+// comparePreReleaseIdentifier compares a single dot-separated pre-release identifier
+func comparePreReleaseIdentifier(a, b string) int {
+	aNum, aErr := strconv.ParseUint(a, 10, 64)
+	bNum, bErr := strconv.ParseUint(b, 10, 64)
+
+	switch {
+	case aErr == nil && bErr == nil:
+		switch {
+		case aNum < bNum:
+			return -1
+		case aNum > bNum:
+			return 1
+		}
+		return 0
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// compareInt returns -1, 0 or 1 when a is lower than, equal to or higher than b
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
 // Get returns comprehensive version information
 func Get() Info {
 	semver, err := ParseSemVer(Version)
@@ -2,6 +2,7 @@ package version
 
 import (
 	"runtime"
+	"sort"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -465,3 +466,73 @@ func BenchmarkGet(b *testing.B) {
 		_ = Get()
 	}
 }
+
+// TestSemVerCompare tests semantic version precedence
+func TestSemVerCompare(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        string
+		b        string
+		expected int
+	}{
+		{"Equal", "1.2.3", "1.2.3", 0},
+		{"Major", "2.0.0", "1.9.9", 1},
+		{"Minor", "1.2.0", "1.10.0", -1},
+		{"Patch", "1.2.10", "1.2.9", 1},
+		{"Pre-release is lower than release", "1.0.0-alpha", "1.0.0", -1},
+		{"Release is higher than pre-release", "1.0.0", "1.0.0-rc.1", 1},
+		{"Pre-release of a higher version", "1.1.0-alpha", "1.0.0", 1},
+		{"Alphanumeric identifiers compare lexically", "1.0.0-alpha", "1.0.0-beta", -1},
+		{"Numeric identifiers compare numerically", "1.0.0-beta.2", "1.0.0-beta.11", -1},
+		{"Numeric is lower than alphanumeric", "1.0.0-1", "1.0.0-alpha", -1},
+		{"Fewer identifiers is lower", "1.0.0-alpha", "1.0.0-alpha.1", -1},
+		{"Equal pre-release", "1.0.0-rc.1", "1.0.0-rc.1", 0},
+		{"Build metadata is ignored", "1.0.0+build.1", "1.0.0+build.2", 0},
+		{"v prefix is ignored", "v1.2.3", "1.2.3", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := ParseSemVer(tt.a)
+			require.NoError(t, err)
+			b, err := ParseSemVer(tt.b)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, a.Compare(b))
+			assert.Equal(t, -tt.expected, b.Compare(a))
+			assert.Equal(t, tt.expected < 0, a.Less(b))
+		})
+	}
+}
+
+// TestSemVerSort tests sorting versions by precedence, using the semver spec's example order
+func TestSemVerSort(t *testing.T) {
+	expected := []string{
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.0.1",
+		"1.1.0",
+		"2.0.0",
+	}
+
+	versions := make([]SemVer, len(expected))
+	for i, v := range expected {
+		parsed, err := ParseSemVer(v)
+		require.NoError(t, err)
+		versions[len(expected)-1-i] = parsed
+	}
+
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Less(versions[j]) })
+
+	actual := make([]string, len(versions))
+	for i, v := range versions {
+		actual[i] = v.String()
+	}
+	assert.Equal(t, expected, actual)
+}